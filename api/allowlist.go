@@ -0,0 +1,44 @@
+package api
+
+import (
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+// CoinAllowlist holds the coin handles served by the API, an empty allowlist serves all coins
+type CoinAllowlist map[string]struct{}
+
+func NewCoinAllowlist(handles []string) CoinAllowlist {
+	allowlist := make(CoinAllowlist, len(handles))
+	for _, handle := range handles {
+		allowlist[handle] = struct{}{}
+	}
+	return allowlist
+}
+
+func (l CoinAllowlist) Allows(handle string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	_, ok := l[handle]
+	return ok
+}
+
+func (l CoinAllowlist) FilterStakeAPIs(apis map[string]blockatlas.StakeAPI) map[string]blockatlas.StakeAPI {
+	result := make(map[string]blockatlas.StakeAPI, len(apis))
+	for handle, api := range apis {
+		if l.Allows(handle) {
+			result[handle] = api
+		}
+	}
+	return result
+}
+
+func (l CoinAllowlist) FilterCollectionsAPIs(apis blockatlas.CollectionsAPIs) blockatlas.CollectionsAPIs {
+	result := make(blockatlas.CollectionsAPIs, len(apis))
+	for id, api := range apis {
+		if l.Allows(api.Coin().Handle) {
+			result[id] = api
+		}
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+type collectionsAPIMock struct {
+	blockatlas.CollectionsAPI
+	coin coin.Coin
+}
+
+func (m collectionsAPIMock) Coin() coin.Coin {
+	return m.coin
+}
+
+func TestCoinAllowlist_Allows(t *testing.T) {
+	assert.True(t, NewCoinAllowlist(nil).Allows("bitcoin"))
+
+	allowlist := NewCoinAllowlist([]string{"bitcoin", "ethereum"})
+	assert.True(t, allowlist.Allows("bitcoin"))
+	assert.True(t, allowlist.Allows("ethereum"))
+	assert.False(t, allowlist.Allows("tezos"))
+}
+
+func TestCoinAllowlist_Filter(t *testing.T) {
+	allowlist := NewCoinAllowlist([]string{"ethereum", "tezos"})
+
+	stakeAPIs := allowlist.FilterStakeAPIs(map[string]blockatlas.StakeAPI{"tezos": nil, "cosmos": nil})
+	assert.Len(t, stakeAPIs, 1)
+	assert.Contains(t, stakeAPIs, "tezos")
+
+	collectionsAPIs := allowlist.FilterCollectionsAPIs(blockatlas.CollectionsAPIs{
+		coin.ETHEREUM:   collectionsAPIMock{coin: coin.Ethereum()},
+		coin.SMARTCHAIN: collectionsAPIMock{coin: coin.Smartchain()},
+	})
+	assert.Len(t, collectionsAPIs, 1)
+	assert.Contains(t, collectionsAPIs, uint(coin.ETHEREUM))
+}
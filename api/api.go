@@ -1,33 +1,148 @@
 package api
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
+	"github.com/trustwallet/blockatlas/api/endpoint"
 	"github.com/trustwallet/blockatlas/config"
+	"github.com/trustwallet/blockatlas/db"
 	_ "github.com/trustwallet/blockatlas/docs"
+	"github.com/trustwallet/blockatlas/internal"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/blockatlas/platform"
+	"github.com/trustwallet/blockatlas/services/assets"
 	"github.com/trustwallet/blockatlas/services/tokenindexer"
 )
 
-func SetupPlatformAPI(router gin.IRouter) {
+func SetupPlatformAPI(router gin.IRouter, database *db.Instance) {
+	opts := endpoint.TxOptions{
+		TrustedTokens: platform.TrustedTokens,
+		MaxPages:      config.Default.API.MaxProviderPages,
+		Assets:        assets.Registry{},
+		UpstreamKey:   config.Default.API.UpstreamKey,
+		SpamTokens:    platform.SpamTokens,
+
+		SharedAddresses: blockatlas.NewSharedAddresses(config.Default.API.SharedAddresses),
+		BlockedSenders:  blockatlas.NewSenderBlocklist(config.Default.API.BlockedSenders),
+		Backfill: endpoint.BackfillLimits{
+			Key:      config.Default.API.Backfill.Key,
+			MaxPages: config.Default.API.Backfill.MaxPages,
+		},
+		FullHistory: endpoint.FullHistoryLimits{
+			MaxPages: config.Default.API.FullHistory.MaxPages,
+			MaxTxs:   config.Default.API.FullHistory.MaxTransactions,
+			Deadline: config.Default.API.FullHistory.Deadline,
+		},
+	}
+	// without Postgres the memory cache of the database is replaced by a bounded one
+	opts.ContractCache = db.NewLRUCache(config.Default.API.MemoryCache.Size)
+	var balanceStore endpoint.BalanceStore
+	if database != nil {
+		opts.LabelStore = database
+		opts.ContractCache = database
+		balanceStore = database
+	}
+	if dir := config.Default.API.Recording.Dir; dir != "" {
+		log.WithField("dir", dir).Warn("Recording the provider responses")
+		opts.Recorder = platform.RecordResponses(dir)
+	}
+	if enrichments := config.Default.API.Enrichments; len(enrichments) > 0 {
+		pipeline, err := blockatlas.NewEnrichmentPipeline(enrichments, platform.SpamTokens)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Enrichments = pipeline
+	}
+	var prewarmer *endpoint.Prewarmer
+	if prewarm := config.Default.API.Prewarm; prewarm.MaxConcurrent > 0 {
+		prewarmer = endpoint.NewPrewarmer(prewarm.MaxConcurrent, prewarm.Interval)
+	}
+	allowlist := NewCoinAllowlist(config.Default.API.Coins)
+	stakeAPIs := allowlist.FilterStakeAPIs(platform.StakeAPIs)
+	collectionsAPIs := allowlist.FilterCollectionsAPIs(platform.CollectionsAPIs)
+	txAPIs := make(map[uint]blockatlas.TxAPI)
+	health := make(map[string]endpoint.HealthSource)
 	for _, api := range platform.Platforms {
-		RegisterTransactionsAPI(router, api)
-		RegisterTokensAPI(router, api)
+		if !allowlist.Allows(api.Coin().Handle) {
+			continue
+		}
+		if txAPI, ok := platform.WithFailover(api).(blockatlas.TxAPI); ok {
+			txAPIs[api.Coin().ID] = txAPI
+		}
+		var window *blockatlas.HealthWindow
+		if failover, ok := platform.FailoverPlatforms[api.Coin().Handle].(endpoint.HealthSource); ok {
+			health[api.Coin().Handle] = failover
+		} else if _, ok := api.(blockatlas.TxAPI); ok {
+			window = &blockatlas.HealthWindow{}
+			health[api.Coin().Handle] = singleProviderHealth{window: window}
+		}
+		RegisterTransactionsAPI(router, api, opts, window)
+		RegisterPrewarmAPI(router, api, prewarmer)
+		RegisterLabelsAPI(router, api, opts.LabelStore, config.Default.API.LabelsKey)
+		RegisterBalancesAPI(router, api, balanceStore)
+		RegisterDepositsAPI(router, api, opts)
+		RegisterTokensAPI(router, api, opts)
 		RegisterStakeAPI(router, api)
 		RegisterBlockAPI(router, api)
 	}
-	for _, api := range platform.CollectionsAPIs {
+	for _, api := range collectionsAPIs {
 		RegisterCollectionsAPI(router, api)
 	}
 
-	RegisterBatchAPI(router)
-	RegisterBasicAPI(router)
+	RegisterBatchAPI(router, stakeAPIs, collectionsAPIs, txAPIs)
+	RegisterHealthAPI(router, health)
+	var defaultCoin blockatlas.Platform
+	if handle := config.Default.API.DefaultCoin; allowlist.Allows(handle) {
+		defaultCoin = platform.Platforms[handle]
+	}
+	RegisterBasicAPI(router, defaultCoin)
 }
 
-func SetupTokensIndexAPI(router gin.IRouter, instance tokenindexer.Instance) {
-	RegisterTokensIndexAPI(router, instance)
+// SetupAdminAPI registers the operator endpoints, disabled without configured operators
+func SetupAdminAPI(router gin.IRouter) {
+	operators := config.Default.API.Admin.Operators
+	if len(operators) == 0 {
+		return
+	}
+	purger := internal.QueuePurger{
+		URL:    config.Default.Observer.Rabbitmq.URL,
+		Prefix: config.Default.Observer.Rabbitmq.Prefix,
+	}
+	admin := router.Group("/admin", AdminAuthMiddleware(operators))
+	admin.POST("/queues/:queue/purge", func(c *gin.Context) {
+		endpoint.PurgeQueue(c, purger)
+	})
+}
+
+// SetupBatchAPI registers the batch endpoint, running its sub-requests against the routes of the engine
+func SetupBatchAPI(engine *gin.Engine) {
+	engine.POST(endpoint.BatchPath, func(c *gin.Context) {
+		endpoint.ServeBatch(c, engine)
+	})
+}
+
+// SetupTokensIndexAPI registers the tokens index endpoints. Their responses hint whether the index lags the chain
+// when the rawTokens queue feeding it is backed up, inspected until the context is done
+func SetupTokensIndexAPI(ctx context.Context, router gin.IRouter, instance tokenindexer.Instance) {
+	cfg := config.Default.API.DataStaleness
+	var backlog endpoint.QueueBacklog
+	if cfg.Threshold > 0 && cfg.Interval > 0 {
+		queueBacklog := &internal.QueueBacklog{
+			Inspector: internal.QueueInspector{
+				URL:    config.Default.Observer.Rabbitmq.URL,
+				Prefix: config.Default.Observer.Rabbitmq.Prefix,
+			},
+			Queue: string(internal.RawTokens),
+		}
+		go queueBacklog.Run(ctx, cfg.Interval)
+		backlog = queueBacklog
+	}
+	RegisterTokensIndexAPI(router, instance, DataStalenessMiddleware(backlog, cfg.Threshold))
 }
 
 func SetupSwaggerAPI(router gin.IRouter) {
@@ -0,0 +1,41 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/internal"
+)
+
+const (
+	AdminKeyHeader = "X-Admin-Key"
+	// OperatorKey holds the name of the operator authenticated by the admin middleware
+	OperatorKey = "operator"
+)
+
+// QueuePurger drops the ready messages of a queue, see internal.QueuePurger
+type QueuePurger interface {
+	PurgeQueue(name string) (int, error)
+}
+
+// PurgeQueue drains a backed-up queue during incident recovery. It is destructive,
+// every purge is logged with the operator identity.
+func PurgeQueue(c *gin.Context, purger QueuePurger) {
+	queue := c.Param("queue")
+	entry := logger(c).WithFields(log.Fields{OperatorKey: c.GetString(OperatorKey), "queue": queue})
+	purged, err := purger.PurgeQueue(queue)
+	if errors.Is(err, internal.ErrQueueNotFound) {
+		entry.Warn("Purge of an unknown queue")
+		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+	if err != nil {
+		entry.WithError(err).Error("Failed to purge queue")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	entry.WithField("purged", purged).Warn("Purged queue")
+	c.JSON(http.StatusOK, map[string]interface{}{"queue": queue, "purged": purged})
+}
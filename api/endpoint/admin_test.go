@@ -0,0 +1,50 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/internal"
+)
+
+type queuePurgerMock map[string]int
+
+func (m queuePurgerMock) PurgeQueue(name string) (int, error) {
+	if name == "broken" {
+		return 0, errors.New("channel closed")
+	}
+	purged, ok := m[name]
+	if !ok {
+		return 0, internal.ErrQueueNotFound
+	}
+	return purged, nil
+}
+
+func TestPurgeQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/queues/:queue/purge", func(c *gin.Context) {
+		c.Set(OperatorKey, "alice")
+		PurgeQueue(c, queuePurgerMock{"rawTransactions": 42})
+	})
+
+	tests := []struct {
+		queue    string
+		wantCode int
+		wantBody string
+	}{
+		{"rawTransactions", http.StatusOK, `{"purged":42,"queue":"rawTransactions"}`},
+		{"unknown", http.StatusNotFound, `{"error":{"message":"queue not found"}}`},
+		{"broken", http.StatusInternalServerError, `{"error":{"message":"channel closed"}}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/queues/"+tt.queue+"/purge", nil))
+		assert.Equal(t, tt.wantCode, w.Code, tt.queue)
+		assert.JSONEq(t, tt.wantBody, w.Body.String(), tt.queue)
+	}
+}
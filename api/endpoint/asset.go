@@ -0,0 +1,31 @@
+package endpoint
+
+import (
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+// AssetRegistry resolves the logos of coins and tokens
+type AssetRegistry interface {
+	GetCoinLogo(c coin.Coin) string
+	GetTokenLogos(c coin.Coin) (blockatlas.TokenLogos, error)
+}
+
+// setLogos fills the token logos of the transactions and returns the coin logo.
+// Token logos are skipped when the registry is unavailable.
+func setLogos(c *gin.Context, registry AssetRegistry, txCoin coin.Coin, txs blockatlas.Txs) string {
+	if registry == nil {
+		return ""
+	}
+	if txs.HasTokenTransfers() {
+		logos, err := registry.GetTokenLogos(txCoin)
+		if err != nil {
+			logger(c).WithFields(log.Fields{"coin": txCoin.Handle, "error": err}).Warn("Failed to get token logos")
+		} else {
+			txs.SetTokenLogos(logos)
+		}
+	}
+	return registry.GetCoinLogo(txCoin)
+}
@@ -0,0 +1,53 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type assetRegistryMock struct {
+	logos blockatlas.TokenLogos
+	err   error
+	calls int
+}
+
+func (m *assetRegistryMock) GetCoinLogo(c coin.Coin) string {
+	return "https://assets/" + c.Handle + ".png"
+}
+
+func (m *assetRegistryMock) GetTokenLogos(c coin.Coin) (blockatlas.TokenLogos, error) {
+	m.calls++
+	return m.logos, m.err
+}
+
+func TestSetLogos(t *testing.T) {
+	transfer := types.Tx{ID: "1", Meta: types.Transfer{Value: "1"}}
+	token := types.Tx{ID: "2", Meta: types.TokenTransfer{TokenID: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Value: "1"}}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	registry := &assetRegistryMock{logos: blockatlas.TokenLogos{"0xdac17f958d2ee523a2206206994597c13d831ec7": "https://assets/usdt.png"}}
+	txs := blockatlas.NewTxs(types.Txs{transfer})
+	assert.Equal(t, "https://assets/ethereum.png", setLogos(c, registry, coin.Ethereum(), txs))
+	assert.Equal(t, 0, registry.calls)
+
+	txs = blockatlas.NewTxs(types.Txs{transfer, token})
+	setLogos(c, registry, coin.Ethereum(), txs)
+	assert.Equal(t, 1, registry.calls)
+	assert.Equal(t, "https://assets/usdt.png", txs[1].TokenLogo)
+
+	registry = &assetRegistryMock{err: blockatlas.ErrSourceConn}
+	txs = blockatlas.NewTxs(types.Txs{token})
+	assert.Equal(t, "https://assets/ethereum.png", setLogos(c, registry, coin.Ethereum(), txs))
+	assert.False(t, txs[0].UnknownToken)
+
+	assert.Empty(t, setLogos(c, nil, coin.Ethereum(), txs))
+}
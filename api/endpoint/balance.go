@@ -0,0 +1,50 @@
+package endpoint
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// BalanceStore keeps the balances of the subscribed addresses, see services/balance
+type BalanceStore interface {
+	GetAddressBalances(addressID string) ([]models.AddressBalance, error)
+}
+
+// @Summary Get the cached balances of a subscribed address
+// @ID balances_v2
+// @Description Get the balances of a subscribed address by asset id, in the smallest unit of the asset.
+// @Description They are read from the provider on subscription and updated by the observed transactions, without calling the provider.
+// @Description Addresses not subscribed have no balances.
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(ethereum)
+// @Param address path string true "the address" default(0x0875BCab22dE3d02402bc38aEe4104e1239374a7)
+// @Success 200 {object} blockatlas.AddressBalances
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/balances/{address} [get]
+func GetAddressBalances(c *gin.Context, txCoin coin.Coin, store BalanceStore) {
+	address := c.Param("address")
+	if address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+	balances, err := store.GetAddressBalances(types.GetAddressID(strconv.Itoa(int(txCoin.ID)), address))
+	if err != nil {
+		logger(c).WithFields(log.Fields{"path": c.FullPath(), "error": err}).Error("Failed to get the address balances")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	result := make(blockatlas.AddressBalances)
+	for _, balance := range balances {
+		result[balance.Asset] = balance.Balance
+	}
+	c.JSON(http.StatusOK, result)
+}
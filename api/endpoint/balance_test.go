@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/golibs/coin"
+)
+
+type balanceStoreMock struct {
+	balances map[string][]models.AddressBalance
+	err      error
+}
+
+func (m balanceStoreMock) GetAddressBalances(addressID string) ([]models.AddressBalance, error) {
+	return m.balances[addressID], m.err
+}
+
+func TestGetAddressBalances(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := balanceStoreMock{balances: map[string][]models.AddressBalance{
+		"60_0xa": {
+			{Address: "60_0xa", Asset: "c60", Balance: "1000"},
+			{Address: "60_0xa", Asset: "c60_t0xt", Balance: "5"},
+		},
+	}}
+	serve := func(store BalanceStore, path string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/balances/:address", func(c *gin.Context) {
+			GetAddressBalances(c, coin.Ethereum(), store)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := serve(store, "/balances/0xa")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"c60":"1000","c60_t0xt":"5"}`, w.Body.String())
+
+	w = serve(store, "/balances/0xb")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{}`, w.Body.String())
+
+	w = serve(balanceStoreMock{err: errors.New("db down")}, "/balances/0xa")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
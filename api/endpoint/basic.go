@@ -1,9 +1,11 @@
 package endpoint
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/trustwallet/blockatlas/internal"
-	"net/http"
+	"github.com/trustwallet/golibs/coin"
 )
 
 func GetStatus(c *gin.Context) {
@@ -13,3 +15,13 @@ func GetStatus(c *gin.Context) {
 		"date":   internal.Date,
 	})
 }
+
+// GetRoot redirects browsers to the transactions of the sample address of the coin,
+// other clients get the status
+func GetRoot(c *gin.Context, sample coin.Coin) {
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		GetStatus(c)
+		return
+	}
+	c.Redirect(http.StatusFound, "/v2/"+sample.Handle+"/transactions/"+sample.SampleAddr)
+}
@@ -0,0 +1,43 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+)
+
+func TestGetRoot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		GetRoot(c, coin.Bitcoin())
+	})
+
+	tests := []struct {
+		name     string
+		accept   string
+		code     int
+		location string
+	}{
+		{"no accept", "", http.StatusOK, ""},
+		{"any", "*/*", http.StatusOK, ""},
+		{"json", "application/json", http.StatusOK, ""},
+		{"browser", "text/html,application/xhtml+xml,*/*;q=0.8", http.StatusFound, "/v2/bitcoin/transactions/" + coin.Bitcoin().SampleAddr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
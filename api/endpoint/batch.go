@@ -0,0 +1,114 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+const (
+	// BatchPath serves the batch requests, it can't be part of a batch
+	BatchPath = "/v2/batch"
+	// maxBatchRequests bounds the sub-requests of a batch
+	maxBatchRequests = 20
+	// batchTimeout bounds a batch, sub-requests still running are answered with 504
+	batchTimeout = 30 * time.Second
+)
+
+// clientHeaders identify the client of a batch to the middlewares of its sub-requests
+var clientHeaders = []string{"X-Forwarded-For", APIKeyHeader}
+
+type (
+	// BatchSubRequest is a GET request of the API, the path holds the query, e.g. /v2/bitcoin/transactions/{address}?limit=5
+	BatchSubRequest struct {
+		Path string `json:"path"`
+	}
+
+	// BatchSubResponse is the response of a sub-request, non JSON bodies are served as JSON strings
+	BatchSubResponse struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	}
+)
+
+// @Summary Batch requests
+// @ID batch_v2
+// @Description Run several GET requests of the API concurrently, responses are returned in the order of the requests
+// @Accept json
+// @Produce json
+// @Tags Batch
+// @Param requests body []endpoint.BatchSubRequest true "GET requests, at most 20"
+// @Success 200 {array} endpoint.BatchSubResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /v2/batch [post]
+func ServeBatch(c *gin.Context, handler http.Handler) {
+	var requests []BatchSubRequest
+	if err := c.BindJSON(&requests); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(requests) == 0 || len(requests) > maxBatchRequests {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("a batch has 1 to %d requests", maxBatchRequests)))
+		return
+	}
+	keys := make([]string, len(requests))
+	for i, request := range requests {
+		if !strings.HasPrefix(request.Path, "/") || strings.HasPrefix(request.Path, BatchPath) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid path of request %d", i)))
+			return
+		}
+		keys[i] = strconv.Itoa(i)
+	}
+
+	requestID := c.GetString(RequestIDKey)
+	batch := blockatlas.FanOut(keys, batchTimeout, func(key string) (interface{}, error) {
+		i, _ := strconv.Atoi(key)
+		r, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, requests[i].Path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if requestID != "" {
+			r.Header.Set(RequestIDHeader, requestID+"-"+key)
+		}
+		// sub-requests count against the concurrent requests quota of the client
+		r.RemoteAddr = c.Request.RemoteAddr
+		for _, header := range clientHeaders {
+			if value := c.GetHeader(header); value != "" {
+				r.Header.Set(header, value)
+			}
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return newBatchSubResponse(w.Code, w.Body.Bytes()), nil
+	})
+
+	responses := make([]BatchSubResponse, len(requests))
+	for i, key := range keys {
+		if result, ok := batch.Results[key]; ok {
+			responses[i] = result.(BatchSubResponse)
+			continue
+		}
+		status := http.StatusBadRequest
+		if batch.Errors[key] == blockatlas.ErrTimeout.Error() {
+			status = http.StatusGatewayTimeout
+		}
+		body, _ := json.Marshal(errorResponse(errors.New(batch.Errors[key])))
+		responses[i] = newBatchSubResponse(status, body)
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+func newBatchSubResponse(status int, body []byte) BatchSubResponse {
+	if !json.Valid(body) {
+		body, _ = json.Marshal(string(body))
+	}
+	return BatchSubResponse{Status: status, Body: body}
+}
@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v2/:coin/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"coin": c.Param("coin"), "limit": c.Query("limit")})
+	})
+	router.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.POST(BatchPath, func(c *gin.Context) {
+		ServeBatch(c, router)
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, BatchPath, strings.NewReader(body)))
+		return w
+	}
+
+	w := post(`[{"path":"/v2/bitcoin/status?limit=5"},{"path":"/v2/missing"},{"path":"/text"}]`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var responses []BatchSubResponse
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &responses))
+	assert.Len(t, responses, 3)
+	assert.Equal(t, http.StatusOK, responses[0].Status)
+	assert.JSONEq(t, `{"coin":"bitcoin","limit":"5"}`, string(responses[0].Body))
+	assert.Equal(t, http.StatusNotFound, responses[1].Status)
+	assert.Equal(t, http.StatusOK, responses[2].Status)
+	assert.JSONEq(t, `"ok"`, string(responses[2].Body))
+
+	for _, body := range []string{
+		`[]`,
+		`{"path":"/text"}`,
+		`[{"path":"text"}]`,
+		`[{"path":"/v2/batch"}]`,
+		"[" + strings.Repeat(`{"path":"/text"},`, maxBatchRequests) + `{"path":"/text"}]`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, post(body).Code, body)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 )
 
@@ -37,3 +38,34 @@ func GetBlock(c *gin.Context, blockAPI blockatlas.BlockAPI) {
 
 	c.JSON(http.StatusOK, &block)
 }
+
+// @Summary Get Latest Block
+// @ID block_latest_v2
+// @Description Get the height and hash of the latest block
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Success 200 {object} blockatlas.BlockHead
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/block/latest [get]
+func GetLatestBlock(c *gin.Context, blockAPI blockatlas.BlockAPI) {
+	head, err := getBlockHead(blockAPI)
+	if err != nil {
+		logger(c).WithFields(log.Fields{"path": c.FullPath(), "error": err}).Error("Failed to get latest block")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(blockatlas.NewSourceError(err)))
+		return
+	}
+	c.JSON(http.StatusOK, &head)
+}
+
+func getBlockHead(blockAPI blockatlas.BlockAPI) (blockatlas.BlockHead, error) {
+	if api, ok := blockAPI.(blockatlas.BlockHeadAPI); ok {
+		return api.GetCurrentBlockHeight()
+	}
+	height, err := blockAPI.CurrentBlockNumber()
+	if err != nil {
+		return blockatlas.BlockHead{}, err
+	}
+	return blockatlas.BlockHead{Height: height}, nil
+}
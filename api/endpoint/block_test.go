@@ -0,0 +1,63 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type blockAPIMock struct {
+	height int64
+}
+
+func (m blockAPIMock) Coin() coin.Coin {
+	return coin.Bitcoin()
+}
+
+func (m blockAPIMock) CurrentBlockNumber() (int64, error) {
+	return m.height, nil
+}
+
+func (m blockAPIMock) GetBlockByNumber(num int64) (*types.Block, error) {
+	return nil, blockatlas.ErrNotFound
+}
+
+type blockHeadAPIMock struct {
+	blockAPIMock
+	hash string
+}
+
+func (m blockHeadAPIMock) GetCurrentBlockHeight() (blockatlas.BlockHead, error) {
+	return blockatlas.BlockHead{Height: m.height, Hash: m.hash}, nil
+}
+
+func TestGetLatestBlock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name     string
+		api      blockatlas.BlockAPI
+		expected string
+	}{
+		{"height only", blockAPIMock{height: 675000}, `{"height":675000}`},
+		{"height and hash", blockHeadAPIMock{blockAPIMock{height: 675000}, "000000000000000000051e"}, `{"height":675000,"hash":"000000000000000000051e"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/block/latest", func(c *gin.Context) {
+				GetLatestBlock(c, tt.api)
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/block/latest", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.expected, w.Body.String())
+		})
+	}
+}
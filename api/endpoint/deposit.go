@@ -0,0 +1,85 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
+
+// @Summary Get Deposits
+// @ID deposits_v2
+// @Description Get confirmed incoming transactions to the address, oldest first. Provider pages are followed back to since_block or since, truncated tells why deposits may be missing
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Param address path string true "the deposit address" default(bc1qhn03cww757mnnlpkdvvfkaydxqygm86nvkm92h)
+// @Param since_block query int false "only deposits included after this block"
+// @Param since query int false "only deposits included after this unix timestamp"
+// @Param min_confirmations query int false "minimum confirmations, defaults to the coin confirmations"
+// @Success 200 {object} blockatlas.TxPage
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/deposits/{address} [get]
+func GetDeposits(c *gin.Context, txAPI blockatlas.TxAPI, blockAPI blockatlas.BlockAPI, trusted blockatlas.TrustedTokens, maxPages int) {
+	address := c.Param("address")
+	if address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+
+	sinceBlock, err := strconv.ParseUint(c.DefaultQuery("since_block", "0"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid since_block param")))
+		return
+	}
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid since param")))
+		return
+	}
+	minConfirmations := blockAPI.Coin().MinConfirmations
+	if minConfirmations < 1 {
+		minConfirmations = 1
+	}
+	if raw := c.Query("min_confirmations"); raw != "" {
+		minConfirmations, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || minConfirmations < 1 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid min_confirmations param")))
+			return
+		}
+	}
+
+	txAPI, _ = bindRequest(c, txAPI).(blockatlas.TxAPI)
+	txs, truncation, err := blockatlas.GetTxsByAddressSince(txAPI, address, sinceBlock, since, maxPages)
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+	source := blockatlas.NewTxSource(getProvider(c, txAPI.Coin()))
+	currentBlock, err := blockAPI.CurrentBlockNumber()
+	if err != nil {
+		abortWithTxsError(c, blockatlas.NewSourceError(err))
+		return
+	}
+
+	deposits := blockatlas.FilterCoinTxsByMemo(txAPI.Coin(), blockatlas.SanitizeMemos(blockatlas.FilterUniqueTxs(txs)), trusted)
+	deposits = blockatlas.FilterTxsByDirection(deposits, address, types.DirectionIncoming)
+	deposits = blockatlas.FilterTxsByConfirmations(deposits, currentBlock, minConfirmations)
+	deposits = blockatlas.FilterTxsSince(deposits, sinceBlock, since)
+	deposits = blockatlas.SortTxsAscending(deposits)
+
+	for i := range deposits {
+		deposits[i].Direction = types.DirectionIncoming
+	}
+
+	page := blockatlas.NewTxs(deposits)
+	page.SetBlockHashes(getTxExtras(c))
+	txPage := blockatlas.NewTxPage(page, txAPI.Coin().Decimals)
+	txPage.TxSource = source
+	txPage.Truncated = truncation
+	c.JSON(http.StatusOK, txPage)
+}
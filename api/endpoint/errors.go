@@ -1,11 +1,28 @@
 package endpoint
 
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDKey    = "request_id"
+	// APIKeyHeader identifies the client for its concurrent requests quota
+	APIKeyHeader = "X-API-Key"
+)
+
 type (
 	ErrorResponse struct {
 		Error ErrorDetails `json:"error"`
 	}
 	ErrorDetails struct {
 		Message string `json:"message"`
+		// Reason tells why the source API failed, e.g. timeout or rate_limited
+		Reason string `json:"reason,omitempty"`
 	}
 
 	ErrorCode int
@@ -16,7 +33,17 @@ func errorResponse(err error) ErrorResponse {
 	if err != nil {
 		message = err.Error()
 	}
+	var reason string
+	if errors.Is(err, blockatlas.ErrSourceConn) {
+		reason = string(blockatlas.GetSourceErrorReason(err))
+	}
 	return ErrorResponse{Error: ErrorDetails{
 		Message: message,
+		Reason:  reason,
 	}}
 }
+
+// logger returns the log entry of the request, carrying the request ID set by the request ID middleware
+func logger(c *gin.Context) *log.Entry {
+	return blockatlas.Logger(c.Request.Context())
+}
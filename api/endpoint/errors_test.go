@@ -0,0 +1,20 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/client"
+)
+
+func TestErrorResponse(t *testing.T) {
+	assert.Equal(t, ErrorDetails{Message: "invalid address"}, errorResponse(blockatlas.ErrInvalidAddr).Error)
+	assert.Equal(t, ErrorDetails{Message: "boom"}, errorResponse(errors.New("boom")).Error)
+	assert.Equal(t, ErrorDetails{Message: "connection to servers failed", Reason: "unknown"}, errorResponse(blockatlas.ErrSourceConn).Error)
+
+	err := blockatlas.NewSourceError(&client.HttpError{StatusCode: http.StatusTooManyRequests})
+	assert.Equal(t, ErrorDetails{Message: "connection to servers failed", Reason: "rate_limited"}, errorResponse(err).Error)
+}
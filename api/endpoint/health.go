@@ -0,0 +1,28 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+// HealthSource reports the recent failure rates of the providers of a coin
+type HealthSource interface {
+	Health() blockatlas.CoinHealth
+}
+
+// @Summary Get providers health
+// @ID health_providers_v2
+// @Description Get the status of the providers of every coin by coin handle: ok, degraded or down, from their recent failure rates
+// @Produce json
+// @Tags Health
+// @Success 200 {object} map[string]blockatlas.CoinHealth
+// @Router /v2/health/providers [get]
+func GetProvidersHealth(c *gin.Context, sources map[string]HealthSource) {
+	health := make(map[string]blockatlas.CoinHealth, len(sources))
+	for handle, source := range sources {
+		health[handle] = source.Health()
+	}
+	c.JSON(http.StatusOK, health)
+}
@@ -0,0 +1,339 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// txHistoryParams are the query params of GetTransactionsHistory
+type txHistoryParams struct {
+	address          string
+	token            string
+	category         blockatlas.TxCategory
+	group            blockatlas.TxGroup
+	assetType        blockatlas.AssetType
+	details          blockatlas.TxDetails
+	includeInternal  bool
+	minConfirmations int64
+	excludeZero      bool
+	minValue         *big.Int
+	maxValue         *big.Int
+	signed           bool
+	hideSpam         bool
+	excludedFrom     []string
+	requiredMemo     string
+	hasRequiredMemo  bool
+	afterHash        string
+	cursor           blockatlas.TxCursor
+	paginated        bool
+	page             int
+	perPage          int
+	limit            int
+	wait             int64
+	fullHistory      bool
+	maxPages         int
+	label            string
+	counterpartyType blockatlas.CounterpartyType
+	fields           []string
+}
+
+// parseTxHistoryParams reads the query params of a transactions history request, the request is aborted on invalid params
+func parseTxHistoryParams(c *gin.Context, opts TxOptions) (txHistoryParams, bool) {
+	var (
+		p   txHistoryParams
+		err error
+		ok  bool
+	)
+	abort := func(err error) (txHistoryParams, bool) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return txHistoryParams{}, false
+	}
+	p.address = c.Param("address")
+	if p.address == "" {
+		return abort(blockatlas.ErrInvalidAddr)
+	}
+	p.token = c.Query("token")
+	p.category = blockatlas.TxCategory(c.DefaultQuery("category", string(blockatlas.TxCategoryAll)))
+	if !p.category.IsValid() {
+		return abort(errors.New("invalid category"))
+	}
+	p.group = blockatlas.TxGroup(c.Query("group"))
+	if !p.group.IsValid() {
+		return abort(errors.New("invalid group"))
+	}
+	p.assetType = blockatlas.AssetType(c.Query("asset_type"))
+	if !p.assetType.IsValid() {
+		return abort(errors.New("invalid asset_type"))
+	}
+	p.details = blockatlas.TxDetails(c.Query("details"))
+	if !p.details.IsValid() {
+		return abort(errors.New("invalid details"))
+	}
+	p.details = getTxDetails(c, p.details)
+	if p.includeInternal, err = strconv.ParseBool(c.DefaultQuery("include_internal", "0")); err != nil {
+		return abort(errors.New("invalid include_internal"))
+	}
+	p.minConfirmations, err = strconv.ParseInt(c.DefaultQuery("min_confirmations", "0"), 10, 64)
+	if err != nil || p.minConfirmations < 0 {
+		return abort(errors.New("invalid min_confirmations param"))
+	}
+	if p.excludeZero, err = strconv.ParseBool(c.DefaultQuery("exclude_zero", "0")); err != nil {
+		return abort(errors.New("invalid exclude_zero"))
+	}
+	if p.minValue, ok = parseValueParam(c, "min_value"); !ok {
+		return txHistoryParams{}, false
+	}
+	if p.maxValue, ok = parseValueParam(c, "max_value"); !ok {
+		return txHistoryParams{}, false
+	}
+	if p.minValue != nil && p.maxValue != nil && p.minValue.Cmp(p.maxValue) > 0 {
+		return abort(errors.New("min_value is greater than max_value"))
+	}
+	if p.signed, err = strconv.ParseBool(c.DefaultQuery("signed", "0")); err != nil {
+		return abort(errors.New("invalid signed"))
+	}
+	if p.hideSpam, err = strconv.ParseBool(c.DefaultQuery("hide_spam", "0")); err != nil {
+		return abort(errors.New("invalid hide_spam"))
+	}
+	if raw := c.Query("exclude_from"); raw != "" {
+		p.excludedFrom = strings.Split(raw, ",")
+	}
+	if len(p.excludedFrom) > maxExcludedSenders {
+		return abort(fmt.Errorf("invalid exclude_from param, at most %d senders", maxExcludedSenders))
+	}
+	p.requiredMemo, p.hasRequiredMemo = c.GetQuery("required_memo")
+	p.requiredMemo = strings.TrimSpace(p.requiredMemo)
+	p.afterHash = c.Query("after_hash")
+	if p.cursor, err = blockatlas.ParseTxCursor(p.afterHash); err != nil {
+		return abort(errors.New("invalid after_hash param"))
+	}
+	_, hasPage := c.GetQuery("page")
+	_, hasPerPage := c.GetQuery("per_page")
+	p.paginated = hasPage || hasPerPage
+	p.page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || p.page < 1 || p.page > blockatlas.MaxPage {
+		return abort(fmt.Errorf("invalid page param, pages go from 1 to %d", blockatlas.MaxPage))
+	}
+	p.perPage, err = strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(types.TxPerPage)))
+	if err != nil || p.perPage < 1 || p.perPage > blockatlas.MaxPerPage {
+		return abort(fmt.Errorf("invalid per_page param, at most %d", blockatlas.MaxPerPage))
+	}
+	p.limit, err = strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || p.limit < 0 || p.limit > types.TxPerPage {
+		return abort(fmt.Errorf("invalid limit param, at most %d", types.TxPerPage))
+	}
+	if p.limit > 0 && p.paginated {
+		return abort(errors.New("limit is not supported with page or per_page"))
+	}
+	p.wait, err = strconv.ParseInt(c.DefaultQuery("wait", "0"), 10, 64)
+	if err != nil || p.wait < 0 || p.wait > maxWaitSeconds || (p.wait > 0 && p.afterHash == "") {
+		return abort(errors.New("invalid wait param"))
+	}
+	if p.fullHistory, err = strconv.ParseBool(c.DefaultQuery("full_history", "0")); err != nil {
+		return abort(errors.New("invalid full_history"))
+	}
+	if p.fullHistory && opts.FullHistory.MaxPages == 0 {
+		return abort(blockatlas.ErrNotSupported)
+	}
+	if p.fullHistory && (p.token != "" || p.paginated || p.limit > 0 || p.wait > 0) {
+		return abort(errors.New("full_history is not supported with token, page, per_page, limit or wait"))
+	}
+	bound := opts.MaxPages
+	if p.fullHistory {
+		bound = opts.FullHistory.MaxPages
+	}
+	if p.maxPages, ok = getMaxPages(c, bound, opts.Backfill); !ok {
+		return txHistoryParams{}, false
+	}
+	p.label = c.Query("label")
+	if p.label != "" && opts.LabelStore == nil {
+		return abort(blockatlas.ErrNotSupported)
+	}
+	p.counterpartyType = blockatlas.CounterpartyType(c.Query("counterparty_type"))
+	if !p.counterpartyType.IsValid() {
+		return abort(errors.New("invalid counterparty_type"))
+	}
+	p.fields = blockatlas.ParseTxFields(c.Query("fields"))
+	return p, true
+}
+
+// txHistory is the fetch and filter pipeline of a transactions history request
+type txHistory struct {
+	txHistoryParams
+	opts        TxOptions
+	txAPI       blockatlas.TxAPI
+	tokenTxAPI  blockatlas.TokenTxAPI
+	contractAPI blockatlas.ContractAPI
+	blockAPI    blockatlas.BlockAPI
+	coin        coin.Coin
+	// senders are known with the coin, before the first fetch
+	senders blockatlas.Senders
+	// internal are the internal transfers of the fetched page, merged before the filters
+	internal types.Txs
+
+	truncation blockatlas.TxTruncation
+	hashFound  bool
+	reorged    bool
+	totalPages int
+}
+
+// newTxHistory picks the platform serving the request, the request is aborted when the platform lacks a requested feature
+func newTxHistory(c *gin.Context, p txHistoryParams, txAPI blockatlas.TxAPI, tokenTxAPI blockatlas.TokenTxAPI, opts TxOptions) (*txHistory, bool) {
+	h := &txHistory{txHistoryParams: p, opts: opts, txAPI: txAPI, tokenTxAPI: tokenTxAPI, hashFound: true}
+	var ok bool
+	h.contractAPI, ok = getContractAPI(txAPI, tokenTxAPI)
+	if p.counterpartyType != blockatlas.CounterpartyTypeAll && !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+		return nil, false
+	}
+	h.blockAPI, ok = getBlockAPI(txAPI, tokenTxAPI)
+	if p.minConfirmations > 0 && !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+		return nil, false
+	}
+	switch {
+	case p.token == "" && txAPI != nil:
+		h.coin = txAPI.Coin()
+	case p.token != "" && tokenTxAPI != nil:
+		h.coin = tokenTxAPI.Coin()
+	default:
+		c.AbortWithStatusJSON(
+			http.StatusInternalServerError,
+			errorResponse(errors.New("Failed to find api for that coin")),
+		)
+		return nil, false
+	}
+	h.senders = opts.BlockedSenders.Senders(h.coin, p.excludedFrom...)
+	// the outgoing transactions of a blocked sender stay in its own history
+	delete(h.senders, strings.ToLower(p.address))
+	if p.hasRequiredMemo && !blockatlas.SupportsMemo(h.coin) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("required_memo is not supported by %s", h.coin.Name)))
+		return nil, false
+	}
+	if (p.hasRequiredMemo && p.requiredMemo == "") || (!p.hasRequiredMemo && opts.SharedAddresses.IsShared(h.coin, p.address)) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf(
+			"%s is a shared %s deposit address, the required_memo param is needed to select the deposits of a user",
+			p.address, h.coin.Name,
+		)))
+		return nil, false
+	}
+	return h, true
+}
+
+// filter drops the transactions not matching the params. Filters dropping most of a provider page
+// make the history follow the provider pagination.
+func (h *txHistory) filter(txs types.Txs) types.Txs {
+	txs = blockatlas.FilterTxsByCategory(txs, h.category)
+	txs = blockatlas.FilterTxsByAssetType(txs, h.assetType)
+	if h.excludeZero {
+		txs = blockatlas.FilterTxsZeroValue(txs)
+	}
+	txs = blockatlas.FilterTxsByValue(txs, h.minValue, h.maxValue)
+	if h.hideSpam {
+		txs = blockatlas.FilterTxsSpam(txs, h.opts.SpamTokens)
+	}
+	if h.hasRequiredMemo {
+		txs = blockatlas.FilterTxsByRequiredMemo(txs, h.requiredMemo)
+	}
+	return blockatlas.FilterTxsBySenders(txs, h.senders)
+}
+
+// lookup fetches the transactions once: the token transfers, the transactions with their internal transfers,
+// the full history, the latest transactions or the provider pages needed to fill the requested page
+func (h *txHistory) lookup(ctx context.Context) (types.Txs, error) {
+	if h.token != "" {
+		return h.tokenTxAPI.GetTokenTxsByAddress(h.address, h.token)
+	}
+	// internal transfers come with the transactions of a single provider page
+	if internalAPI, ok := h.txAPI.(blockatlas.InternalTxAPI); ok && h.includeInternal {
+		txs, internal, err := internalAPI.GetTxsWithInternalByAddress(h.address)
+		h.internal = internal
+		if h.fullHistory {
+			h.truncation = blockatlas.TxTruncatedProvider
+		}
+		return txs, err
+	}
+	if h.fullHistory {
+		ctx, cancel := context.WithTimeout(ctx, h.opts.FullHistory.Deadline)
+		defer cancel()
+		txs, reason, err := blockatlas.GetFullTxHistory(ctx, h.txAPI, h.address, h.maxPages, h.opts.FullHistory.MaxTxs)
+		h.truncation = reason
+		return txs, err
+	}
+	// filters supported by the provider are pushed down, the local filters still apply
+	upstream := blockatlas.TxFilter{AssetType: h.assetType}
+	// the latest transactions are served from a page of the requested size, older ones are needed after a cursor
+	if h.limit > 0 && h.afterHash == "" {
+		return blockatlas.GetLatestTxs(h.txAPI, h.address, upstream, h.limit, h.maxPages, h.filter)
+	}
+	return blockatlas.GetTxsByAddressPages(h.txAPI, h.address, upstream, h.maxPages, h.page*h.perPage, h.filter)
+}
+
+// fetch looks the transactions up, with wait it polls until a transaction newer than after_hash shows up
+func (h *txHistory) fetch(ctx context.Context) (types.Txs, error) {
+	if h.wait == 0 {
+		return h.lookup(ctx)
+	}
+	return blockatlas.PollTxs(ctx, func() (types.Txs, error) {
+		return h.lookup(ctx)
+	}, func(txs types.Txs) bool {
+		newer, found, reorged := blockatlas.FilterTxsAfterCursor(blockatlas.SortTxs(blockatlas.FilterUniqueTxs(txs)), h.cursor)
+		return !found || reorged || len(h.filter(newer)) > 0
+	}, time.Duration(h.wait)*time.Second, longPollInterval)
+}
+
+// selectTxs applies the filters, the cursor and the pagination to the fetched transactions, newest first.
+// Errors are source errors, except blockatlas.ErrNotSupported.
+func (h *txHistory) selectTxs(txs types.Txs, labels blockatlas.TxLabels) (types.Txs, error) {
+	// internal transfers share the ID of their transaction, they are added after the duplicates are dropped
+	txs = blockatlas.SortTxs(append(blockatlas.FilterUniqueTxs(txs), h.internal...))
+	// required memos are matched before the memos not allowed are cleared
+	txs = h.filter(blockatlas.SanitizeMemos(txs))
+	txs = blockatlas.FilterCoinTxsByMemo(h.coin, txs, h.opts.TrustedTokens)
+	if h.token != "" {
+		txs = blockatlas.FilterCoinTxsByToken(h.coin, txs, h.token, h.opts.TrustedTokens)
+	}
+	if h.counterpartyType != blockatlas.CounterpartyTypeAll {
+		var err error
+		txs, err = blockatlas.FilterTxsByCounterpartyType(txs, h.address, h.counterpartyType, blockatlas.NewContractLookup(h.contractAPI, h.opts.ContractCache))
+		if err == blockatlas.ErrNotSupported {
+			return nil, err
+		}
+		if err != nil {
+			return nil, blockatlas.NewSourceError(err)
+		}
+	}
+	if h.minConfirmations > 0 {
+		currentBlock, err := h.blockAPI.CurrentBlockNumber()
+		if err != nil {
+			return nil, blockatlas.NewSourceError(err)
+		}
+		txs = blockatlas.FilterTxsByConfirmations(txs, currentBlock, h.minConfirmations)
+	}
+	if h.label != "" {
+		txs = blockatlas.FilterTxsByLabel(txs, labels, h.label)
+	}
+	if h.afterHash != "" {
+		txs, h.hashFound, h.reorged = blockatlas.FilterTxsAfterCursor(txs, h.cursor)
+	}
+	switch {
+	case h.paginated:
+		txs, h.totalPages = blockatlas.PaginateTxs(txs, h.page, h.perPage)
+	case h.limit > 0 && len(txs) > h.limit:
+		txs = txs[0:h.limit]
+	case len(txs) > types.TxPerPage && !h.fullHistory:
+		txs = txs[0:types.TxPerPage]
+	}
+	return txs, nil
+}
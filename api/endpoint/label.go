@@ -0,0 +1,130 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+const maxLabelLength = 64
+
+// LabelsKeyHeader authorizes the label writes, see api.LabelsAuthMiddleware
+const LabelsKeyHeader = "X-Labels-Key"
+
+// TxLabelStore persists the labels users attach to transactions
+type TxLabelStore interface {
+	AddTxLabel(label models.TxLabel) error
+	DeleteTxLabel(label models.TxLabel) error
+	GetTxLabels(coin uint, address string) ([]models.TxLabel, error)
+}
+
+// @Summary Add a transaction label
+// @ID tx_label_add_v2
+// @Description Attach a label to a transaction of the address
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Param address path string true "the address" default(bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh)
+// @Param hash path string true "the transaction hash"
+// @Param label path string true "the label" default(salary)
+// @Success 200 {object} blockatlas.TxLabels
+// @Param X-Labels-Key header string true "the key of the label writes"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/labels/{address}/{hash}/{label} [post]
+func AddTxLabel(c *gin.Context, txCoin coin.Coin, store TxLabelStore) {
+	label, ok := getTxLabelParams(c, txCoin)
+	if !ok {
+		return
+	}
+	if err := store.AddTxLabel(label); err != nil {
+		abortWithLabelsError(c, err)
+		return
+	}
+	respondWithTxLabels(c, txCoin, label.Address, store)
+}
+
+// @Summary Remove a transaction label
+// @ID tx_label_delete_v2
+// @Description Remove a label from a transaction of the address
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Param address path string true "the address" default(bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh)
+// @Param hash path string true "the transaction hash"
+// @Param label path string true "the label" default(salary)
+// @Success 200 {object} blockatlas.TxLabels
+// @Param X-Labels-Key header string true "the key of the label writes"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/labels/{address}/{hash}/{label} [delete]
+func DeleteTxLabel(c *gin.Context, txCoin coin.Coin, store TxLabelStore) {
+	label, ok := getTxLabelParams(c, txCoin)
+	if !ok {
+		return
+	}
+	if err := store.DeleteTxLabel(label); err != nil {
+		abortWithLabelsError(c, err)
+		return
+	}
+	respondWithTxLabels(c, txCoin, label.Address, store)
+}
+
+func getTxLabelParams(c *gin.Context, txCoin coin.Coin) (models.TxLabel, bool) {
+	label := models.TxLabel{
+		Coin:    txCoin.ID,
+		Address: c.Param("address"),
+		Hash:    c.Param("hash"),
+		Label:   c.Param("label"),
+	}
+	if label.Address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return label, false
+	}
+	if label.Hash == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid hash")))
+		return label, false
+	}
+	if !isValidLabel(label.Label) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid label")))
+		return label, false
+	}
+	return label, true
+}
+
+func isValidLabel(label string) bool {
+	return label != "" && len(label) <= maxLabelLength && blockatlas.SanitizeMemo(label) == label
+}
+
+func getTxLabels(txCoin coin.Coin, address string, store TxLabelStore) (blockatlas.TxLabels, error) {
+	labels, err := store.GetTxLabels(txCoin.ID, address)
+	if err != nil {
+		return nil, err
+	}
+	result := make(blockatlas.TxLabels)
+	for _, label := range labels {
+		result[label.Hash] = append(result[label.Hash], label.Label)
+	}
+	return result, nil
+}
+
+func respondWithTxLabels(c *gin.Context, txCoin coin.Coin, address string, store TxLabelStore) {
+	labels, err := getTxLabels(txCoin, address, store)
+	if err != nil {
+		abortWithLabelsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, labels)
+}
+
+func abortWithLabelsError(c *gin.Context, err error) {
+	logger(c).WithFields(log.Fields{"path": c.FullPath(), "error": err}).Error("Failed to update transaction labels")
+	c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+}
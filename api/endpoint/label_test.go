@@ -0,0 +1,78 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type txLabelStoreMock struct {
+	labels []models.TxLabel
+}
+
+func (m *txLabelStoreMock) AddTxLabel(label models.TxLabel) error {
+	m.labels = append(m.labels, label)
+	return nil
+}
+
+func (m *txLabelStoreMock) DeleteTxLabel(label models.TxLabel) error {
+	result := m.labels[:0]
+	for _, l := range m.labels {
+		if l != label {
+			result = append(result, l)
+		}
+	}
+	m.labels = result
+	return nil
+}
+
+func (m *txLabelStoreMock) GetTxLabels(coin uint, address string) ([]models.TxLabel, error) {
+	return m.labels, nil
+}
+
+func TestTxLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var txs types.Txs
+	assert.Nil(t, json.Unmarshal(readFixture(t, "utxo_txs.json"), &txs))
+	api := txAPIFixture{coin: coin.Bitcoin(), txs: txs}
+	store := &txLabelStoreMock{}
+	router := gin.New()
+	router.POST("/labels/:address/:hash/:label", func(c *gin.Context) {
+		AddTxLabel(c, coin.Bitcoin(), store)
+	})
+	router.DELETE("/labels/:address/:hash/:label", func(c *gin.Context) {
+		DeleteTxLabel(c, coin.Bitcoin(), store)
+	})
+	router.GET("/transactions/:address", func(c *gin.Context) {
+		GetTransactionsHistory(c, api, api, TxOptions{LabelStore: store})
+	})
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/labels/bc1qown/newer/salary")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"newer":["salary"]}`, w.Body.String())
+
+	w = serve(http.MethodGet, "/transactions/bc1qown?label=salary")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+	assert.Contains(t, w.Body.String(), `"labels":["salary"]`)
+
+	w = serve(http.MethodDelete, "/labels/bc1qown/newer/salary")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{}`, w.Body.String())
+
+	w = serve(http.MethodPost, "/labels/bc1qown/newer/%1B%5B31m")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, store.labels)
+}
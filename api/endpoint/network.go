@@ -0,0 +1,41 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+)
+
+// GetNetworkPlatform resolves the platform serving the network requested with the network query param.
+// The request is aborted if the network is unknown or not supported by the coin, or if the address
+// belongs to the other network, see blockatlas.IsTestnetAddress for the coins it is checked for.
+func GetNetworkPlatform(c *gin.Context, mainnet blockatlas.Platform, testnets blockatlas.Platforms) (blockatlas.Platform, bool) {
+	network := c.DefaultQuery("network", NetworkMainnet)
+	var p blockatlas.Platform
+	switch network {
+	case NetworkMainnet:
+		p = mainnet
+	case NetworkTestnet:
+		testnet, ok := testnets[mainnet.Coin().Handle]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+			return nil, false
+		}
+		p = testnet
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid network")))
+		return nil, false
+	}
+	if testnet, ok := blockatlas.IsTestnetAddress(mainnet.Coin().ID, c.Param("address")); ok && testnet != (network == NetworkTestnet) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return nil, false
+	}
+	return p, true
+}
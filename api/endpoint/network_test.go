@@ -0,0 +1,54 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetNetworkPlatform(t *testing.T) {
+	mainnet := txAPIFixture{coin: coin.Bitcoin()}
+	testnet := txAPIFixture{coin: coin.Bitcoin(), txs: types.Txs{{ID: "testnet"}}}
+	testnets := blockatlas.Platforms{coin.Bitcoin().Handle: testnet}
+
+	tests := []struct {
+		name     string
+		mainnet  blockatlas.Platform
+		path     string
+		want     blockatlas.Platform
+		wantCode int
+	}{
+		{"mainnet by default", mainnet, "/bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", mainnet, http.StatusOK},
+		{"mainnet", mainnet, "/1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH?network=mainnet", mainnet, http.StatusOK},
+		{"testnet bech32", mainnet, "/tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx?network=testnet", testnet, http.StatusOK},
+		{"testnet legacy", mainnet, "/mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r?network=testnet", testnet, http.StatusOK},
+		{"testnet address on mainnet", mainnet, "/tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", nil, http.StatusBadRequest},
+		{"mainnet address on testnet", mainnet, "/1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH?network=testnet", nil, http.StatusBadRequest},
+		{"address of unknown format", mainnet, "/bc1qown?network=testnet", testnet, http.StatusOK},
+		{"testnet not supported", txAPIFixture{coin: coin.Tezos()}, "/tz1own?network=testnet", nil, http.StatusBadRequest},
+		{"invalid network", mainnet, "/bc1qown?network=regtest", nil, http.StatusBadRequest},
+	}
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got blockatlas.Platform
+			router := gin.New()
+			router.GET("/:address", func(c *gin.Context) {
+				if p, ok := GetNetworkPlatform(c, tt.mainnet, testnets); ok {
+					got = p
+					c.Status(http.StatusOK)
+				}
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
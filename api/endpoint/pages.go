@@ -0,0 +1,51 @@
+package endpoint
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+// BackfillKeyHeader authorizes max_pages above the configured bound, see BackfillLimits
+const BackfillKeyHeader = "X-Backfill-Key"
+
+// BackfillLimits let backfill jobs follow more provider pages than the configured bound, empty Key disables it
+type BackfillLimits struct {
+	Key      string
+	MaxPages int
+}
+
+var errBackfillForbidden = errors.New("max_pages above the configured bound requires a valid X-Backfill-Key header")
+
+// getMaxPages returns the provider pages a request follows at most: bound by default, lowered by the max_pages
+// param or raised by backfill jobs up to the backfill limit. The request is aborted on invalid params.
+func getMaxPages(c *gin.Context, bound int, backfill BackfillLimits) (int, bool) {
+	raw, ok := c.GetQuery("max_pages")
+	if !ok {
+		return bound, true
+	}
+	maxPages, err := strconv.Atoi(raw)
+	if err != nil || maxPages < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid max_pages param")))
+		return 0, false
+	}
+	if maxPages <= bound {
+		return maxPages, true
+	}
+	requestKey := c.GetHeader(BackfillKeyHeader)
+	if backfill.Key == "" || subtle.ConstantTimeCompare([]byte(backfill.Key), []byte(requestKey)) != 1 {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errBackfillForbidden))
+		return 0, false
+	}
+	if maxPages > backfill.MaxPages {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid max_pages param, at most %d", backfill.MaxPages)))
+		return 0, false
+	}
+	logger(c).WithFields(log.Fields{"max_pages": maxPages}).Info("Following provider pages for a backfill")
+	return maxPages, true
+}
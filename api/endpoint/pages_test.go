@@ -0,0 +1,53 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMaxPages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	backfill := BackfillLimits{Key: "secret", MaxPages: 20}
+
+	tests := []struct {
+		name     string
+		query    string
+		backfill BackfillLimits
+		auth     string
+		code     int
+		want     int
+	}{
+		{"default", "", backfill, "", http.StatusOK, 3},
+		{"lower", "max_pages=1", backfill, "", http.StatusOK, 1},
+		{"bound", "max_pages=3", BackfillLimits{}, "", http.StatusOK, 3},
+		{"backfill", "max_pages=20", backfill, "secret", http.StatusOK, 20},
+		{"above backfill", "max_pages=21", backfill, "secret", http.StatusBadRequest, 0},
+		{"no key", "max_pages=4", backfill, "", http.StatusForbidden, 0},
+		{"wrong key", "max_pages=4", backfill, "guess", http.StatusForbidden, 0},
+		{"disabled", "max_pages=4", BackfillLimits{}, "", http.StatusForbidden, 0},
+		{"zero", "max_pages=0", backfill, "", http.StatusBadRequest, 0},
+		{"invalid", "max_pages=all", backfill, "", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := 0
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if maxPages, ok := getMaxPages(c, 3, tt.backfill); ok {
+					got = maxPages
+					c.Status(http.StatusOK)
+				}
+			})
+			r := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			r.Header.Set(BackfillKeyHeader, tt.auth)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
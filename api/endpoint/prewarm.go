@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+var errTooManyPrewarms = errors.New("too many prewarm requests")
+
+// Prewarmer fetches the transactions of addresses in the background, filling the caches of the platforms
+// caching provider responses ahead of the first request. At most maxConcurrent fetches run at once
+// and an address is fetched once per interval.
+type Prewarmer struct {
+	slots    chan struct{}
+	interval time.Duration
+
+	mu     sync.Mutex
+	recent map[string]time.Time
+}
+
+func NewPrewarmer(maxConcurrent int, interval time.Duration) *Prewarmer {
+	return &Prewarmer{
+		slots:    make(chan struct{}, maxConcurrent),
+		interval: interval,
+		recent:   make(map[string]time.Time),
+	}
+}
+
+// Prewarm starts the background fetch, it returns false when all the fetch slots are busy
+func (p *Prewarmer) Prewarm(key string, fetch func() error) bool {
+	if !p.claim(key) {
+		return true
+	}
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		p.release(key)
+		return false
+	}
+	go func() {
+		defer func() { <-p.slots }()
+		if err := fetch(); err != nil {
+			p.release(key)
+		}
+	}()
+	return true
+}
+
+// claim reports whether the key was not fetched within the interval and marks it fetched
+func (p *Prewarmer) claim(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for k, at := range p.recent {
+		if now.Sub(at) >= p.interval {
+			delete(p.recent, k)
+		}
+	}
+	if _, ok := p.recent[key]; ok {
+		return false
+	}
+	p.recent[key] = now
+	return true
+}
+
+func (p *Prewarmer) release(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.recent, key)
+}
+
+// @Summary Prewarm the transactions of an address
+// @ID prewarm_v2
+// @Description Fetch the transactions of the address in the background so the first request is fast
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(tron)
+// @Param address path string true "the query address" default(TLbbuMiPA7ngfw3pgFiDuMNPZE5hcn9S4w)
+// @Success 202
+// @Failure 429 {object} ErrorResponse
+// @Router /v2/{coin}/prewarm/{address} [post]
+func PrewarmAddress(c *gin.Context, txAPI blockatlas.TxAPI, prewarmer *Prewarmer) {
+	address := c.Param("address")
+	if address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+	key := txAPI.Coin().Handle + ":" + strings.ToLower(address)
+	entry := logger(c).WithField("key", key)
+	ok := prewarmer.Prewarm(key, func() error {
+		_, err := txAPI.GetTxsByAddress(address)
+		if err != nil {
+			entry.WithError(err).Warn("Failed to prewarm transactions")
+		}
+		return err
+	})
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(errTooManyPrewarms))
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
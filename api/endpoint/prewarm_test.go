@@ -0,0 +1,42 @@
+package endpoint
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrewarmer_Prewarm(t *testing.T) {
+	prewarmer := NewPrewarmer(1, time.Hour)
+	var calls int32
+	unblock := make(chan struct{})
+	fetch := func() error {
+		atomic.AddInt32(&calls, 1)
+		<-unblock
+		return nil
+	}
+
+	assert.True(t, prewarmer.Prewarm("bitcoin:a", fetch))
+	assert.True(t, prewarmer.Prewarm("bitcoin:a", fetch), "recently prewarmed addresses are accepted without a fetch")
+	assert.False(t, prewarmer.Prewarm("bitcoin:b", fetch), "no free slot")
+	close(unblock)
+	assert.Eventually(t, func() bool {
+		return prewarmer.Prewarm("bitcoin:b", fetch)
+	}, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 2
+	}, time.Second, time.Millisecond)
+
+	// failed fetches can be retried
+	failing := func() error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("timeout")
+	}
+	assert.Eventually(t, func() bool {
+		prewarmer.Prewarm("bitcoin:c", failing)
+		return atomic.LoadInt32(&calls) >= 4
+	}, time.Second, time.Millisecond)
+}
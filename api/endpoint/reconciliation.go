@@ -0,0 +1,60 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+// TxDiffRequest holds the transactions of an address already known by the client
+type TxDiffRequest struct {
+	AddressBatchRequest
+	KnownHashes []string `json:"known_hashes"`
+}
+
+// @Summary Get Unknown Transactions
+// @ID tx_diff_v2
+// @Description Get the transactions of the address which are not in the known hashes, for ledger reconciliation
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param request body endpoint.TxDiffRequest true "Coin, address and known transaction hashes"
+// @Success 200 {object} blockatlas.TxPage
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/transactions/diff [post]
+func GetUnknownTransactions(c *gin.Context, apis map[uint]blockatlas.TxAPI, trusted blockatlas.TrustedTokens) {
+	var req TxDiffRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+	api, ok := apis[req.Coin]
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+		return
+	}
+
+	api, _ = bindRequest(c, api).(blockatlas.TxAPI)
+	txs, err := api.GetTxsByAddress(req.Address)
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+	source := blockatlas.NewTxSource(getProvider(c, api.Coin()))
+
+	filteredTxs := blockatlas.SortTxs(blockatlas.FilterUniqueTxs(txs))
+	filteredTxs = blockatlas.FilterCoinTxsByMemo(api.Coin(), blockatlas.SanitizeMemos(filteredTxs), trusted)
+	filteredTxs = blockatlas.FilterTxsNotIn(filteredTxs, req.KnownHashes)
+	filteredTxs = blockatlas.SetDirections(filteredTxs, req.Address)
+
+	page := blockatlas.NewTxs(filteredTxs)
+	page.SetBlockHashes(getTxExtras(c))
+	txPage := blockatlas.NewTxPage(page, api.Coin().Decimals)
+	txPage.TxSource = source
+	c.JSON(http.StatusOK, txPage)
+}
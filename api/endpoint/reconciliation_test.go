@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetUnknownTransactions(t *testing.T) {
+	var txs types.Txs
+	assert.Nil(t, json.Unmarshal(readFixture(t, "utxo_txs.json"), &txs))
+	apis := map[uint]blockatlas.TxAPI{coin.BITCOIN: txAPIFixture{coin: coin.Bitcoin(), txs: txs}}
+	known := txs[0].ID
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/diff", func(c *gin.Context) {
+		GetUnknownTransactions(c, apis, nil)
+	})
+
+	tests := []struct {
+		name  string
+		body  string
+		code  int
+		total int
+	}{
+		{"nothing known", `{"coin":0,"address":"bc1qown"}`, http.StatusOK, 2},
+		{"one known", `{"coin":0,"address":"bc1qown","known_hashes":["` + strings.ToUpper(known) + `"]}`, http.StatusOK, 1},
+		{"unsupported coin", `{"coin":60,"address":"0x"}`, http.StatusBadRequest, 0},
+		{"missing address", `{"coin":0}`, http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/diff", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+			var page blockatlas.TxPage
+			assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &page))
+			assert.Equal(t, tt.total, page.Total)
+			assert.Equal(t, "bitcoin", page.Provider)
+			assert.NotZero(t, page.FetchedAt)
+			if tt.total == 1 {
+				assert.NotEqual(t, known, page.Docs[0].ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package endpoint
+
+// DataStalenessHeader hints whether the data served from the database may lag the chain
+const DataStalenessHeader = "X-Data-Staleness"
+
+// DataStaleness tells whether the queue feeding the database is backed up
+type DataStaleness string
+
+const (
+	DataCurrent          DataStaleness = "current"
+	DataLagging          DataStaleness = "lagging"
+	DataStalenessUnknown DataStaleness = "unknown"
+)
+
+// QueueBacklog reports the depth of the queue feeding the database, see internal.QueueBacklog
+type QueueBacklog interface {
+	Depth() (int, bool)
+}
+
+// GetDataStaleness returns lagging from threshold messages waiting in the queue, unknown when the depth isn't
+func GetDataStaleness(backlog QueueBacklog, threshold int) DataStaleness {
+	depth, ok := backlog.Depth()
+	switch {
+	case !ok:
+		return DataStalenessUnknown
+	case depth >= threshold:
+		return DataLagging
+	default:
+		return DataCurrent
+	}
+}
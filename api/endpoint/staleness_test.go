@@ -0,0 +1,22 @@
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type backlogFixture struct {
+	depth int
+	known bool
+}
+
+func (b backlogFixture) Depth() (int, bool) {
+	return b.depth, b.known
+}
+
+func TestGetDataStaleness(t *testing.T) {
+	assert.Equal(t, DataCurrent, GetDataStaleness(backlogFixture{depth: 999, known: true}, 1000))
+	assert.Equal(t, DataLagging, GetDataStaleness(backlogFixture{depth: 1000, known: true}, 1000))
+	assert.Equal(t, DataStalenessUnknown, GetDataStaleness(backlogFixture{}, 1000))
+}
@@ -2,8 +2,10 @@ package endpoint
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/trustwallet/blockatlas/pkg/blockatlas"
@@ -52,6 +54,76 @@ func GetTokensIdsByAddress(c *gin.Context, tokenAPI blockatlas.TokensAPI) {
 	c.JSON(http.StatusOK, result)
 }
 
+// @Summary Get Token Details
+// @ID token_details_v2
+// @Description Get the token balance of the address along with its latest token transfers
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(ethereum)
+// @Param address path string true "the query address" default(0x5574Cd97432cEd0D7Caf58ac3c4fEDB2061C98fB)
+// @Param token path string true "the token id" default(0xdAC17F958D2ee523a2206206994597C13D831ec7)
+// @Success 200 {object} blockatlas.TokenDetails
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/tokens/{address}/token/{token} [get]
+func GetTokenDetails(c *gin.Context, balanceAPI blockatlas.TokenBalanceAPI, tokenTxAPI blockatlas.TokenTxAPI, opts TxOptions) {
+	address := c.Param("address")
+	token := c.Param("token")
+	if address == "" || token == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+
+	balanceAPI, _ = bindRequest(c, balanceAPI).(blockatlas.TokenBalanceAPI)
+	tokenTxAPI, _ = bindRequest(c, tokenTxAPI).(blockatlas.TokenTxAPI)
+	var (
+		wg                 sync.WaitGroup
+		balance            types.Amount
+		txs                types.Txs
+		balanceErr, txsErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		balance, balanceErr = balanceAPI.GetTokenBalance(address, token)
+	}()
+	go func() {
+		defer wg.Done()
+		txs, txsErr = tokenTxAPI.GetTokenTxsByAddress(address, token)
+	}()
+	wg.Wait()
+
+	if txsErr != nil {
+		abortWithTxsError(c, txsErr)
+		return
+	}
+	if balanceErr != nil {
+		abortWithTxsError(c, balanceErr)
+		return
+	}
+	source := blockatlas.NewTxSource(getProvider(c, tokenTxAPI.Coin()))
+
+	filteredTxs := blockatlas.SortTxs(blockatlas.FilterUniqueTxs(txs))
+	filteredTxs = blockatlas.FilterCoinTxsByMemo(tokenTxAPI.Coin(), blockatlas.SanitizeMemos(filteredTxs), opts.TrustedTokens)
+	filteredTxs = blockatlas.FilterCoinTxsByToken(tokenTxAPI.Coin(), filteredTxs, token, opts.TrustedTokens)
+	if len(filteredTxs) > types.TxPerPage {
+		filteredTxs = filteredTxs[0:types.TxPerPage]
+	}
+	filteredTxs = blockatlas.SetDirections(filteredTxs, address)
+
+	page := blockatlas.NewTxs(filteredTxs)
+	page.SetBlockHashes(getTxExtras(c))
+	page.SetSpamFlags(opts.SpamTokens)
+	page.SetAssetTypes()
+	txPage := blockatlas.NewTxPage(page, tokenTxAPI.Coin().Decimals)
+	txPage.TxSource = source
+	txPage.Logo = setLogos(c, opts.Assets, tokenTxAPI.Coin(), page)
+	c.JSON(http.StatusOK, blockatlas.TokenDetails{
+		Balance:      balance,
+		Transactions: txPage,
+	})
+}
+
 func GetTokensByAddressV3(c *gin.Context, instance tokenindexer.Instance) {
 	var query tokenindexer.GetTokensByAddressRequest
 	if err := c.Bind(&query); err != nil {
@@ -93,3 +165,36 @@ func GetNewTokens(c *gin.Context, instance tokenindexer.Instance) {
 	}
 	c.JSON(http.StatusOK, resp)
 }
+
+// TruncatedHeader tells why the counts miss the oldest transactions of the address, see blockatlas.TxTruncation
+const TruncatedHeader = "X-Truncated"
+
+// @Summary Get Token Transfer Counts
+// @ID token_transfers_v2
+// @Description Get the number of transfers and the date of the latest one by token, over the provider pages read within max_provider_pages. X-Truncated tells why older transfers are not counted: cap, deadline or provider
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(ethereum)
+// @Param address path string true "the query address" default(0x5574Cd97432cEd0D7Caf58ac3c4fEDB2061C98fB)
+// @Success 200 {object} map[string]blockatlas.TokenTransferCount
+// @Header 200 {string} X-Truncated "why older transfers are not counted"
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/tokens/{address}/transfers [get]
+func GetTokenTransferCounts(c *gin.Context, txAPI blockatlas.TxAPI, maxPages int) {
+	address := c.Param("address")
+	if address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+	// the pages bound the lookup, the transactions of the pages read are all counted
+	txs, truncation, err := blockatlas.GetFullTxHistory(c.Request.Context(), txAPI, address, maxPages, math.MaxInt32)
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+	if truncation != "" {
+		c.Header(TruncatedHeader, string(truncation))
+	}
+	c.JSON(http.StatusOK, blockatlas.CountTokenTransfers(txs))
+}
@@ -0,0 +1,177 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// txPagesFixture serves pages of a token transfer each, dated by page
+type txPagesFixture struct {
+	txAPIFixture
+	pages int
+}
+
+func (f txPagesFixture) GetTxsByAddressPage(address string, page int) (types.Txs, bool, error) {
+	tx := types.Tx{ID: strconv.Itoa(page), Date: int64(1000 - page), Status: types.StatusCompleted,
+		Meta: types.TokenTransfer{TokenID: "0xusdt", Value: "1"}}
+	return types.Txs{tx}, page < f.pages, nil
+}
+
+func TestGetTokenTransferCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	get := func(api blockatlas.TxAPI, maxPages int) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/:address", func(c *gin.Context) {
+			GetTokenTransferCounts(c, api, maxPages)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/0x5574Cd97432cEd0D7Caf58ac3c4fEDB2061C98fB", nil))
+		return w
+	}
+	counts := func(w *httptest.ResponseRecorder) map[string]blockatlas.TokenTransferCount {
+		var result map[string]blockatlas.TokenTransferCount
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	w := get(txPagesFixture{pages: 2}, 3)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(TruncatedHeader))
+	assert.Equal(t, map[string]blockatlas.TokenTransferCount{"0xusdt": {Count: 2, LastSeen: 999}}, counts(w))
+
+	w = get(txPagesFixture{pages: 5}, 3)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(blockatlas.TxTruncatedCap), w.Header().Get(TruncatedHeader))
+	assert.Equal(t, 3, counts(w)["0xusdt"].Count)
+
+	w = get(txAPIFixture{txs: types.Txs{{ID: "1", Meta: types.TokenTransfer{TokenID: "0xdai", Value: "1"}}}}, 3)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(blockatlas.TxTruncatedProvider), w.Header().Get(TruncatedHeader))
+	assert.Equal(t, 1, counts(w)["0xdai"].Count)
+}
+
+// tokenDetailsFixture serves the token balance and transfers, each lookup waits for the other one to start
+type tokenDetailsFixture struct {
+	txAPIFixture
+	balance    types.Amount
+	balanceErr error
+	txsErr     error
+	started    *sync.WaitGroup
+}
+
+func (f tokenDetailsFixture) GetTokenBalance(address, token string) (types.Amount, error) {
+	if err := f.await(); err != nil {
+		return "", err
+	}
+	return f.balance, f.balanceErr
+}
+
+func (f tokenDetailsFixture) GetTokenTxsByAddress(address, token string) (types.Txs, error) {
+	if err := f.await(); err != nil {
+		return nil, err
+	}
+	return f.txs, f.txsErr
+}
+
+func (f tokenDetailsFixture) await() error {
+	f.started.Done()
+	done := make(chan struct{})
+	go func() {
+		f.started.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(time.Second):
+		return errors.New("lookups not run concurrently")
+	}
+}
+
+func TestGetTokenDetails(t *testing.T) {
+	transfer := func(id, token, from, to string, date int64) types.Tx {
+		return types.Tx{ID: id, Coin: coin.ETHEREUM, From: from, To: to, Date: date, Block: uint64(date), Status: types.StatusCompleted,
+			Meta: types.TokenTransfer{TokenID: token, From: from, To: to, Value: "1", Decimals: 6, Symbol: "USDT"}}
+	}
+	txs := types.Txs{
+		transfer("0x1", "0xusdt", "0xother", "0xown", 100),
+		transfer("0x2", "0xusdt", "0xown", "0xother", 200),
+		transfer("0x2", "0xusdt", "0xown", "0xother", 200),
+		transfer("0x3", "0xdai", "0xother", "0xown", 300),
+	}
+	fixture := func(balanceErr, txsErr error) tokenDetailsFixture {
+		started := &sync.WaitGroup{}
+		started.Add(2)
+		return tokenDetailsFixture{
+			txAPIFixture: txAPIFixture{coin: coin.Ethereum(), txs: txs},
+			balance:      "5000000",
+			balanceErr:   balanceErr,
+			txsErr:       txsErr,
+			started:      started,
+		}
+	}
+	gin.SetMode(gin.TestMode)
+	get := func(api tokenDetailsFixture) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/:address/token/:token", func(c *gin.Context) {
+			GetTokenDetails(c, api, api, TxOptions{})
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/0xown/token/0xusdt", nil))
+		return w
+	}
+
+	w := get(fixture(nil, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var details struct {
+		Balance      string `json:"balance"`
+		Transactions struct {
+			Total    int                      `json:"total"`
+			Decimals int                      `json:"decimals"`
+			Docs     []map[string]interface{} `json:"docs"`
+		} `json:"transactions"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, "5000000", details.Balance)
+	assert.Equal(t, 2, details.Transactions.Total)
+	assert.Equal(t, 18, details.Transactions.Decimals)
+	assert.Len(t, details.Transactions.Docs, 2)
+	assert.Equal(t, "0x2", details.Transactions.Docs[0]["id"])
+	assert.Equal(t, "outgoing", details.Transactions.Docs[0]["direction"])
+	assert.Equal(t, "0x1", details.Transactions.Docs[1]["id"])
+	assert.Equal(t, "incoming", details.Transactions.Docs[1]["direction"])
+
+	tests := []struct {
+		name       string
+		balanceErr error
+		txsErr     error
+		wantCode   int
+		wantError  string
+	}{
+		{"balance error", errors.New("balance failed"), nil, http.StatusInternalServerError, "balance failed"},
+		{"transfers error", nil, errors.New("transfers failed"), http.StatusInternalServerError, "transfers failed"},
+		{"both errors", errors.New("balance failed"), errors.New("transfers failed"), http.StatusInternalServerError, "transfers failed"},
+		{"invalid address", nil, blockatlas.ErrInvalidAddr, http.StatusBadRequest, blockatlas.ErrInvalidAddr.Error()},
+		{"not found", blockatlas.ErrNotFound, nil, http.StatusNotFound, blockatlas.ErrNotFound.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(fixture(tt.balanceErr, tt.txsErr))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantError)
+			assert.NotContains(t, w.Body.String(), `"balance"`)
+		})
+	}
+}
@@ -2,13 +2,65 @@ package endpoint
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
+// TxOptions holds the deployment settings of the transactions endpoints, zero values disable them
+type TxOptions struct {
+	LabelStore    TxLabelStore
+	TrustedTokens blockatlas.TrustedTokens
+	// MaxPages bounds the provider pages read to fill a page of filtered transactions, see getMaxPages
+	MaxPages int
+	Backfill BackfillLimits
+	Assets   AssetRegistry
+	// UpstreamKey authorizes provider overrides, see GetUpstreamPlatform
+	UpstreamKey     string
+	SpamTokens      *blockatlas.SpamTokens
+	SharedAddresses blockatlas.SharedAddresses
+	// BlockedSenders are dropped from the histories, along with the exclude_from param
+	BlockedSenders blockatlas.SenderBlocklist
+	// Enrichments are applied to the returned transactions, nil applies the blockatlas.DefaultEnrichments
+	Enrichments blockatlas.EnrichmentPipeline
+	FullHistory FullHistoryLimits
+	// Recorder snapshots the address requests for fixtures, nil disables it
+	Recorder *blockatlas.Recorder
+	// ContractCache shares the counterparty_type lookups across requests, nil keeps them to a request
+	ContractCache blockatlas.ContractCache
+}
+
+// FullHistoryLimits bound the full_history lookups, zero MaxPages disables them
+type FullHistoryLimits struct {
+	MaxPages int
+	MaxTxs   int
+	Deadline time.Duration
+}
+
+func (o TxOptions) enrichments() blockatlas.EnrichmentPipeline {
+	if o.Enrichments != nil {
+		return o.Enrichments
+	}
+	pipeline, _ := blockatlas.NewEnrichmentPipeline(blockatlas.DefaultEnrichments, o.SpamTokens)
+	return pipeline
+}
+
+const (
+	// maxWaitSeconds bounds the long polling of the transactions history
+	maxWaitSeconds = 60
+	// longPollInterval is the delay between lookups of a long polling request
+	longPollInterval = 5 * time.Second
+	// maxExcludedSenders bounds the exclude_from param
+	maxExcludedSenders = 50
+)
+
 // @Summary Get Transactions
 // @ID tx_v2
 // @Description Get transactions from the address
@@ -17,81 +69,114 @@ import (
 // @Tags Transactions
 // @Param coin path string true "the coin name" default(tezos)
 // @Param address path string true "the query address" default(tz1WCd2jm4uSt4vntk4vSuUWoZQGhLcDuR9q)
+// @Param category query string false "the transactions category: send, swap, stake, reward, fee, contract, staking (stake and reward), transfer (all but staking) or all" default(all)
+// @Param asset_type query string false "the kind of asset moved: native, fungible or nft"
+// @Param page query int false "the 1-based page of offset pagination, at most 20. Pages shift when new transactions come in, prefer after_hash"
+// @Param per_page query int false "the page size of offset pagination, at most 100" default(25)
+// @Param limit query int false "only the latest transactions, at most 25. Served from a smaller provider page when supported"
+// @Param network query string false "the network: mainnet or testnet" default(mainnet)
+// @Param group query string false "group transactions by day with daily totals of the coin, token transfers excluded: day"
+// @Param details query string false "include the inputs, outputs, size and vsize of UTXO transactions: full. v1 responses always include the inputs and outputs"
+// @Param include_internal query bool false "include value moved by contract calls (EVM coins)"
+// @Param min_confirmations query int false "only transactions with at least this number of confirmations"
+// @Param after_hash query string false "only transactions newer than the transaction with this hash, or the cursor of a previous page"
+// @Param label query string false "only transactions with this label attached"
+// @Param counterparty_type query string false "only transactions with a contract or a regular account on the other side (EVM coins): contract or eoa"
+// @Param exclude_zero query bool false "exclude approvals, contract calls and transfers moving no value"
+// @Param min_value query string false "only transactions moving at least this value, in the smallest unit of the asset moved"
+// @Param max_value query string false "only transactions moving at most this value, in the smallest unit of the asset moved"
+// @Param hide_spam query bool false "exclude transfers of known spam tokens"
+// @Param exclude_from query string false "comma separated senders whose transactions are excluded, on top of the blocked senders of known spam campaigns"
+// @Param signed query bool false "include signed_value, the amount moved for the address: negative when outgoing, fee included"
+// @Param required_memo query string false "only deposits tagged with this memo, required for shared deposit addresses of memo coins"
+// @Param wait query int false "with after_hash, wait up to this number of seconds for a newer transaction"
+// @Param full_history query bool false "all the transactions back to the first one, within limits: truncated tells why older transactions are missing"
+// @Param max_pages query int false "the provider pages followed at most, lower than the configured bound or higher for backfill jobs with the X-Backfill-Key header"
+// @Param fields query string false "comma separated transaction fields to return, metadata fields with the metadata. prefix, ignored with group" default(id,date,direction,metadata.value)
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/{coin}/{address} [get]
 // @Router /v2/{coin}/transactions/{address} [get]
-func GetTransactionsHistory(c *gin.Context, txAPI blockatlas.TxAPI, tokenTxAPI blockatlas.TokenTxAPI) {
-	address := c.Param("address")
-	if address == "" {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+func GetTransactionsHistory(c *gin.Context, txAPI blockatlas.TxAPI, tokenTxAPI blockatlas.TokenTxAPI, opts TxOptions) {
+	params, ok := parseTxHistoryParams(c, opts)
+	if !ok {
 		return
 	}
-	token := c.Query("token")
-
-	var (
-		txs types.Txs
-		err error
-	)
-
-	switch {
-	case token == "" && txAPI != nil:
-		txs, err = txAPI.GetTxsByAddress(address)
-	case token != "" && tokenTxAPI != nil:
-		txs, err = tokenTxAPI.GetTokenTxsByAddress(address, token)
-	default:
-		c.AbortWithStatusJSON(
-			http.StatusInternalServerError,
-			errorResponse(errors.New("Failed to find api for that coin")),
-		)
+	txAPI, _ = bindRequest(c, txAPI).(blockatlas.TxAPI)
+	tokenTxAPI, _ = bindRequest(c, tokenTxAPI).(blockatlas.TokenTxAPI)
+	history, ok := newTxHistory(c, params, txAPI, tokenTxAPI, opts)
+	if !ok {
 		return
 	}
-
+	txs, err := history.fetch(c.Request.Context())
 	if err != nil {
-		switch err {
-		case blockatlas.ErrInvalidAddr:
-			c.AbortWithStatusJSON(
-				http.StatusBadRequest,
-				errorResponse(blockatlas.ErrInvalidAddr),
-			)
-			return
-		case blockatlas.ErrNotFound:
-			c.AbortWithStatusJSON(
-				http.StatusNotFound,
-				errorResponse(blockatlas.ErrNotFound),
-			)
-			return
-		case blockatlas.ErrSourceConn:
-			c.AbortWithStatusJSON(
-				http.StatusServiceUnavailable,
-				errorResponse(blockatlas.ErrSourceConn),
-			)
-			return
-		default:
-			c.AbortWithStatusJSON(
-				http.StatusInternalServerError,
-				errorResponse(err),
-			)
+		abortWithTxsError(c, err)
+		return
+	}
+	source := blockatlas.NewTxSource(getProvider(c, history.coin))
+
+	var labels blockatlas.TxLabels
+	if opts.LabelStore != nil {
+		labels, err = getTxLabels(history.coin, params.address, opts.LabelStore)
+		if err != nil {
+			abortWithTxsError(c, err)
 			return
 		}
 	}
-
-	filteredTxs := txs.FilterUniqueID().SortByDate()
-	filteredTxs = filteredTxs.FilterTransactionsByMemo()
-	if token != "" {
-		filteredTxs = filteredTxs.FilterTransactionsByToken(token)
+	filteredTxs, err := history.selectTxs(txs, labels)
+	if err == blockatlas.ErrNotSupported {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
-
-	if len(filteredTxs) > types.TxPerPage {
-		filteredTxs = filteredTxs[0:types.TxPerPage]
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
 	}
 
-	// modify in loop
-	result := make(types.Txs, len(filteredTxs))
-	for i, t := range filteredTxs {
-		result[i] = t
-		result[i].Direction = t.GetTransactionDirection(address)
+	result := blockatlas.SetDirections(filteredTxs, params.address)
+
+	page := blockatlas.NewTxs(blockatlas.ApplyTxDetails(result, params.details))
+	page.SetRecipients(result)
+	extras := getTxExtras(c)
+	if params.details == blockatlas.TxDetailsFull {
+		page.SetTxSizes(extras)
+	}
+	page.SetReplaceable(extras)
+	page.SetInternal(history.internal)
+	if params.signed {
+		page.SetSignedValues()
+	}
+	enrichment := blockatlas.TxEnrichment{Extras: extras, Labels: labels}
+	opts.enrichments().Enrich(page, enrichment)
+	logo := setLogos(c, opts.Assets, history.coin, page)
+	if params.group == blockatlas.TxGroupDay {
+		daysPage := blockatlas.GroupTxsByDay(page, history.coin.Decimals)
+		daysPage.TxSource = source
+		daysPage.Truncated = history.truncation
+		c.JSON(http.StatusOK, daysPage)
+		return
 	}
-	c.JSON(http.StatusOK, types.NewTxPage(result))
+	txPage := blockatlas.NewTxPage(page, history.coin.Decimals)
+	txPage.TxSource = source
+	txPage.HashNotFound = !history.hashFound
+	txPage.Restart = history.reorged
+	txPage.Truncated = history.truncation
+	if params.paginated {
+		txPage.Page, txPage.PerPage, txPage.TotalPages = params.page, params.perPage, history.totalPages
+	}
+	if len(filteredTxs) > 0 {
+		txPage.Cursor = blockatlas.NewTxCursor(filteredTxs[0]).String()
+	}
+	txPage.Logo = logo
+	if len(params.fields) > 0 {
+		docs, err := page.SelectFields(params.fields)
+		if err != nil {
+			abortWithTxsError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, blockatlas.SparseTxPage{TxPage: txPage, Docs: docs})
+		return
+	}
+	respondTxPage(c, txPage)
 }
 
 // @Summary Get Transactions by XPUB
@@ -102,52 +187,199 @@ func GetTransactionsHistory(c *gin.Context, txAPI blockatlas.TxAPI, tokenTxAPI b
 // @Tags Transactions
 // @Param coin path string true "the coin name" default(bitcoin)
 // @Param xpub path string true "the xpub key" default(zpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtihrC)
+// @Param network query string false "the network: mainnet or testnet" default(mainnet)
+// @Param details query string false "include the inputs, outputs, size and vsize of UTXO transactions: full. v1 responses always include the inputs and outputs"
+// @Param token query string false "the token transfers across the derived addresses instead of the native transactions"
+// @Param count_only query bool false "only return the number of transactions"
+// @Param derivation query string false "the derivation standard of the wallet, when it differs from the one of the xpub prefix: bip44, bip49 or bip84"
 // @Failure 500 {object} ErrorResponse
 // @Router /v1/{coin}/{address} [get]
 // @Router /v2/{coin}/transactions/xpub/{xpub} [get]
-func GetTransactionsByXpub(c *gin.Context, api blockatlas.TxUtxoAPI) {
+func GetTransactionsByXpub(c *gin.Context, api blockatlas.TxUtxoAPI, tokenTxAPI blockatlas.TokenTxAPI, opts TxOptions) {
 	xPubKey := c.Param("xpub")
 	if xPubKey == "" {
 		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidKey))
 		return
 	}
-
-	txs, err := api.GetTxsByXpub(xPubKey)
+	details := blockatlas.TxDetails(c.Query("details"))
+	if !details.IsValid() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid details")))
+		return
+	}
+	details = getTxDetails(c, details)
+	countOnly, err := strconv.ParseBool(c.DefaultQuery("count_only", "0"))
 	if err != nil {
-		switch err {
-		case blockatlas.ErrInvalidKey:
-			c.AbortWithStatusJSON(
-				http.StatusBadRequest,
-				errorResponse(blockatlas.ErrInvalidKey),
-			)
-			return
-		case blockatlas.ErrNotFound:
-			c.AbortWithStatusJSON(
-				http.StatusNotFound,
-				errorResponse(blockatlas.ErrNotFound),
-			)
-			return
-		case blockatlas.ErrSourceConn:
-			c.AbortWithStatusJSON(
-				http.StatusServiceUnavailable,
-				errorResponse(blockatlas.ErrSourceConn),
-			)
-			return
-		default:
-			c.AbortWithStatusJSON(
-				http.StatusInternalServerError,
-				errorResponse(err),
-			)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid count_only")))
+		return
+	}
+	derivation := blockatlas.Derivation(c.Query("derivation"))
+	if !derivation.IsValid() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid derivation")))
+		return
+	}
+	// the derived addresses and transactions are looked up with the descriptor of the derivation
+	xPubKey, err = blockatlas.XpubDescriptor(xPubKey, derivation)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	api, _ = bindRequest(c, api).(blockatlas.TxUtxoAPI)
+	tokenTxAPI, _ = bindRequest(c, tokenTxAPI).(blockatlas.TokenTxAPI)
+	token := c.Query("token")
+	addressesAPI, okAddressesAPI := api.(blockatlas.XpubAddressesAPI)
+	if token != "" && (tokenTxAPI == nil || !okAddressesAPI) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+		return
+	}
+
+	// derived addresses are only needed to count token transfers
+	var addresses []blockatlas.XpubAddress
+	if okAddressesAPI && (token != "" || !countOnly) {
+		addresses, err = addressesAPI.GetAddressesFromXpub(xPubKey)
+		if err != nil {
+			abortWithTxsError(c, err)
 			return
 		}
 	}
 
-	filteredTxs := txs.FilterUniqueID().SortByDate()
-	filteredTxs = filteredTxs.FilterTransactionsByMemo()
+	var txs types.Txs
+	if token != "" {
+		used := blockatlas.UsedXpubAddresses(addresses)
+		txs, err = blockatlas.GetTokenTxsByAddresses(tokenTxAPI, blockatlas.GetXpubAddressList(used), token)
+	} else {
+		txs, err = api.GetTxsByXpub(xPubKey)
+	}
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+	source := blockatlas.NewTxSource(getProvider(c, api.Coin()))
+
+	filteredTxs := blockatlas.SortTxs(blockatlas.FilterUniqueTxs(txs))
+	filteredTxs = blockatlas.FilterCoinTxsByMemo(api.Coin(), blockatlas.SanitizeMemos(filteredTxs), opts.TrustedTokens)
+	if token != "" {
+		filteredTxs = blockatlas.FilterCoinTxsByToken(api.Coin(), filteredTxs, token, opts.TrustedTokens)
+	}
+	if countOnly {
+		c.JSON(http.StatusOK, blockatlas.TxCount{Total: len(filteredTxs)})
+		return
+	}
 
 	if len(filteredTxs) > types.TxPerPage {
 		filteredTxs = filteredTxs[0:types.TxPerPage]
 	}
 
-	c.JSON(http.StatusOK, types.NewTxPage(filteredTxs))
+	// direction is computed against all the addresses derived from the xpub
+	if okAddressesAPI && blockatlas.HasMissingDirection(filteredTxs) {
+		filteredTxs = blockatlas.SetDirections(filteredTxs, blockatlas.GetXpubAddressList(addresses)...)
+	}
+
+	page := blockatlas.NewTxs(blockatlas.ApplyTxDetails(filteredTxs, details))
+	page.SetRecipients(filteredTxs)
+	extras := getTxExtras(c)
+	if details == blockatlas.TxDetailsFull {
+		page.SetTxSizes(extras)
+	}
+	page.SetReplaceable(extras)
+	opts.enrichments().Enrich(page, blockatlas.TxEnrichment{Extras: extras})
+	txPage := blockatlas.NewTxPage(page, api.Coin().Decimals)
+	txPage.TxSource = source
+	txPage.Addresses = blockatlas.UsedXpubAddresses(addresses)
+	respondTxPage(c, txPage)
+}
+
+// @Summary Get Transactions Summary
+// @ID tx_summary_v2
+// @Description Get the inflow, outflow and fees of the address in the coin over the transactions returned by the provider, token transfers are not counted
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Param address path string true "the query address" default(bc1qhn03cww757mnnlpkdvvfkaydxqygm86nvkm92h)
+// @Param since query int false "only transactions included after this unix timestamp"
+// @Param until query int false "only transactions included before or at this unix timestamp"
+// @Success 200 {object} blockatlas.TxFlowSummary
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/summary/{address} [get]
+func GetTransactionsSummary(c *gin.Context, txAPI blockatlas.TxAPI) {
+	address := c.Param("address")
+	if address == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidAddr))
+		return
+	}
+	since, err := strconv.ParseInt(c.DefaultQuery("since", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid since param")))
+		return
+	}
+	until, err := strconv.ParseInt(c.DefaultQuery("until", "0"), 10, 64)
+	if err != nil || until < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid until param")))
+		return
+	}
+
+	txs, err := txAPI.GetTxsByAddress(address)
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+
+	filteredTxs := blockatlas.FilterTxsSince(blockatlas.FilterUniqueTxs(txs), 0, since)
+	filteredTxs = blockatlas.FilterTxsUntil(filteredTxs, until)
+	filteredTxs = blockatlas.SetDirections(filteredTxs, address)
+	c.JSON(http.StatusOK, blockatlas.SummarizeTxs(filteredTxs, txAPI.Coin().Decimals))
+}
+
+// parseValueParam parses an optional non-negative amount in the smallest unit, nil when absent
+func parseValueParam(c *gin.Context, name string) (*big.Int, bool) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return nil, true
+	}
+	value, ok := new(big.Int).SetString(raw, 10)
+	if !ok || value.Sign() < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid %s param", name)))
+		return nil, false
+	}
+	return value, true
+}
+
+func abortWithTxsError(c *gin.Context, err error) {
+	fields := log.Fields{"path": c.FullPath(), "error": err}
+	if blockatlas.IsSourceConnError(err) {
+		err = blockatlas.NewSourceError(err)
+		fields["reason"] = blockatlas.GetSourceErrorReason(err)
+	}
+	logger(c).WithFields(fields).Error("Failed to get transactions")
+	switch err {
+	case blockatlas.ErrInvalidAddr, blockatlas.ErrInvalidKey:
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+	case blockatlas.ErrNotFound:
+		c.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+	default:
+		if blockatlas.IsSourceConnError(err) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse(err))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+func getBlockAPI(apis ...blockatlas.Platform) (blockatlas.BlockAPI, bool) {
+	for _, api := range apis {
+		if blockAPI, ok := api.(blockatlas.BlockAPI); ok {
+			return blockAPI, true
+		}
+	}
+	return nil, false
+}
+
+func getContractAPI(apis ...blockatlas.Platform) (blockatlas.ContractAPI, bool) {
+	for _, api := range apis {
+		if contractAPI, ok := api.(blockatlas.ContractAPI); ok {
+			return contractAPI, true
+		}
+	}
+	return nil, false
 }
@@ -0,0 +1,605 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// txAPIFixture serves the transactions of a fixture for any address and token
+type txAPIFixture struct {
+	coin coin.Coin
+	txs  types.Txs
+}
+
+func (f txAPIFixture) Coin() coin.Coin {
+	return f.coin
+}
+
+func (f txAPIFixture) GetTxsByAddress(address string) (types.Txs, error) {
+	return f.txs, nil
+}
+
+func (f txAPIFixture) GetTokenTxsByAddress(address, token string) (types.Txs, error) {
+	return f.txs, nil
+}
+
+const evmAddress = "0x7d8bf18C7cE84b3E175b339c4Ca93aEd1dD166F1"
+
+// txHistoryAPI serves both the transactions and the token transactions of the history, as the fixtures do
+type txHistoryAPI interface {
+	blockatlas.TxAPI
+	blockatlas.TokenTxAPI
+}
+
+func TestGetTransactionsHistory_Fixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		coin     coin.Coin
+		address  string
+		query    string
+		fixture  string
+		expected string
+	}{
+		{"UTXO unique and sorted", coin.Bitcoin(), "bc1qown", "", "utxo_txs.json", "utxo_expected.json"},
+		{"UTXO full details", coin.Bitcoin(), "bc1qown", "details=full", "utxo_txs.json", "utxo_full_expected.json"},
+		{"UTXO after hash", coin.Bitcoin(), "bc1qown", "after_hash=older", "utxo_txs.json", "utxo_after_hash_expected.json"},
+		{"UTXO after hash without waiting", coin.Bitcoin(), "bc1qown", "after_hash=older&wait=30", "utxo_txs.json", "utxo_after_hash_expected.json"},
+		{"UTXO sparse fields", coin.Bitcoin(), "bc1qown", "fields=id,date,direction,hash,metadata.value", "utxo_txs.json", "utxo_fields_expected.json"},
+		{"UTXO after unknown hash", coin.Bitcoin(), "bc1qown", "after_hash=unknown", "utxo_txs.json", "utxo_hash_not_found_expected.json"},
+		{"UTXO after reorged cursor", coin.Bitcoin(), "bc1qown", "after_hash=649990:older", "utxo_txs.json", "utxo_restart_expected.json"},
+		{"EVM all", coin.Ethereum(), evmAddress, "", "evm_txs.json", "evm_expected.json"},
+		{"EVM token", coin.Ethereum(), evmAddress, "token=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "evm_txs.json", "evm_token_expected.json"},
+		{"Memo filtered", coin.Binance(), "bnb1own", "", "memo_txs.json", "memo_expected.json"},
+		{"Memo staking category", coin.Binance(), "bnb1own", "category=staking", "memo_txs.json", "memo_staking_expected.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := txAPIFixture{coin: tt.coin, txs: loadTxs(t, tt.fixture)}
+			w := get(historyRouter(api, api, TxOptions{}), "/"+tt.address+"?"+tt.query)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, string(readFixture(t, tt.expected)), withoutFetchedAt(t, w.Body.Bytes()))
+		})
+	}
+}
+
+func TestGetTransactionsHistory_IDs(t *testing.T) {
+	memo := txAPIFixture{coin: coin.Binance(), txs: loadTxs(t, "memo_txs.json")}
+	evm := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	contracts := contractAPIFixture{txAPIFixture: evm, contracts: map[string]bool{"0x0000000000000000000000000000000000000003": true}}
+	blocklist := TxOptions{BlockedSenders: blockatlas.NewSenderBlocklist(map[string][]string{
+		coin.Ethereum().Handle: {evmAddress},
+	})}
+	sharedMemo := TxOptions{SharedAddresses: blockatlas.NewSharedAddresses(map[string][]string{"binance": {"bnb1own"}})}
+
+	tests := []struct {
+		name    string
+		api     txHistoryAPI
+		opts    TxOptions
+		path    string
+		wantIDs []string
+	}{
+		{"no value bounds", memo, TxOptions{}, "/bnb1own", []string{"delegation", "text-memo", "numeric-memo"}},
+		{"min value", memo, TxOptions{}, "/bnb1own?min_value=5000001", []string{"numeric-memo"}},
+		{"max value", memo, TxOptions{}, "/bnb1own?max_value=5000000", []string{"text-memo"}},
+		{"value window", memo, TxOptions{}, "/bnb1own?min_value=5000000&max_value=100000000", []string{"text-memo", "numeric-memo"}},
+		{"value beyond 64 bits", memo, TxOptions{}, "/bnb1own?max_value=100000000000000000000000", []string{"text-memo", "numeric-memo"}},
+		{"numeric required memo", memo, sharedMemo, "/bnb1own?required_memo=104532", []string{"numeric-memo"}},
+		{"text required memo is matched then cleared", memo, sharedMemo, "/bnb1own?required_memo=%3Cscript%3Ealert(1)%3C%2Fscript%3E", []string{"text-memo"}},
+		{"contract counterparties", contracts, TxOptions{}, "/" + evmAddress + "?counterparty_type=contract", []string{"0xcall"}},
+		{"EOA counterparties", contracts, TxOptions{}, "/" + evmAddress + "?counterparty_type=eoa", []string{"0xtoken", "0xtransfer"}},
+		// blocked senders keep their own transactions
+		{"blocked sender", evm, blocklist, "/" + evmAddress, []string{"0xcall", "0xtoken", "0xtransfer"}},
+		{"from blocked sender", evm, blocklist, "/0x0000000000000000000000000000000000000002", []string{"0xtransfer"}},
+		{"excluded sender", evm, blocklist, "/0x0000000000000000000000000000000000000002?exclude_from=0x0000000000000000000000000000000000000001", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(historyRouter(tt.api, tt.api, tt.opts), tt.path)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantIDs, docIDs(t, w))
+			for _, tx := range docs(t, w) {
+				memo, _ := tx["memo"].(string)
+				assert.True(t, memo == "" || types.AllowMemo(memo))
+			}
+		})
+	}
+}
+
+func TestGetTransactionsHistory_InvalidQuery(t *testing.T) {
+	utxo := txAPIFixture{coin: coin.Bitcoin(), txs: loadTxs(t, "utxo_txs.json")}
+	memo := txAPIFixture{coin: coin.Binance(), txs: loadTxs(t, "memo_txs.json")}
+	evm := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	contracts := contractAPIFixture{txAPIFixture: evm}
+	limited := txLimitFixture{txAPIFixture: evm, limits: new([]int)}
+	fullHistory := TxOptions{FullHistory: FullHistoryLimits{MaxPages: 5, MaxTxs: 100, Deadline: time.Second}}
+	sharedMemo := TxOptions{SharedAddresses: blockatlas.NewSharedAddresses(map[string][]string{"binance": {"bnb1own"}})}
+
+	tests := []struct {
+		name string
+		api  txHistoryAPI
+		opts TxOptions
+		path string
+	}{
+		{"signed", utxo, TxOptions{}, "/bc1qown?signed=maybe"},
+		{"asset type", evm, TxOptions{}, "/" + evmAddress + "?asset_type=erc721"},
+		{"first page", evm, TxOptions{}, "/" + evmAddress + "?page=0"},
+		{"last page", evm, TxOptions{}, "/" + evmAddress + "?page=21"},
+		{"page size", evm, TxOptions{}, "/" + evmAddress + "?per_page=101"},
+		{"page number", evm, TxOptions{}, "/" + evmAddress + "?page=two"},
+		{"limit number", limited, TxOptions{}, "/" + evmAddress + "?limit=0x"},
+		{"negative limit", limited, TxOptions{}, "/" + evmAddress + "?limit=-1"},
+		{"limit size", limited, TxOptions{}, "/" + evmAddress + "?limit=26"},
+		{"limit with page", limited, TxOptions{}, "/" + evmAddress + "?limit=5&page=1"},
+		{"limit with full history", limited, TxOptions{}, "/" + evmAddress + "?limit=5&full_history=1"},
+		{"full history disabled", evm, TxOptions{}, "/" + evmAddress + "?full_history=1"},
+		{"full history with page", evm, fullHistory, "/" + evmAddress + "?full_history=1&page=2"},
+		{"full history of token", evm, fullHistory, "/" + evmAddress + "?full_history=1&token=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"},
+		{"full history flag", evm, fullHistory, "/" + evmAddress + "?full_history=maybe"},
+		{"counterparty type", contracts, TxOptions{}, "/" + evmAddress + "?counterparty_type=bot"},
+		{"counterparty type without contract lookup", evm, TxOptions{}, "/" + evmAddress + "?counterparty_type=eoa"},
+		{"excluded senders", evm, TxOptions{}, "/0x02?exclude_from=" + strings.Repeat("0x01,", maxExcludedSenders+1)},
+		{"min value greater than max", memo, TxOptions{}, "/bnb1own?min_value=2&max_value=1"},
+		{"negative value", memo, TxOptions{}, "/bnb1own?min_value=-1"},
+		{"decimal value", memo, TxOptions{}, "/bnb1own?max_value=1.5"},
+		{"shared address without memo", memo, sharedMemo, "/bnb1own"},
+		{"empty memo", memo, sharedMemo, "/bnb1own?required_memo="},
+		{"coin without memos", txAPIFixture{coin: coin.Bitcoin(), txs: memo.txs}, sharedMemo, "/bnb1own?required_memo=104532"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, http.StatusBadRequest, get(historyRouter(tt.api, tt.api, tt.opts), tt.path).Code)
+		})
+	}
+}
+
+func TestGetTransactionsHistory_EmptyPageSource(t *testing.T) {
+	api := txAPIFixture{coin: coin.Bitcoin()}
+	router := historyRouter(api, api, TxOptions{})
+
+	w := get(router, "/bc1qempty")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"provider":"bitcoin","from_cache":false,"total":0,"docs":[],"status":true,"decimals":8}`, withoutFetchedAt(t, w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/bc1qempty", nil)
+	r.Header.Set(UpstreamOverrideHeader, "https://btc.example.com")
+	router.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"provider":"upstream"`)
+}
+
+// txExtrasFixture returns the extras of its transactions to the request it is bound to
+type txExtrasFixture struct {
+	txAPIFixture
+	extras blockatlas.TxExtrasIndex
+	ctx    context.Context
+}
+
+func (f txExtrasFixture) WithContext(ctx context.Context) blockatlas.Platform {
+	f.ctx = ctx
+	return f
+}
+
+func (f txExtrasFixture) GetTxsByAddress(address string) (types.Txs, error) {
+	for id, extras := range f.extras {
+		blockatlas.AddTxExtras(f.ctx, id, extras)
+	}
+	return f.txs, nil
+}
+
+func TestGetTransactionsHistory_TxSizes(t *testing.T) {
+	api := txExtrasFixture{
+		txAPIFixture: txAPIFixture{coin: coin.Bitcoin(), txs: loadTxs(t, "utxo_txs.json")},
+		extras:       blockatlas.TxExtrasIndex{"newer": {Size: blockatlas.TxSize{Size: 225, VSize: 144}}},
+	}
+	router := historyRouter(api, nil, TxOptions{})
+
+	w := get(router, "/bc1qown?details=full")
+	assert.Equal(t, http.StatusOK, w.Code)
+	page := docs(t, w)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "newer", page[0]["id"])
+	assert.Equal(t, float64(225), page[0]["size"])
+	assert.Equal(t, float64(144), page[0]["vsize"])
+	assert.NotContains(t, page[1], "size")
+
+	assert.NotContains(t, get(router, "/bc1qown").Body.String(), `"vsize"`)
+}
+
+func TestGetTransactionsHistory_V1Details(t *testing.T) {
+	api := txAPIFixture{coin: coin.Bitcoin(), txs: loadTxs(t, "utxo_txs.json")}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		GetTransactionsHistory(c, api, nil, TxOptions{})
+	}
+	router.GET("/v1/bitcoin/:address", handler)
+	router.GET("/v2/bitcoin/transactions/:address", handler)
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		inputs bool
+	}{
+		{"v1 route", "/v1/bitcoin/bc1qown", "", true},
+		{"v2 route accepting v1", "/v2/bitcoin/transactions/bc1qown", V1MediaType, true},
+		{"v2 route", "/v2/bitcoin/transactions/bc1qown", "", false},
+		{"v2 route with full details", "/v2/bitcoin/transactions/bc1qown?details=full", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.inputs, strings.Contains(w.Body.String(), `"inputs"`))
+		})
+	}
+}
+
+func TestGetTransactionsHistory_Replaceable(t *testing.T) {
+	txs := loadTxs(t, "utxo_txs.json")
+	for i := range txs {
+		txs[i].Status = types.StatusPending
+	}
+	api := txExtrasFixture{
+		txAPIFixture: txAPIFixture{coin: coin.Bitcoin(), txs: txs},
+		extras:       blockatlas.TxExtrasIndex{"newer": {RBF: true}},
+	}
+
+	w := get(historyRouter(api, nil, TxOptions{}), "/bc1qown")
+	assert.Equal(t, http.StatusOK, w.Code)
+	page := docs(t, w)
+	assert.Len(t, page, 2)
+	for _, doc := range page {
+		if doc["id"] == "newer" {
+			assert.Equal(t, true, doc["rbf"])
+		} else {
+			assert.NotContains(t, doc, "rbf")
+		}
+	}
+}
+
+func TestGetTransactionsHistory_Signed(t *testing.T) {
+	api := txAPIFixture{coin: coin.Bitcoin(), txs: loadTxs(t, "utxo_txs.json")}
+	router := historyRouter(api, nil, TxOptions{})
+
+	w := get(router, "/bc1qown?signed=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	page := docs(t, w)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "-50141", page[0]["signed_value"])
+	assert.Equal(t, "60000", page[1]["signed_value"])
+
+	assert.NotContains(t, get(router, "/bc1qown").Body.String(), "signed_value")
+}
+
+func TestGetTransactionsHistory_AssetType(t *testing.T) {
+	api := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	router := historyRouter(api, api, TxOptions{})
+
+	for _, assetType := range []blockatlas.AssetType{blockatlas.AssetTypeNative, blockatlas.AssetTypeFungible} {
+		w := get(router, "/"+evmAddress+"?asset_type="+string(assetType))
+		assert.Equal(t, http.StatusOK, w.Code)
+		page := docs(t, w)
+		assert.NotEmpty(t, page, assetType)
+		for _, tx := range page {
+			assert.Equal(t, string(assetType), tx["asset_type"])
+		}
+	}
+}
+
+func TestGetTransactionsHistory_Pagination(t *testing.T) {
+	api := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	router := historyRouter(api, api, TxOptions{})
+	type page struct {
+		Total      int `json:"total"`
+		Page       int `json:"page"`
+		PerPage    int `json:"per_page"`
+		TotalPages int `json:"total_pages"`
+		Docs       []struct {
+			ID string `json:"id"`
+		} `json:"docs"`
+	}
+
+	var all page
+	w := get(router, "/"+evmAddress)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &all))
+	assert.Zero(t, all.Page)
+	assert.Zero(t, all.TotalPages)
+
+	var second page
+	w = get(router, "/"+evmAddress+"?page=2&per_page=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &second))
+	assert.Equal(t, 2, second.Page)
+	assert.Equal(t, 1, second.PerPage)
+	assert.Equal(t, len(all.Docs), second.TotalPages)
+	assert.Equal(t, 1, second.Total)
+	assert.Equal(t, all.Docs[1].ID, second.Docs[0].ID)
+}
+
+// txLimitFixture limits the fixture page natively, recording the limits asked
+type txLimitFixture struct {
+	txAPIFixture
+	limits *[]int
+}
+
+func (f txLimitFixture) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	*f.limits = append(*f.limits, limit)
+	txs := blockatlas.SortTxs(f.txs)
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
+type txInternalFixture struct {
+	txAPIFixture
+	internal types.Txs
+}
+
+func (f txInternalFixture) GetTxsWithInternalByAddress(address string) (types.Txs, types.Txs, error) {
+	return f.txs, f.internal, nil
+}
+
+func TestGetTransactionsHistory_IncludeInternal(t *testing.T) {
+	transfer := func(id, from, to string, date int64, value string) types.Tx {
+		return types.Tx{ID: id, Coin: coin.ETHEREUM, From: from, To: to, Date: date, Block: uint64(date), Status: types.StatusCompleted,
+			Type: types.TxTransfer, Meta: types.Transfer{Value: types.Amount(value), Symbol: "ETH", Decimals: 18}}
+	}
+	var txs types.Txs
+	for i := 1; i <= 30; i++ {
+		txs = append(txs, transfer(fmt.Sprintf("0x%02d", i), evmAddress, "0xcontract", int64(i*10), "1"))
+	}
+	pending := transfer("0xpending", evmAddress, "0xother", 5, "1")
+	pending.Status, pending.Block = types.StatusPending, 0
+	txs = append(txs, pending)
+	internal := types.Txs{
+		transfer("0x03", "0xcontract", evmAddress, 30, "500"),
+		transfer("0x29", "0xcontract", evmAddress, 290, "7"),
+	}
+	api := txInternalFixture{txAPIFixture: txAPIFixture{coin: coin.Ethereum(), txs: txs}, internal: internal}
+	router := historyRouter(api, api, TxOptions{})
+	getDocs := func(query string) []map[string]interface{} {
+		w := get(router, "/"+evmAddress+"?include_internal=1&"+query)
+		assert.Equal(t, http.StatusOK, w.Code, query)
+		return docs(t, w)
+	}
+
+	all := getDocs("per_page=50")
+	assert.Len(t, all, 33)
+	assert.Equal(t, "0xpending", all[0]["id"])
+	assert.Equal(t, "0x30", all[1]["id"])
+	assert.Equal(t, "0x29", all[2]["id"])
+	assert.Equal(t, "0x29", all[3]["id"])
+	assert.NotEqual(t, all[2]["internal"], all[3]["internal"])
+
+	latest := getDocs("")
+	assert.Len(t, latest, types.TxPerPage)
+	assert.Equal(t, "0xpending", latest[0]["id"])
+
+	large := getDocs("min_value=100")
+	assert.Len(t, large, 1)
+	assert.Equal(t, "0x03", large[0]["id"])
+	assert.Equal(t, true, large[0]["internal"])
+
+	limited := getDocs("limit=3")
+	assert.Len(t, limited, 3)
+}
+
+func TestGetTransactionsHistory_Limit(t *testing.T) {
+	var limits []int
+	api := txLimitFixture{txAPIFixture: txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}, limits: &limits}
+	router := historyRouter(api, api, TxOptions{})
+
+	w := get(router, "/"+evmAddress)
+	assert.Equal(t, http.StatusOK, w.Code)
+	all := docIDs(t, w)
+	assert.Empty(t, limits)
+
+	w = get(router, "/"+evmAddress+"?limit=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int{2}, limits)
+	assert.Equal(t, all[:2], docIDs(t, w))
+}
+
+func TestGetTransactionsHistory_FullHistory(t *testing.T) {
+	api := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	router := historyRouter(api, api, TxOptions{FullHistory: FullHistoryLimits{MaxPages: 5, MaxTxs: 100, Deadline: time.Second}})
+
+	w := get(router, "/"+evmAddress+"?full_history=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Total     int    `json:"total"`
+		Truncated string `json:"truncated"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, 3, page.Total)
+	// the fixture provider has no pagination, older transactions may be missing
+	assert.Equal(t, string(blockatlas.TxTruncatedProvider), page.Truncated)
+}
+
+type contractAPIFixture struct {
+	txAPIFixture
+	contracts map[string]bool
+}
+
+func (f contractAPIFixture) IsContract(address string) (bool, error) {
+	return f.contracts[address], nil
+}
+
+func TestGetTransactionsHistory_Enrichments(t *testing.T) {
+	api := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	spam := blockatlas.NewSpamTokens([]string{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"})
+	enrichments, err := blockatlas.NewEnrichmentPipeline([]string{blockatlas.EnricherAssetType}, spam)
+	assert.Nil(t, err)
+
+	w := get(historyRouter(api, api, TxOptions{SpamTokens: spam, Enrichments: enrichments}), "/"+evmAddress)
+	assert.Equal(t, http.StatusOK, w.Code)
+	page := docs(t, w)
+	assert.NotEmpty(t, page)
+	for _, tx := range page {
+		assert.NotContains(t, tx, "is_spam")
+		assert.NotEmpty(t, tx["asset_type"])
+	}
+}
+
+func TestGetTransactionsHistory_Spam(t *testing.T) {
+	api := txAPIFixture{coin: coin.Ethereum(), txs: loadTxs(t, "evm_txs.json")}
+	spam := blockatlas.NewSpamTokens([]string{"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"})
+	router := historyRouter(api, api, TxOptions{SpamTokens: spam})
+
+	tests := []struct {
+		query       string
+		wantFlagged bool
+	}{
+		{"", true},
+		{"hide_spam=1", false},
+	}
+	for _, tt := range tests {
+		w := get(router, "/"+evmAddress+"?"+tt.query)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var page struct {
+			Docs []struct {
+				IsSpam   bool `json:"is_spam"`
+				Metadata struct {
+					TokenID string `json:"token_id"`
+				} `json:"metadata"`
+			} `json:"docs"`
+		}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.NotEmpty(t, page.Docs)
+		flagged := false
+		for _, tx := range page.Docs {
+			assert.Equal(t, spam.Contains(tx.Metadata.TokenID), tx.IsSpam)
+			flagged = flagged || tx.IsSpam
+		}
+		assert.Equal(t, tt.wantFlagged, flagged, tt.query)
+	}
+}
+
+func (f txAPIFixture) GetTxsByXpub(xpub string) (types.Txs, error) {
+	return f.txs, nil
+}
+
+func TestGetTransactionsByXpub_CountOnly(t *testing.T) {
+	api := txAPIFixture{coin: coin.Bitcoin(), txs: loadTxs(t, "utxo_txs.json")}
+	router := newRouter("/:xpub", func(c *gin.Context) {
+		GetTransactionsByXpub(c, api, nil, TxOptions{})
+	})
+
+	w := get(router, "/zpub?count_only=1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"total":2}`, w.Body.String())
+}
+
+// xpubKeyFixture records the keys the XPUB transactions are looked up with
+type xpubKeyFixture struct {
+	txAPIFixture
+	keys *[]string
+}
+
+func (f xpubKeyFixture) GetTxsByXpub(xpub string) (types.Txs, error) {
+	*f.keys = append(*f.keys, xpub)
+	return f.txs, nil
+}
+
+func TestGetTransactionsByXpub_Derivation(t *testing.T) {
+	var keys []string
+	api := xpubKeyFixture{txAPIFixture: txAPIFixture{coin: coin.Bitcoin()}, keys: &keys}
+	router := newRouter("/:xpub", func(c *gin.Context) {
+		GetTransactionsByXpub(c, api, nil, TxOptions{})
+	})
+
+	assert.Equal(t, http.StatusOK, get(router, "/xpub6C?derivation=bip84").Code)
+	assert.Equal(t, http.StatusOK, get(router, "/zpub6r?derivation=bip84").Code)
+	assert.Equal(t, http.StatusOK, get(router, "/zpub6r").Code)
+	assert.Equal(t, []string{"wpkh(xpub6C)", "zpub6r", "zpub6r"}, keys)
+
+	assert.Equal(t, http.StatusBadRequest, get(router, "/zpub6r?derivation=bip44").Code)
+	assert.Equal(t, http.StatusBadRequest, get(router, "/zpub6r?derivation=bip86").Code)
+	assert.Len(t, keys, 3)
+}
+
+// newRouter serves the handler on the route, in test mode
+func newRouter(route string, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(route, handler)
+	return router
+}
+
+// historyRouter serves the transactions history of the APIs on /:address
+func historyRouter(txAPI blockatlas.TxAPI, tokenTxAPI blockatlas.TokenTxAPI, opts TxOptions) *gin.Engine {
+	return newRouter("/:address", func(c *gin.Context) {
+		GetTransactionsHistory(c, txAPI, tokenTxAPI, opts)
+	})
+}
+
+func get(router http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+// docs returns the transactions of the page of the response
+func docs(t *testing.T, w *httptest.ResponseRecorder) []map[string]interface{} {
+	var page struct {
+		Docs []map[string]interface{} `json:"docs"`
+	}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page.Docs
+}
+
+// docIDs returns the IDs of the transactions of the page of the response, in order
+func docIDs(t *testing.T, w *httptest.ResponseRecorder) []string {
+	ids := make([]string, 0)
+	for _, tx := range docs(t, w) {
+		ids = append(ids, tx["id"].(string))
+	}
+	return ids
+}
+
+func loadTxs(t *testing.T, fixture string) types.Txs {
+	var txs types.Txs
+	assert.Nil(t, json.Unmarshal(readFixture(t, fixture), &txs))
+	return txs
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("mocks", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// withoutFetchedAt drops the time the page was fetched at, after checking it is set
+func withoutFetchedAt(t *testing.T, body []byte) string {
+	var page map[string]interface{}
+	assert.Nil(t, json.Unmarshal(body, &page))
+	assert.NotZero(t, page["fetched_at"])
+	delete(page, "fetched_at")
+	raw, err := json.Marshal(page)
+	assert.Nil(t, err)
+	return string(raw)
+}
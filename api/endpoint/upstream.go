@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+const (
+	UpstreamOverrideHeader    = "X-Upstream-Override"
+	UpstreamOverrideKeyHeader = "X-Upstream-Override-Key"
+)
+
+var errUpstreamForbidden = errors.New("upstream override not allowed")
+
+// UpstreamInitializer initializes the platform of a coin handle with another provider API
+type UpstreamInitializer func(handle, api string) (blockatlas.Platform, bool)
+
+// GetUpstreamPlatform resolves the platform serving the request from the provider URL of the X-Upstream-Override header.
+// Overrides require the X-Upstream-Override-Key header to match the key, they are disabled with an empty key.
+// Overridden responses are not cached and skip the failover of the coin.
+func GetUpstreamPlatform(c *gin.Context, p blockatlas.Platform, key string, initPlatform UpstreamInitializer) (blockatlas.Platform, bool) {
+	upstream := c.GetHeader(UpstreamOverrideHeader)
+	if upstream == "" {
+		return p, true
+	}
+	requestKey := c.GetHeader(UpstreamOverrideKeyHeader)
+	if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(requestKey)) != 1 {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errUpstreamForbidden))
+		return nil, false
+	}
+	u, err := url.Parse(upstream)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid upstream url")))
+		return nil, false
+	}
+	overridden, ok := initPlatform(p.Coin().Handle, upstream)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrNotSupported))
+		return nil, false
+	}
+	logger(c).WithFields(log.Fields{"coin": p.Coin().Handle, "upstream": u.Host}).Warn("Serving request from upstream override")
+	c.Header("Cache-Control", "no-store")
+	return overridden, true
+}
+
+// getProvider names the provider serving the request, overrides are past GetUpstreamPlatform when reaching a handler.
+// Lookups served by a failover are named after the provider answering them, e.g. bitcoin/1 for the first secondary API
+func getProvider(c *gin.Context, txCoin coin.Coin) string {
+	if c.GetHeader(UpstreamOverrideHeader) != "" {
+		return "upstream"
+	}
+	if provider, ok := blockatlas.ServedBy(c.Request.Context()); ok {
+		return txCoin.Handle + "/" + provider
+	}
+	return txCoin.Handle
+}
+
+// bindRequest binds the provider lookups of the platform to the request, for getProvider, getTxExtras
+// and the recordings to see them
+func bindRequest(c *gin.Context, p blockatlas.Platform) blockatlas.Platform {
+	if p == nil {
+		return nil
+	}
+	ctx := blockatlas.TrackTxExtras(blockatlas.TrackProvider(c.Request.Context()))
+	c.Request = c.Request.WithContext(ctx)
+	return blockatlas.BindContext(p, c.Request.Context())
+}
+
+// getTxExtras returns the extras of the transactions the platforms bound to the request returned, see bindRequest
+func getTxExtras(c *gin.Context) blockatlas.TxExtrasIndex {
+	return blockatlas.GetTxExtras(c.Request.Context())
+}
@@ -0,0 +1,63 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetUpstreamPlatform(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	primary := txAPIFixture{coin: coin.Bitcoin()}
+	staging := txAPIFixture{coin: coin.Bitcoin(), txs: types.Txs{{ID: "staging"}}}
+	initPlatform := func(handle, api string) (blockatlas.Platform, bool) {
+		if handle != coin.Bitcoin().Handle {
+			return nil, false
+		}
+		return staging, true
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		upstream string
+		auth     string
+		code     int
+		want     blockatlas.Platform
+	}{
+		{"no override", "secret", "", "", http.StatusOK, primary},
+		{"override", "secret", "https://btc-staging.example.com", "secret", http.StatusOK, staging},
+		{"disabled", "", "https://btc-staging.example.com", "", http.StatusForbidden, nil},
+		{"wrong key", "secret", "https://btc-staging.example.com", "guess", http.StatusForbidden, nil},
+		{"invalid url", "secret", "btc-staging", "secret", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got blockatlas.Platform
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				if p, ok := GetUpstreamPlatform(c, primary, tt.key, initPlatform); ok {
+					got = p
+					c.Status(http.StatusOK)
+				}
+			})
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.upstream != "" {
+				r.Header.Set(UpstreamOverrideHeader, tt.upstream)
+			}
+			if tt.auth != "" {
+				r.Header.Set(UpstreamOverrideKeyHeader, tt.auth)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
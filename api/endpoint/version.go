@@ -0,0 +1,44 @@
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+const (
+	APIVersion1 = 1
+	APIVersion2 = 2
+
+	// V1MediaType requests the v1 response shape from the v2 routes
+	V1MediaType = "application/vnd.blockatlas.v1+json"
+)
+
+// getAPIVersion negotiates the shape of the response, v1 routes and clients accepting
+// the v1 media type get the v1 shape, see blockatlas.TxPage.V1 for the differences
+func getAPIVersion(c *gin.Context) int {
+	if strings.HasPrefix(c.FullPath(), "/v1/") || strings.Contains(c.GetHeader("Accept"), V1MediaType) {
+		return APIVersion1
+	}
+	return APIVersion2
+}
+
+// getTxDetails is the level of detail of the UTXO transactions of the response. The v1 shape keeps
+// the inputs and outputs it always served, the details param only applies to v2
+func getTxDetails(c *gin.Context, details blockatlas.TxDetails) blockatlas.TxDetails {
+	if getAPIVersion(c) == APIVersion1 {
+		return blockatlas.TxDetailsFull
+	}
+	return details
+}
+
+// respondTxPage writes the transactions page in the shape of the negotiated API version
+func respondTxPage(c *gin.Context, page blockatlas.TxPage) {
+	if getAPIVersion(c) == APIVersion1 {
+		c.JSON(http.StatusOK, page.V1())
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestRespondTxPage(t *testing.T) {
+	txs := blockatlas.NewTxs(types.Txs{{ID: "0x1", Block: 10, Date: 100, Status: types.StatusCompleted, Meta: types.Transfer{Value: "1", Symbol: "ETH", Decimals: 18}}})
+	txs[0].Labels = []string{"rent"}
+	page := blockatlas.NewTxPage(txs, 18)
+	page.Cursor = "10:0x1"
+	page.TxSource = blockatlas.TxSource{Provider: "ethereum", FetchedAt: 1600000000}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		respondTxPage(c, page)
+	}
+	router.GET("/v1/ethereum/:address", handler)
+	router.GET("/v2/ethereum/transactions/:address", handler)
+
+	v1 := `{"total":1,"status":true,"docs":[{"id":"0x1","coin":0,"from":"","to":"","fee":"","date":100,"block":10,"status":"completed","sequence":0,"type":"transfer","memo":"","metadata":{"value":"1","symbol":"ETH","decimals":18}}]}`
+	v2 := `{"total":1,"status":true,"decimals":18,"cursor":"10:0x1","provider":"ethereum","fetched_at":1600000000,"from_cache":false,"docs":[{"id":"0x1","coin":0,"from":"","to":"","fee":"","date":100,"block":10,"status":"completed","sequence":0,"type":"transfer","memo":"","metadata":{"value":"1","symbol":"ETH","decimals":18},"block_height":10,"block_time":100,"category":"send","labels":["rent"]}]}`
+
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		want   string
+	}{
+		{"v1 route", "/v1/ethereum/0xown", "", v1},
+		{"v2 route", "/v2/ethereum/transactions/0xown", "application/json", v2},
+		{"v2 route accepting v1", "/v2/ethereum/transactions/0xown", V1MediaType, v1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.want, w.Body.String())
+		})
+	}
+}
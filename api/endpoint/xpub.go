@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+// maxXpubAddresses caps the addresses derived by request, the gap limit of Blockbook which always
+// derives that many receiving addresses past the last used one
+const maxXpubAddresses = 20
+
+// @Summary Get XPUB Addresses
+// @ID xpub_addresses_v2
+// @Description Get the first receiving addresses derived from the XPUB, without their transactions
+// @Accept json
+// @Produce json
+// @Tags Transactions
+// @Param coin path string true "the coin name" default(bitcoin)
+// @Param xpub path string true "the xpub key" default(zpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtihrC)
+// @Param count query int false "the number of addresses, at most 20" default(20)
+// @Param derivation query string false "the derivation standard of the wallet, when it differs from the one of the xpub prefix: bip44, bip49 or bip84"
+// @Success 200 {array} blockatlas.XpubAddress
+// @Failure 500 {object} ErrorResponse
+// @Router /v2/{coin}/xpub/{xpub}/addresses [get]
+func GetXpubAddresses(c *gin.Context, api blockatlas.XpubAddressesAPI) {
+	xPubKey := c.Param("xpub")
+	if !blockatlas.IsValidXpub(xPubKey) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(blockatlas.ErrInvalidKey))
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(maxXpubAddresses)))
+	if err != nil || count < 1 || count > maxXpubAddresses {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid count param, at most %d", maxXpubAddresses)))
+		return
+	}
+	derivation := blockatlas.Derivation(c.Query("derivation"))
+	if !derivation.IsValid() {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(errors.New("invalid derivation")))
+		return
+	}
+	xPubKey, err = blockatlas.XpubDescriptor(xPubKey, derivation)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	addresses, err := api.GetAddressesFromXpub(xPubKey)
+	if err != nil {
+		abortWithTxsError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, blockatlas.FirstXpubAddresses(addresses, count))
+}
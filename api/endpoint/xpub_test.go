@@ -0,0 +1,80 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+const testXpub = "zpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtihrC"
+
+// xpubAddressesFixture derives 25 receiving and change addresses
+type xpubAddressesFixture struct {
+	keys *[]string
+}
+
+func (f xpubAddressesFixture) Coin() coin.Coin {
+	return coin.Bitcoin()
+}
+
+func (f xpubAddressesFixture) GetAddressesFromXpub(xpub string) ([]blockatlas.XpubAddress, error) {
+	*f.keys = append(*f.keys, xpub)
+	addresses := make([]blockatlas.XpubAddress, 0)
+	for chain := 1; chain >= 0; chain-- {
+		for i := 24; i >= 0; i-- {
+			addresses = append(addresses, blockatlas.XpubAddress{
+				Address: fmt.Sprintf("bc1q%d_%d", chain, i),
+				Path:    fmt.Sprintf("m/84'/0'/0'/%d/%d", chain, i),
+				Index:   uint32(i),
+			})
+		}
+	}
+	return addresses, nil
+}
+
+func TestGetXpubAddresses(t *testing.T) {
+	var keys []string
+	api := xpubAddressesFixture{keys: &keys}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/:xpub", func(c *gin.Context) {
+		GetXpubAddresses(c, api)
+	})
+	get := func(path string) (int, []string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var addresses []blockatlas.XpubAddress
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &addresses))
+		return w.Code, blockatlas.GetXpubAddressList(addresses)
+	}
+
+	code, addresses := get("/" + testXpub + "?count=3")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{"bc1q0_0", "bc1q0_1", "bc1q0_2"}, addresses)
+	code, addresses = get("/" + testXpub)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, addresses, maxXpubAddresses)
+	assert.Equal(t, []string{testXpub, testXpub}, keys)
+
+	for _, path := range []string{
+		"/" + testXpub + "?count=0",
+		"/" + testXpub + "?count=21",
+		"/" + testXpub + "?derivation=bip44",
+		"/zpub6r",
+	} {
+		code, _ := get(path)
+		assert.Equal(t, http.StatusBadRequest, code, path)
+	}
+	assert.Len(t, keys, 2)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/trustwallet/blockatlas/api/endpoint"
+)
+
+// InFlightLimiter bounds the concurrent requests of each client. Clients sending a known key in the
+// X-API-Key header are told apart by key with the key limit, the others by IP. The IP is the address of
+// the connection, the X-Forwarded-For header being only trusted from the trusted proxies.
+type InFlightLimiter struct {
+	limit    int
+	keyLimit int
+	keys     []string
+	proxies  []*net.IPNet
+
+	mu       sync.Mutex
+	inFlight map[string]int
+}
+
+var errTooManyInFlight = errors.New("too many concurrent requests")
+
+// NewInFlightLimiter returns the limiter of the limit by IP and keyLimit by known key, nil when limit is 0
+func NewInFlightLimiter(limit, keyLimit int, keys []string, proxies []*net.IPNet) *InFlightLimiter {
+	if limit == 0 {
+		return nil
+	}
+	return &InFlightLimiter{
+		limit:    limit,
+		keyLimit: keyLimit,
+		keys:     keys,
+		proxies:  proxies,
+		inFlight: make(map[string]int),
+	}
+}
+
+// ParseTrustedProxies parses the IPs and CIDR ranges of the proxies in front of the API
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			if ip := net.ParseIP(value); ip != nil && ip.To4() != nil {
+				value += "/32"
+			} else {
+				value += "/128"
+			}
+		}
+		_, proxy, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %v", value, err)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// client returns the client of the request and its limit
+func (l *InFlightLimiter) client(c *gin.Context) (string, int) {
+	if key := c.GetHeader(endpoint.APIKeyHeader); key != "" {
+		for _, known := range l.keys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(known)) == 1 {
+				return "key:" + known, l.keyLimit
+			}
+		}
+	}
+	return "ip:" + l.clientIP(c.Request), l.limit
+}
+
+// clientIP returns the address of the connection, or behind trusted proxies the last address of
+// X-Forwarded-For they didn't add, the addresses before it being set by the client
+func (l *InFlightLimiter) clientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		ip = strings.TrimSpace(r.RemoteAddr)
+	}
+	if !l.isTrustedProxy(ip) {
+		return ip
+	}
+	forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		if !l.isTrustedProxy(hop) {
+			return hop
+		}
+		ip = hop
+	}
+	return ip
+}
+
+func (l *InFlightLimiter) isTrustedProxy(value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range l.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *InFlightLimiter) acquire(client string, limit int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight[client] >= limit {
+		return false
+	}
+	l.inFlight[client]++
+	return true
+}
+
+func (l *InFlightLimiter) release(client string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight[client]--
+	if l.inFlight[client] <= 0 {
+		delete(l.inFlight, client)
+	}
+}
+
+// InFlightLimitMiddleware answers 429 to the clients already running their quota of requests, a nil limiter disables it.
+// Batches aren't counted, their sub-requests are
+func InFlightLimitMiddleware(l *InFlightLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil || c.Request.URL.Path == endpoint.BatchPath {
+			c.Next()
+			return
+		}
+		client, limit := l.client(c)
+		if !l.acquire(client, limit) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, endpoint.ErrorResponse{
+				Error: endpoint.ErrorDetails{Message: errTooManyInFlight.Error()},
+			})
+			return
+		}
+		defer l.release(client)
+		c.Next()
+	}
+}
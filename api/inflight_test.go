@@ -0,0 +1,112 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/api/endpoint"
+)
+
+func TestInFlightLimitMiddleware(t *testing.T) {
+	limiter := NewInFlightLimiter(1, 2, []string{"partner-key"}, nil)
+	started, release := make(chan struct{}), make(chan struct{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(InFlightLimitMiddleware(limiter))
+	router.GET("/slow", func(c *gin.Context) {
+		started <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func(path, ip, key string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = ip + ":1234"
+		if key != "" {
+			r.Header.Set(endpoint.APIKeyHeader, key)
+		}
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	var wg sync.WaitGroup
+	slow := func(ip, key string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, get("/slow", ip, key))
+		}()
+		<-started
+	}
+	slow("10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, get("/fast", "10.0.0.1", ""))
+	assert.Equal(t, http.StatusTooManyRequests, get("/fast", "10.0.0.1", "unknown-key"))
+	assert.Equal(t, http.StatusOK, get("/fast", "10.0.0.2", ""))
+
+	slow("10.0.0.1", "partner-key")
+	assert.Equal(t, http.StatusOK, get("/fast", "10.0.0.3", "partner-key"))
+	slow("10.0.0.3", "partner-key")
+	assert.Equal(t, http.StatusTooManyRequests, get("/fast", "10.0.0.4", "partner-key"))
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, get("/fast", "10.0.0.1", ""))
+	assert.Empty(t, limiter.inFlight)
+}
+
+func TestInFlightLimitMiddleware_Disabled(t *testing.T) {
+	assert.Nil(t, NewInFlightLimiter(0, 2, nil, nil))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(InFlightLimitMiddleware(nil))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestInFlightLimiter_ClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	assert.Nil(t, err)
+	limiter := NewInFlightLimiter(1, 1, nil, proxies)
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded string
+		want      string
+	}{
+		{"direct", "203.0.113.7:1234", "", "203.0.113.7"},
+		{"spoofed", "203.0.113.7:1234", "198.51.100.1", "203.0.113.7"},
+		{"behind proxy", "10.1.2.3:1234", "198.51.100.1", "198.51.100.1"},
+		{"spoofed behind proxy", "10.1.2.3:1234", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"behind proxies", "10.1.2.3:1234", "198.51.100.1, 192.168.1.1", "198.51.100.1"},
+		{"proxy without header", "192.168.1.1:1234", "", "192.168.1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			r.Header.Set("X-Real-Ip", "1.1.1.1")
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			assert.Equal(t, tt.want, limiter.clientIP(r))
+		})
+	}
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.NotNil(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy"})
+	assert.NotNil(t, err)
+}
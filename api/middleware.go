@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/api/endpoint"
+	"github.com/trustwallet/blockatlas/config"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/blockatlas/services/parser"
+	"github.com/trustwallet/golibs/coin"
+)
+
+// cacheControlWriter sets the Cache-Control header on successful responses only,
+// so errors are never cached by proxies
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value string
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		w.Header().Set("Cache-Control", w.value)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// CacheControlMiddleware sets the max-age of successful responses
+func CacheControlMiddleware(maxAge time.Duration) gin.HandlerFunc {
+	value := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+	return func(c *gin.Context) {
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, value: value}
+		c.Next()
+	}
+}
+
+// GetMaxAge returns the configured max-age of the coin, otherwise its block time bounded by the configured limits
+func GetMaxAge(c coin.Coin) time.Duration {
+	cfg := config.Default.API.CacheControl
+	if maxAge, ok := cfg.Coins[c.Handle]; ok {
+		return maxAge
+	}
+	return parser.GetInterval(c.BlockTime, cfg.Min, cfg.Max)
+}
+
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware accepts the X-Request-ID header of the client or generates one,
+// stores it in the request context for log entries, see blockatlas.Logger, and echoes it in the response
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(endpoint.RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(endpoint.RequestIDKey, id)
+		c.Request = c.Request.WithContext(blockatlas.WithRequestID(c.Request.Context(), id))
+		c.Header(endpoint.RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// AdminAuthMiddleware authenticates the operators of the admin endpoints by the key of the
+// X-Admin-Key header, operators maps their names to their keys. The operator name is stored
+// in the context for the audit log entries.
+func AdminAuthMiddleware(operators map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(endpoint.AdminKeyHeader)
+		operator := ""
+		for name, operatorKey := range operators {
+			if operatorKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(operatorKey)) == 1 {
+				operator = name
+			}
+		}
+		if operator == "" {
+			blockatlas.Logger(c.Request.Context()).WithField("path", c.Request.URL.Path).Warn("Unauthorized admin request")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, endpoint.ErrorResponse{
+				Error: endpoint.ErrorDetails{Message: errUnauthorized.Error()},
+			})
+			return
+		}
+		c.Set(endpoint.OperatorKey, operator)
+		c.Next()
+	}
+}
+
+var errUnauthorized = errors.New("unauthorized")
+
+// LabelsAuthMiddleware authorizes the label writes by the key of the X-Labels-Key header,
+// held by the backend writing the labels on behalf of the users
+func LabelsAuthMiddleware(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestKey := c.GetHeader(endpoint.LabelsKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(requestKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, endpoint.ErrorResponse{
+				Error: endpoint.ErrorDetails{Message: errUnauthorized.Error()},
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ProviderHealthMiddleware counts the transactions requests failing on the provider of a coin without failover,
+// the failover observes its providers. Requests failing on the client side or served by an upstream override are ignored.
+func ProviderHealthMiddleware(window *blockatlas.HealthWindow) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if window == nil || c.GetHeader(endpoint.UpstreamOverrideHeader) != "" {
+			return
+		}
+		switch status := c.Writer.Status(); {
+		case status == http.StatusServiceUnavailable:
+			window.Observe(true)
+		case status < http.StatusBadRequest:
+			window.Observe(false)
+		}
+	}
+}
+
+// DataStalenessMiddleware hints clients whether the queue feeding the database is backed up,
+// a nil backlog disables it
+func DataStalenessMiddleware(backlog endpoint.QueueBacklog, threshold int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if backlog != nil {
+			c.Header(endpoint.DataStalenessHeader, string(endpoint.GetDataStaleness(backlog, threshold)))
+		}
+		c.Next()
+	}
+}
+
+// singleProviderHealth reports the health of a coin without failover, observed by ProviderHealthMiddleware
+type singleProviderHealth struct {
+	window *blockatlas.HealthWindow
+}
+
+func (h singleProviderHealth) Health() blockatlas.CoinHealth {
+	return blockatlas.NewCoinHealth(h.window.ProviderHealth("0"))
+}
+
+// recordingWriter keeps a copy of the response body for the recorder
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// RecordingMiddleware records the upstream responses of the address requests of coin with the response served,
+// a nil recorder disables it
+func RecordingMiddleware(recorder *blockatlas.Recorder, coin string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if recorder == nil {
+			c.Next()
+			return
+		}
+		address, query := c.Param("address"), c.Request.URL.Query()
+		path, err := recorder.Record(c.Request.Context(), coin, address, query, func(ctx context.Context) (int, []byte) {
+			writer := &recordingWriter{ResponseWriter: c.Writer}
+			c.Writer = writer
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			c.Writer = writer.ResponseWriter
+			return writer.Status(), writer.body.Bytes()
+		})
+		if err != nil {
+			blockatlas.Logger(c.Request.Context()).WithFields(log.Fields{"coin": coin, "address": address}).WithError(err).Error("Failed to write the recording")
+			return
+		}
+		blockatlas.Logger(c.Request.Context()).WithFields(log.Fields{"coin": coin, "address": address, "path": path}).Info("Recorded request")
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/api/endpoint"
+	"github.com/trustwallet/blockatlas/config"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+)
+
+func TestProviderHealthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	window := &blockatlas.HealthWindow{}
+	router := gin.New()
+	router.GET("/:status", ProviderHealthMiddleware(window), func(c *gin.Context) {
+		status, _ := strconv.Atoi(c.Param("status"))
+		c.Status(status)
+	})
+	get := func(status int, override bool) {
+		r := httptest.NewRequest(http.MethodGet, "/"+strconv.Itoa(status), nil)
+		if override {
+			r.Header.Set(endpoint.UpstreamOverrideHeader, "https://btc-staging.example.com")
+		}
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	get(http.StatusOK, false)
+	get(http.StatusServiceUnavailable, false)
+	get(http.StatusBadRequest, false)
+	get(http.StatusServiceUnavailable, true)
+	health := singleProviderHealth{window: window}.Health()
+	assert.Equal(t, 2, health.Providers[0].Requests)
+	assert.Equal(t, 0.5, health.Providers[0].FailureRate)
+
+	for i := 0; i < 3; i++ {
+		get(http.StatusServiceUnavailable, false)
+	}
+	assert.Equal(t, blockatlas.ProviderStatusDown, singleProviderHealth{window: window}.Health().Status)
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ok", CacheControlMiddleware(time.Minute), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	router.GET("/error", CacheControlMiddleware(time.Minute), func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{})
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/error", nil))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
+
+func TestGetMaxAge(t *testing.T) {
+	config.Default.API.CacheControl.Min = 5 * time.Second
+	config.Default.API.CacheControl.Max = time.Minute
+	config.Default.API.CacheControl.Coins = map[string]time.Duration{"bitcoin": 2 * time.Minute}
+
+	assert.Equal(t, 2*time.Minute, GetMaxAge(coin.Bitcoin()))
+	assert.Equal(t, 5*time.Second, GetMaxAge(coin.Binance()))
+	assert.Equal(t, 10*time.Second, GetMaxAge(coin.Ethereum()))
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", RequestIDMiddleware(), func(c *gin.Context) {
+		assert.Equal(t, c.GetString("request_id"), blockatlas.RequestID(c.Request.Context()))
+		c.String(http.StatusOK, c.GetString("request_id"))
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "client-id-1")
+	router.ServeHTTP(w, r)
+	assert.Equal(t, "client-id-1", w.Header().Get("X-Request-ID"))
+	assert.Equal(t, "client-id-1", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-ID", "invalid id")
+	router.ServeHTTP(w, r)
+	assert.Len(t, w.Header().Get("X-Request-ID"), 32)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), w.Body.String())
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin", AdminAuthMiddleware(map[string]string{"alice": "secret-a", "bob": ""}), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("operator"))
+	})
+
+	tests := []struct {
+		key      string
+		wantCode int
+		wantBody string
+	}{
+		{"secret-a", http.StatusOK, "alice"},
+		{"", http.StatusUnauthorized, `{"error":{"message":"unauthorized"}}`},
+		{"secret-b", http.StatusUnauthorized, `{"error":{"message":"unauthorized"}}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/admin", nil)
+		r.Header.Set("X-Admin-Key", tt.key)
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tt.wantCode, w.Code, tt.key)
+		assert.Equal(t, tt.wantBody, w.Body.String(), tt.key)
+	}
+}
+
+func TestLabelsAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/labels", LabelsAuthMiddleware("secret"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.POST("/disabled", LabelsAuthMiddleware(""), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		path     string
+		key      string
+		wantCode int
+	}{
+		{"/labels", "secret", http.StatusOK},
+		{"/labels", "", http.StatusUnauthorized},
+		{"/labels", "guess", http.StatusUnauthorized},
+		{"/disabled", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		r.Header.Set("X-Labels-Key", tt.key)
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tt.wantCode, w.Code, tt.path+" "+tt.key)
+	}
+}
+
+func TestRecordingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &blockatlas.Recorder{Dir: t.TempDir()}
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"docs": []string{c.Param("address")}, "recording": blockatlas.IsRecording(c.Request.Context())})
+	}
+	router.GET("/recorded/:address", RecordingMiddleware(recorder, "ethereum"), handler)
+	router.GET("/plain/:address", RecordingMiddleware(nil, "ethereum"), handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/recorded/0xabc?page=2", nil))
+	assert.Equal(t, `{"docs":["0xabc"],"recording":true}`, w.Body.String())
+
+	raw, err := ioutil.ReadFile(filepath.Join(recorder.Dir, blockatlas.RecordingName("ethereum", "0xabc", url.Values{"page": {"2"}})))
+	assert.Nil(t, err)
+	var recording blockatlas.Recording
+	assert.Nil(t, json.Unmarshal(raw, &recording))
+	assert.Equal(t, "0xabc", recording.Address)
+	assert.Equal(t, "page=2", recording.Query)
+	assert.Equal(t, http.StatusOK, recording.Status)
+	assert.JSONEq(t, `{"docs":["0xabc"],"recording":true}`, string(recording.Response))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain/0xdef", nil))
+	assert.Equal(t, `{"docs":["0xdef"],"recording":false}`, w.Body.String())
+	files, err := ioutil.ReadDir(recorder.Dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 1)
+}
+
+type depthFixture int
+
+func (d depthFixture) Depth() (int, bool) {
+	return int(d), true
+}
+
+func TestDataStalenessMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		backlog endpoint.QueueBacklog
+		want    string
+	}{
+		{nil, ""},
+		{depthFixture(10), "current"},
+		{depthFixture(5000), "lagging"},
+	}
+	for _, tt := range tests {
+		router := gin.New()
+		router.GET("/", DataStalenessMiddleware(tt.backlog, 1000), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, tt.want, w.Header().Get(endpoint.DataStalenessHeader))
+	}
+}
@@ -11,31 +11,135 @@ import (
 	"github.com/trustwallet/golibs/network/middleware"
 )
 
-func RegisterTransactionsAPI(router gin.IRouter, api blockatlas.Platform) {
+// RegisterTransactionsAPI registers the transactions endpoints of the platform, the health window
+// counts the provider failures of the coins without failover and may be nil
+func RegisterTransactionsAPI(router gin.IRouter, api blockatlas.Platform, opts endpoint.TxOptions, health *blockatlas.HealthWindow) {
 	handle := api.Coin().Handle
-	txUtxoAPI, ok := api.(blockatlas.TxUtxoAPI)
-	if ok {
-		router.GET("/v1/"+handle+"/address/:address", func(c *gin.Context) {
-			endpoint.GetTransactionsHistory(c, txUtxoAPI, nil)
+	cacheControl := CacheControlMiddleware(GetMaxAge(api.Coin()))
+	observe := ProviderHealthMiddleware(health)
+	record := RecordingMiddleware(opts.Recorder, handle)
+	if _, ok := api.(blockatlas.TxUtxoAPI); ok {
+		router.GET("/v1/"+handle+"/address/:address", cacheControl, observe, record, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				endpoint.GetTransactionsHistory(c, platform.WithFailover(p).(blockatlas.TxUtxoAPI), nil, opts)
+			}
 		})
-		router.GET("/v1/"+handle+"/xpub/:xpub", func(c *gin.Context) {
-			endpoint.GetTransactionsByXpub(c, txUtxoAPI)
+		router.GET("/v1/"+handle+"/xpub/:xpub", cacheControl, observe, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				tokenTxAPI, _ := p.(blockatlas.TokenTxAPI)
+				endpoint.GetTransactionsByXpub(c, platform.WithFailover(p).(blockatlas.TxUtxoAPI), tokenTxAPI, opts)
+			}
 		})
-		router.GET("/v2/"+handle+"/transactions/xpub/:xpub", func(c *gin.Context) {
-			endpoint.GetTransactionsByXpub(c, txUtxoAPI)
+		router.GET("/v2/"+handle+"/transactions/xpub/:xpub", cacheControl, observe, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				tokenTxAPI, _ := p.(blockatlas.TokenTxAPI)
+				endpoint.GetTransactionsByXpub(c, platform.WithFailover(p).(blockatlas.TxUtxoAPI), tokenTxAPI, opts)
+			}
 		})
+		router.GET("/v2/"+handle+"/summary/:address", cacheControl, observe, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				endpoint.GetTransactionsSummary(c, platform.WithFailover(p).(blockatlas.TxUtxoAPI))
+			}
+		})
+		if _, ok := api.(blockatlas.XpubAddressesAPI); ok {
+			router.GET("/v2/"+handle+"/xpub/:xpub/addresses", cacheControl, observe, func(c *gin.Context) {
+				if p, ok := getPlatform(c, api, opts); ok {
+					endpoint.GetXpubAddresses(c, platform.WithFailover(p).(blockatlas.XpubAddressesAPI))
+				}
+			})
+		}
 		return
 	}
-	txAPI, okTxApi := api.(blockatlas.TxAPI)
-	tokenTxAPI, okTokenTxApi := api.(blockatlas.TokenTxAPI)
+	_, okTxApi := api.(blockatlas.TxAPI)
+	_, okTokenTxApi := api.(blockatlas.TokenTxAPI)
 	if okTxApi || okTokenTxApi {
-		router.GET("/v1/"+handle+"/:address", func(c *gin.Context) {
-			endpoint.GetTransactionsHistory(c, txAPI, tokenTxAPI)
+		router.GET("/v1/"+handle+"/:address", cacheControl, observe, record, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				txAPI, _ := platform.WithFailover(p).(blockatlas.TxAPI)
+				tokenTxAPI, _ := p.(blockatlas.TokenTxAPI)
+				endpoint.GetTransactionsHistory(c, txAPI, tokenTxAPI, opts)
+			}
 		})
-		router.GET("/v2/"+handle+"/transactions/:address", func(c *gin.Context) {
-			endpoint.GetTransactionsHistory(c, txAPI, tokenTxAPI)
+		router.GET("/v2/"+handle+"/transactions/:address", cacheControl, observe, record, func(c *gin.Context) {
+			if p, ok := getPlatform(c, api, opts); ok {
+				txAPI, _ := platform.WithFailover(p).(blockatlas.TxAPI)
+				tokenTxAPI, _ := p.(blockatlas.TokenTxAPI)
+				endpoint.GetTransactionsHistory(c, txAPI, tokenTxAPI, opts)
+			}
 		})
+		if okTxApi {
+			router.GET("/v2/"+handle+"/summary/:address", cacheControl, observe, func(c *gin.Context) {
+				if p, ok := getPlatform(c, api, opts); ok {
+					endpoint.GetTransactionsSummary(c, platform.WithFailover(p).(blockatlas.TxAPI))
+				}
+			})
+		}
+	}
+}
+
+// getPlatform resolves the platform serving the request from its network and upstream override
+func getPlatform(c *gin.Context, api blockatlas.Platform, opts endpoint.TxOptions) (blockatlas.Platform, bool) {
+	p, ok := endpoint.GetNetworkPlatform(c, api, platform.TestnetPlatforms)
+	if !ok {
+		return nil, false
+	}
+	return endpoint.GetUpstreamPlatform(c, p, opts.UpstreamKey, platform.InitBlockbookPlatform)
+}
+
+func RegisterHealthAPI(router gin.IRouter, sources map[string]endpoint.HealthSource) {
+	router.GET("/v2/health/providers", func(c *gin.Context) {
+		endpoint.GetProvidersHealth(c, sources)
+	})
+}
+
+func RegisterPrewarmAPI(router gin.IRouter, api blockatlas.Platform, prewarmer *endpoint.Prewarmer) {
+	if prewarmer == nil {
+		return
+	}
+	txAPI, ok := platform.WithFailover(api).(blockatlas.TxAPI)
+	if !ok {
+		return
 	}
+	router.POST("/v2/"+api.Coin().Handle+"/prewarm/:address", func(c *gin.Context) {
+		endpoint.PrewarmAddress(c, txAPI, prewarmer)
+	})
+}
+
+// RegisterLabelsAPI registers the label writes, disabled without a key
+func RegisterLabelsAPI(router gin.IRouter, api blockatlas.Platform, labelStore endpoint.TxLabelStore, key string) {
+	if labelStore == nil || key == "" {
+		return
+	}
+	handle := api.Coin().Handle
+	auth := LabelsAuthMiddleware(key)
+	router.POST("/v2/"+handle+"/labels/:address/:hash/:label", auth, func(c *gin.Context) {
+		endpoint.AddTxLabel(c, api.Coin(), labelStore)
+	})
+	router.DELETE("/v2/"+handle+"/labels/:address/:hash/:label", auth, func(c *gin.Context) {
+		endpoint.DeleteTxLabel(c, api.Coin(), labelStore)
+	})
+}
+
+// RegisterBalancesAPI registers the cached balances of the subscribed addresses, disabled without a database
+func RegisterBalancesAPI(router gin.IRouter, api blockatlas.Platform, store endpoint.BalanceStore) {
+	if store == nil {
+		return
+	}
+	router.GET("/v2/"+api.Coin().Handle+"/balances/:address", func(c *gin.Context) {
+		endpoint.GetAddressBalances(c, api.Coin(), store)
+	})
+}
+
+func RegisterDepositsAPI(router gin.IRouter, api blockatlas.Platform, opts endpoint.TxOptions) {
+	txAPI, okTxAPI := api.(blockatlas.TxAPI)
+	blockAPI, okBlockAPI := api.(blockatlas.BlockAPI)
+	if !okTxAPI || !okBlockAPI {
+		return
+	}
+	handle := api.Coin().Handle
+	router.GET("/v2/"+handle+"/deposits/:address", CacheControlMiddleware(GetMaxAge(api.Coin())), func(c *gin.Context) {
+		endpoint.GetDeposits(c, txAPI, blockAPI, platform.TrustedTokens, opts.MaxPages)
+	})
 }
 
 func RegisterBlockAPI(router gin.IRouter, api blockatlas.Platform) {
@@ -44,10 +148,13 @@ func RegisterBlockAPI(router gin.IRouter, api blockatlas.Platform) {
 		router.GET("/v2/"+handle+"/blocks/:block", func(c *gin.Context) {
 			endpoint.GetBlock(c, blockAPI)
 		})
+		router.GET("/v2/"+handle+"/block/latest", middleware.CacheMiddleware(GetMaxAge(api.Coin()), func(c *gin.Context) {
+			endpoint.GetLatestBlock(c, blockAPI)
+		}))
 	}
 }
 
-func RegisterTokensAPI(router gin.IRouter, api blockatlas.Platform) {
+func RegisterTokensAPI(router gin.IRouter, api blockatlas.Platform, opts endpoint.TxOptions) {
 	tokenAPI, ok := api.(blockatlas.TokensAPI)
 	if !ok {
 		return
@@ -59,6 +166,19 @@ func RegisterTokensAPI(router gin.IRouter, api blockatlas.Platform) {
 	router.GET("/v2/"+handle+"/tokens/:address/ids", func(c *gin.Context) {
 		endpoint.GetTokensIdsByAddress(c, tokenAPI)
 	})
+	if txAPI, ok := platform.WithFailover(api).(blockatlas.TxAPI); ok {
+		router.GET("/v2/"+handle+"/tokens/:address/transfers", func(c *gin.Context) {
+			endpoint.GetTokenTransferCounts(c, txAPI, opts.MaxPages)
+		})
+	}
+
+	balanceAPI, okBalanceAPI := api.(blockatlas.TokenBalanceAPI)
+	tokenTxAPI, okTokenTxAPI := api.(blockatlas.TokenTxAPI)
+	if okBalanceAPI && okTokenTxAPI {
+		router.GET("/v2/"+handle+"/tokens/:address/token/:token", func(c *gin.Context) {
+			endpoint.GetTokenDetails(c, balanceAPI, tokenTxAPI, opts)
+		})
+	}
 }
 
 func RegisterStakeAPI(router gin.IRouter, api blockatlas.Platform) {
@@ -82,30 +202,40 @@ func RegisterCollectionsAPI(router gin.IRouter, api blockatlas.CollectionsAPI) {
 	})
 }
 
-func RegisterBatchAPI(router gin.IRouter) {
+func RegisterBatchAPI(router gin.IRouter, stakeAPIs map[string]blockatlas.StakeAPI, collectionsAPIs blockatlas.CollectionsAPIs, txAPIs map[uint]blockatlas.TxAPI) {
 	router.GET("/v3/staking/list", middleware.CacheMiddleware(time.Hour*10, func(c *gin.Context) {
-		endpoint.GetStakeInfoForCoins(c, platform.StakeAPIs)
+		endpoint.GetStakeInfoForCoins(c, stakeAPIs)
 	}))
 	router.POST("/v2/staking/delegations", func(c *gin.Context) {
-		endpoint.GetStakeDelegationsWithAllInfoForBatch(c, platform.StakeAPIs)
+		endpoint.GetStakeDelegationsWithAllInfoForBatch(c, stakeAPIs)
 	})
 	router.POST("/v2/staking/list", middleware.CacheMiddleware(time.Hour, func(c *gin.Context) {
-		endpoint.GetStakeInfoForBatch(c, platform.StakeAPIs)
+		endpoint.GetStakeInfoForBatch(c, stakeAPIs)
 	}))
 	router.POST("/v4/collectibles/categories", func(c *gin.Context) {
-		endpoint.GetCollectionCategoriesFromList(c, platform.CollectionsAPIs)
+		endpoint.GetCollectionCategoriesFromList(c, collectionsAPIs)
+	})
+	router.POST("/v2/transactions/diff", func(c *gin.Context) {
+		endpoint.GetUnknownTransactions(c, txAPIs, platform.TrustedTokens)
 	})
 }
 
-func RegisterBasicAPI(router gin.IRouter) {
-	router.GET("/", endpoint.GetStatus)
+func RegisterBasicAPI(router gin.IRouter, defaultCoin blockatlas.Platform) {
+	if defaultCoin == nil {
+		router.GET("/", endpoint.GetStatus)
+		return
+	}
+	sample := defaultCoin.Coin()
+	router.GET("/", func(c *gin.Context) {
+		endpoint.GetRoot(c, sample)
+	})
 }
 
-func RegisterTokensIndexAPI(router gin.IRouter, instance tokenindexer.Instance) {
-	router.GET("/v3/tokens/new", func(c *gin.Context) {
+func RegisterTokensIndexAPI(router gin.IRouter, instance tokenindexer.Instance, staleness gin.HandlerFunc) {
+	router.GET("/v3/tokens/new", staleness, func(c *gin.Context) {
 		endpoint.GetNewTokens(c, instance)
 	})
-	router.POST("/v1/assets/associations", func(c *gin.Context) {
+	router.POST("/v1/assets/associations", staleness, func(c *gin.Context) {
 		endpoint.GetTokensByAddressV3(c, instance)
 	})
 }
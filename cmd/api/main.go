@@ -17,6 +17,7 @@ import (
 	_ "github.com/trustwallet/blockatlas/docs"
 	"github.com/trustwallet/blockatlas/internal"
 	"github.com/trustwallet/blockatlas/platform"
+	"github.com/trustwallet/blockatlas/services/spam"
 	"github.com/trustwallet/blockatlas/services/tokenindexer"
 )
 
@@ -46,22 +47,39 @@ func init() {
 	}
 
 	engine = internal.InitEngine(config.Default.Gin.Mode)
-	platform.Init(config.Default.Platform)
-
-	database, err = db.New(config.Default.Postgres.URL, config.Default.Postgres.Log)
+	engine.Use(api.RequestIDMiddleware())
+	inFlight := config.Default.API.InFlight
+	proxies, err := api.ParseTrustedProxies(inFlight.TrustedProxies)
 	if err != nil {
 		log.Fatal(err)
 	}
+	engine.Use(api.InFlightLimitMiddleware(api.NewInFlightLimiter(inFlight.Limit, inFlight.KeyLimit, inFlight.Keys, proxies)))
+	platform.Init(config.Default.Platform)
 
+	if config.Default.Postgres.URL == "" {
+		log.Warn("Postgres is not configured, tokens index and transaction labels APIs are disabled")
+	} else {
+		database, err = db.New(config.Default.Postgres.URL, config.Default.Postgres.Log)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tokenIndexer = tokenindexer.Init(database)
+	}
 	metrics.Setup(database)
 
-	tokenIndexer = tokenindexer.Init(database)
+	if spamConfig := config.Default.Spam; spamConfig.URL != "" {
+		go spam.RunRefresher(ctx, platform.SpamTokens, spamConfig.Tokens, spamConfig.URL, spamConfig.RefreshInterval)
+	}
 }
 
 func main() {
-	api.SetupTokensIndexAPI(engine, tokenIndexer)
+	if database != nil {
+		api.SetupTokensIndexAPI(ctx, engine, tokenIndexer)
+	}
 	api.SetupSwaggerAPI(engine)
-	api.SetupPlatformAPI(engine)
+	api.SetupPlatformAPI(engine, database)
+	api.SetupAdminAPI(engine)
+	api.SetupBatchAPI(engine)
 	api.SetupMetrics(engine)
 
 	golibsGin.SetupGracefulShutdown(ctx, port, engine)
@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/trustwallet/blockatlas/internal/metrics"
+
 	"github.com/trustwallet/golibs/network/middleware"
 
 	"github.com/trustwallet/blockatlas/platform"
@@ -12,6 +17,7 @@ import (
 
 	"github.com/trustwallet/blockatlas/services/tokenindexer"
 
+	"github.com/trustwallet/blockatlas/services/balance"
 	"github.com/trustwallet/blockatlas/services/notifier"
 
 	"github.com/trustwallet/blockatlas/config"
@@ -24,17 +30,20 @@ import (
 
 const (
 	defaultConfigPath = "../../config.yml"
+	shutdownTimeout   = time.Second * 5
 )
 
 var (
-	ctx      context.Context
-	cancel   context.CancelFunc
-	database *db.Instance
+	ctx         context.Context
+	cancel      context.CancelFunc
+	database    *db.Instance
+	retryQueues *internal.RetryQueues
 
 	transactions        = "transactions"
 	tokens              = "tokens"
 	subscriptions       = "subscriptions"
 	subscriptionsTokens = "subscriptions_tokens"
+	balances            = "balances"
 )
 
 func init() {
@@ -46,7 +55,7 @@ func init() {
 	if err := middleware.SetupSentry(config.Default.Sentry.DSN); err != nil {
 		log.Error(err)
 	}
-	internal.InitMQ(config.Default.Observer.Rabbitmq.URL)
+	internal.InitMQ(config.Default.Observer.Rabbitmq.URL, config.Default.Observer.Rabbitmq.Prefix)
 
 	var err error
 	database, err = db.New(config.Default.Postgres.URL, config.Default.Postgres.Log)
@@ -55,10 +64,21 @@ func init() {
 	}
 
 	tokenindexer.Init(database)
+	metrics.Setup(nil)
+
+	if len(config.Default.Consumer.RetryDelays) > 0 {
+		retryQueues, err = internal.InitRetryQueues(config.Default.Observer.Rabbitmq.URL, config.Default.Consumer.RetryDelays)
+		if err != nil {
+			log.Fatal("Failed to init MQ retry queues: ", err)
+		}
+	}
 }
 
 func main() {
-	defer mq.Close()
+	defer closeMQ()
+	if retryQueues != nil {
+		defer retryQueues.Close()
+	}
 
 	// RunTokenIndexerSubscribe requires to fetch data from token apis. Improve later
 	platform.Init(config.Default.Platform)
@@ -68,58 +88,155 @@ func main() {
 	// Special case options to avoid unknown deadlock on insert
 	subscriptionsOptions := mq.InitDefaultConsumerOptions(1)
 
+	consumers := make(map[mq.Queue]internal.QueueConsumer)
 	switch config.Default.Consumer.Service {
 	case transactions:
-		setupTransactionsConsumer(options, ctx)
+		setupTransactionsConsumer(consumers, options, ctx)
 	case subscriptions:
-		setupSubscriptionsConsumer(subscriptionsOptions, ctx)
+		setupSubscriptionsConsumer(consumers, subscriptionsOptions)
 	case subscriptionsTokens:
-		setupSubscriptionsTokensConsumer(options, ctx)
+		setupSubscriptionsTokensConsumer(consumers, options)
 	case tokens:
-		setupTokensConsumer(options, ctx)
+		setupTokensConsumer(consumers, options)
+	case balances:
+		setupBalancesConsumer(consumers)
 	default:
-		setupTransactionsConsumer(options, ctx)
-		setupSubscriptionsConsumer(subscriptionsOptions, ctx)
-		setupSubscriptionsTokensConsumer(options, ctx)
-		setupTokensConsumer(options, ctx)
+		setupTransactionsConsumer(consumers, options, ctx)
+		setupSubscriptionsConsumer(consumers, subscriptionsOptions)
+		setupSubscriptionsTokensConsumer(consumers, options)
+		setupTokensConsumer(consumers, options)
+		setupBalancesConsumer(consumers)
 	}
 
-	go mq.FatalWorker(time.Second * 10)
+	if port := config.Default.Consumer.MetricsPort; port != "" {
+		go serveMetrics(port)
+	}
 
-	middleware.SetupGracefulShutdown(time.Second * 5)
+	go mq.FatalWorker(time.Second * 10)
 
+	if err := internal.RunConsumers(consumers, ctx); err != nil {
+		log.Fatal("Failed to run consumers: ", err)
+	}
 	cancel()
+	log.Info("Shutdown timeout: ...", shutdownTimeout)
+	time.Sleep(shutdownTimeout)
 }
 
-func setupTransactionsConsumer(options mq.ConsumerOptions, ctx context.Context) {
-	go internal.RawTransactions.RunConsumer(internal.ConsumerDatabase{
+func setupTransactionsConsumer(consumers map[mq.Queue]internal.QueueConsumer, options mq.ConsumerOptions, ctx context.Context) {
+	if config.Default.Consumer.Bulk.Size > 0 {
+		setupBulkTransactionsConsumer(ctx)
+		return
+	}
+	var consumer mq.Consumer = internal.ConsumerDatabase{
 		Database: database,
 		Delivery: notifier.RunNotifier,
 		Tag:      transactions,
-	}, options, ctx)
+	}
+	if config.Default.Consumer.SequenceCheck {
+		consumer = internal.SequencedConsumer{Consumer: consumer, Store: database, Tag: transactions}
+	}
+	queue := internal.GetTransactionsQueue(config.Default.Consumer.Queue)
+	consumers[queue] = internal.QueueConsumer{Consumer: validated(withRetry(timed(consumer, queue), queue)), Options: options}
 }
 
-func setupSubscriptionsConsumer(options mq.ConsumerOptions, ctx context.Context) {
-	go internal.Subscriptions.RunConsumer(internal.ConsumerDatabase{
+// setupBulkTransactionsConsumer drains the transactions queue in batches, e.g. for backfills
+func setupBulkTransactionsConsumer(ctx context.Context) {
+	consumer := internal.BulkConsumer{
+		Database: database,
+		Delivery: notifier.RunNotifier,
+		Queue:    internal.GetTransactionsQueue(config.Default.Consumer.Queue),
+		Size:     config.Default.Consumer.Bulk.Size,
+		Wait:     config.Default.Consumer.Bulk.Wait,
+		MaxSize:  config.Default.Consumer.MaxMessageSize,
+	}
+	go func() {
+		if err := consumer.Run(config.Default.Observer.Rabbitmq.URL, ctx); err != nil && err != context.Canceled {
+			log.Fatal("Bulk transactions consumer: ", err)
+		}
+	}()
+}
+
+func setupSubscriptionsConsumer(consumers map[mq.Queue]internal.QueueConsumer, options mq.ConsumerOptions) {
+	var consumer mq.Consumer = internal.ConsumerDatabase{
 		Database: database,
 		Delivery: subscriber.RunSubscriber,
 		Tag:      subscriptions,
-	}, options, ctx)
+	}
+	// retries republish the signed message, the signature is checked last
+	if secret := config.Default.Consumer.Subscriptions.Secret; secret != "" {
+		consumer = internal.SignedConsumer{Consumer: consumer, Secret: secret}
+	}
+	consumers[internal.Subscriptions] = internal.QueueConsumer{Options: options, Consumer: validated(withRetry(timed(consumer, internal.Subscriptions), internal.Subscriptions))}
 }
 
-func setupSubscriptionsTokensConsumer(options mq.ConsumerOptions, ctx context.Context) {
-	go internal.SubscriptionsTokens.RunConsumer(tokenindexer.ConsumerIndexer{
+func setupSubscriptionsTokensConsumer(consumers map[mq.Queue]internal.QueueConsumer, options mq.ConsumerOptions) {
+	consumers[internal.SubscriptionsTokens] = internal.QueueConsumer{Options: options, Consumer: validated(withRetry(timed(tokenindexer.ConsumerIndexer{
 		Database:   database,
 		TokensAPIs: platform.TokensAPIs,
 		Delivery:   tokenindexer.RunTokenIndexerSubscribe,
 		Tag:        subscriptionsTokens,
-	}, options, ctx)
+	}, internal.SubscriptionsTokens), internal.SubscriptionsTokens))}
 }
 
-func setupTokensConsumer(options mq.ConsumerOptions, ctx context.Context) {
-	go internal.RawTokens.RunConsumer(internal.ConsumerDatabase{
+func setupTokensConsumer(consumers map[mq.Queue]internal.QueueConsumer, options mq.ConsumerOptions) {
+	consumers[internal.RawTokens] = internal.QueueConsumer{Options: options, Consumer: validated(withRetry(timed(internal.ConsumerDatabase{
 		Database: database,
 		Delivery: tokenindexer.RunTokenIndexer,
 		Tag:      tokens,
-	}, options, ctx)
+	}, internal.RawTokens), internal.RawTokens))}
+}
+
+// setupBalancesConsumer runs a single worker, the balances are rewound on out of order blocks
+func setupBalancesConsumer(consumers map[mq.Queue]internal.QueueConsumer) {
+	consumers[internal.RawBalances] = internal.QueueConsumer{Options: mq.InitDefaultConsumerOptions(1), Consumer: validated(withRetry(timed(balance.Consumer{
+		Store:           database,
+		CheckpointDepth: config.Default.Consumer.Balances.CheckpointDepth,
+		Tag:             balances,
+	}, internal.RawBalances), internal.RawBalances))}
+	consumers[internal.SubscriptionsBalances] = internal.QueueConsumer{Options: mq.InitDefaultConsumerOptions(1), Consumer: validated(withRetry(timed(balance.Seeder{
+		Store: database,
+		APIs:  platform.BalanceAPIs,
+	}, internal.SubscriptionsBalances), internal.SubscriptionsBalances))}
+}
+
+func closeMQ() {
+	if err := internal.CloseMQ(); err != nil {
+		log.Error("Failed to close MQ: ", err)
+	}
+}
+
+func validated(consumer mq.Consumer) mq.Consumer {
+	return internal.ValidatedConsumer{
+		Consumer: internal.VersionedConsumer{Consumer: consumer},
+		MaxSize:  config.Default.Consumer.MaxMessageSize,
+	}
+}
+
+// timed records the processing time of the messages by the consumer, retries excluded
+func timed(consumer mq.Consumer, queue mq.Queue) mq.Consumer {
+	return internal.TimedConsumer{Consumer: consumer, Queue: queue}
+}
+
+// serveMetrics exposes the consumer metrics to Prometheus
+func serveMetrics(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/"+strings.TrimPrefix(config.Default.Metrics.Path, "/"), promhttp.Handler())
+	if err := http.ListenAndServe(":"+port, mux); err != nil {
+		log.Error("Metrics server: ", err)
+	}
+}
+
+// withRetry delays the retries of failed messages when retry delays are configured
+func withRetry(consumer mq.Consumer, queue mq.Queue) mq.Consumer {
+	if retryQueues == nil {
+		return consumer
+	}
+	if err := retryQueues.Declare(queue); err != nil {
+		log.Fatal("Retry queues declare: ", queue, err)
+	}
+	return internal.RetryConsumer{
+		Consumer: consumer,
+		Queue:    queue,
+		Retrier:  retryQueues,
+	}
 }
@@ -41,7 +41,7 @@ func init() {
 		log.Error(err)
 	}
 
-	internal.InitMQ(config.Default.Observer.Rabbitmq.URL)
+	internal.InitMQ(config.Default.Observer.Rabbitmq.URL, config.Default.Observer.Rabbitmq.Prefix)
 	platform.Init(config.Default.Platform)
 
 	if len(platform.BlockAPIs) == 0 {
@@ -56,7 +56,11 @@ func init() {
 }
 
 func main() {
-	defer mq.Close()
+	defer func() {
+		if err := internal.CloseMQ(); err != nil {
+			log.Error("Failed to close MQ: ", err)
+		}
+	}()
 	var (
 		wg          sync.WaitGroup
 		coinCancel  = make(map[string]context.CancelFunc)
@@ -84,6 +88,12 @@ func main() {
 			MaxBlocks:             maxBlocks,
 			StopChannel:           stopChannel,
 			Database:              database,
+			TrustedTokens:         platform.TrustedTokens,
+			SubscribedOnly:        config.Default.Observer.SubscribedOnly,
+		}
+		// isolated coins skip the exchange, the tokens indexer and the balances still get their transactions
+		if queue, ok := config.Default.Observer.Queues[coin.Handle]; ok {
+			params.TransactionsQueues = []mq.Queue{internal.GetTransactionsQueue(queue), internal.RawTokens, internal.RawBalances}
 		}
 
 		go parser.RunParser(params, ctx)
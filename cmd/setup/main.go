@@ -23,7 +23,7 @@ func init() {
 	_, confPath := internal.ParseArgs("", defaultConfigPath)
 
 	internal.InitConfig(confPath)
-	internal.InitMQ(config.Default.Observer.Rabbitmq.URL)
+	internal.InitMQ(config.Default.Observer.Rabbitmq.URL, config.Default.Observer.Rabbitmq.Prefix)
 	platform.Init(config.Default.Platform)
 
 	var err error
@@ -53,16 +53,19 @@ func main() {
 		internal.RawTransactions,
 		internal.Subscriptions,
 		internal.SubscriptionsTokens,
+		internal.SubscriptionsBalances,
 		internal.RawTokens,
-		internal.Subscriptions,
+		internal.RawBalances,
+		internal.DeadLetters,
+	}
+	for _, queue := range config.Default.Observer.Queues {
+		queues = append(queues, internal.GetTransactionsQueue(queue))
 	}
-	for _, queue := range queues {
-		if err := queue.Declare(); err != nil {
-			log.Fatal("Queue declare: ", queue, err)
-		}
+	if _, err := internal.DeclareQueues(config.Default.Observer.Rabbitmq.URL, queues); err != nil {
+		log.Fatal("Queue declare: ", err)
 	}
 
-	if err := internal.RawTransactionsExchange.Bind([]mq.Queue{internal.RawTokens, internal.RawTransactions}); err != nil {
+	if err := internal.RawTransactionsExchange.Bind([]mq.Queue{internal.RawTokens, internal.RawTransactions, internal.RawBalances}); err != nil {
 		log.Fatal("Transactions Exchange bind: ", err)
 	}
 
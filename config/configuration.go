@@ -13,8 +13,71 @@ type Configuration struct {
 	Gin struct {
 		Mode string `mapstructure:"mode"`
 	} `mapstructure:"gin"`
+	Log struct {
+		Level  string `mapstructure:"level"`
+		Format string `mapstructure:"format"`
+	} `mapstructure:"log"`
 	Platform []string `mapstructure:"platform"`
 	RestAPI  string   `mapstructure:"rest_api"`
+	API      struct {
+		Coins            []string `mapstructure:"coins"`
+		DefaultCoin      string   `mapstructure:"default_coin"`
+		MaxProviderPages int      `mapstructure:"max_provider_pages"`
+		UpstreamKey      string   `mapstructure:"upstream_override_key"`
+		// LabelsKey authorizes the label writes, empty disables them
+		LabelsKey string `mapstructure:"labels_key"`
+		// SharedAddresses lists by coin handle the deposit addresses requiring the required_memo param
+		SharedAddresses map[string][]string `mapstructure:"shared_addresses"`
+		// BlockedSenders lists by coin handle the senders of spam campaigns dropped from the histories
+		BlockedSenders map[string][]string `mapstructure:"blocked_senders"`
+		// Enrichments lists in order the enrichers applied to the transactions, empty applies the defaults
+		Enrichments []string `mapstructure:"enrichments"`
+		Prewarm     struct {
+			MaxConcurrent int           `mapstructure:"max_concurrent"`
+			Interval      time.Duration `mapstructure:"interval"`
+		} `mapstructure:"prewarm"`
+		FullHistory struct {
+			MaxPages        int           `mapstructure:"max_pages"`
+			MaxTransactions int           `mapstructure:"max_transactions"`
+			Deadline        time.Duration `mapstructure:"deadline"`
+		} `mapstructure:"full_history"`
+		Backfill struct {
+			// Key authorizes requests with max_pages above max_provider_pages, empty disables it
+			Key      string `mapstructure:"key"`
+			MaxPages int    `mapstructure:"max_pages"`
+		} `mapstructure:"backfill"`
+		Recording struct {
+			// Dir receives the recordings of the address requests, empty disables them
+			Dir string `mapstructure:"dir"`
+		} `mapstructure:"recording"`
+		DataStaleness struct {
+			// Threshold of messages waiting in rawTokens from which the tokens index responses are lagging, 0 disables the hint
+			Threshold int           `mapstructure:"threshold"`
+			Interval  time.Duration `mapstructure:"interval"`
+		} `mapstructure:"data_staleness"`
+		InFlight struct {
+			// Limit bounds the concurrent requests by IP, 0 disables the limits
+			Limit int `mapstructure:"limit"`
+			// KeyLimit bounds the concurrent requests of the clients sending one of Keys in the X-API-Key header
+			KeyLimit int      `mapstructure:"key_limit"`
+			Keys     []string `mapstructure:"keys"`
+			// TrustedProxies are the IPs or CIDR ranges whose X-Forwarded-For header is trusted
+			TrustedProxies []string `mapstructure:"trusted_proxies"`
+		} `mapstructure:"in_flight"`
+		Admin struct {
+			// Operators maps the operator names to their X-Admin-Key keys
+			Operators map[string]string `mapstructure:"operators"`
+		} `mapstructure:"admin"`
+		MemoryCache struct {
+			// Size bounds the entries of the memory cache used without Postgres
+			Size int `mapstructure:"size"`
+		} `mapstructure:"memory_cache"`
+		CacheControl struct {
+			Min   time.Duration            `mapstructure:"min"`
+			Max   time.Duration            `mapstructure:"max"`
+			Coins map[string]time.Duration `mapstructure:"coins"`
+		} `mapstructure:"cache_control"`
+	} `mapstructure:"api"`
 	Observer struct {
 		FetchBlocksInterval time.Duration `mapstructure:"fetch_blocks_interval"`
 		BlockPoll           struct {
@@ -24,7 +87,13 @@ type Configuration struct {
 		} `mapstructure:"block_poll"`
 		Rabbitmq struct {
 			URL string `mapstructure:"url"`
+			// Prefix namespaces the queues and exchanges of the environment when the broker is shared
+			Prefix string `mapstructure:"prefix"`
 		} `mapstructure:"rabbitmq"`
+		// Queues maps coin handles to the dedicated queue their transactions are published to
+		Queues map[string]string `mapstructure:"queues"`
+		// SubscribedOnly publishes only the transactions touching a subscribed address
+		SubscribedOnly bool `mapstructure:"subscribed_only"`
 	} `mapstructure:"observer"`
 	Postgres struct {
 		URL string `mapstructure:"url"`
@@ -206,6 +275,21 @@ type Configuration struct {
 	Oasis struct {
 		API string `mapstructure:"api"`
 	} `mapstructure:"oasis"`
+	// Testnet maps coin handles to the API of their test network
+	Testnet map[string]string `mapstructure:"testnet"`
+	// Failover maps coin handles to secondary APIs tried when the primary one fails
+	Failover map[string][]string `mapstructure:"failover"`
+	// TrustedTokens lists the token ids whose transactions keep their memos and skip the token filter
+	TrustedTokens []string `mapstructure:"trusted_tokens"`
+	// MaxResponseSize bounds the body of the provider responses in bytes, 0 disables the limit
+	MaxResponseSize int64 `mapstructure:"max_response_size"`
+
+	Spam struct {
+		Tokens          []string      `mapstructure:"tokens"`
+		URL             string        `mapstructure:"url"`
+		RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	} `mapstructure:"spam"`
+
 	Sentry struct {
 		DSN string `mapstructure:"dsn"`
 	} `mapstructure:"sentry"`
@@ -213,9 +297,30 @@ type Configuration struct {
 		Path string `mapstructure:"path"`
 	} `mapstructure:"metrics"`
 	Consumer struct {
-		Service  string `mapstructure:"service"`
-		Prefetch int    `mapstructure:"prefetch"`
-		Workers  int    `mapstructure:"workers"`
+		Service        string `mapstructure:"service"`
+		Prefetch       int    `mapstructure:"prefetch"`
+		Workers        int    `mapstructure:"workers"`
+		MaxMessageSize int    `mapstructure:"max_message_size"`
+		SequenceCheck  bool   `mapstructure:"sequence_check"`
+		// RetryDelays is the delay before each retry of a failed message
+		RetryDelays []time.Duration `mapstructure:"retry_delays"`
+		// Queue the transactions service consumes, rawTransactions if empty
+		Queue string `mapstructure:"queue"`
+		// MetricsPort serves the Prometheus metrics of the consumer, disabled if empty
+		MetricsPort string `mapstructure:"metrics_port"`
+		// Bulk consumes the transactions service in batches, acked together
+		Bulk struct {
+			Size int           `mapstructure:"size"`
+			Wait time.Duration `mapstructure:"wait"`
+		} `mapstructure:"bulk"`
+		Subscriptions struct {
+			// Secret verifies the signatures of the subscriptions messages, empty accepts unsigned messages
+			Secret string `mapstructure:"secret"`
+		} `mapstructure:"subscriptions"`
+		Balances struct {
+			// CheckpointDepth is the number of blocks the balances can be rewound on reorgs
+			CheckpointDepth uint64 `mapstructure:"checkpoint_depth"`
+		} `mapstructure:"balances"`
 	} `mapstructure:"consumer"`
 }
 
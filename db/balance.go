@@ -0,0 +1,143 @@
+package db
+
+import (
+	"time"
+
+	"github.com/trustwallet/blockatlas/db/models"
+	"gorm.io/gorm/clause"
+)
+
+// GetAddressBalances returns the cached balances of a subscribed address by its coin_address id
+func (i *Instance) GetAddressBalances(addressID string) ([]models.AddressBalance, error) {
+	var balances []models.AddressBalance
+	if err := i.Gorm.Order("asset").Find(&balances, "address = ?", addressID).Error; err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// AddBalanceDeltas stores the deltas and updates the balances they apply to.
+// The delta of an already stored transaction replaces it, deltas behind the balance checkpoint are dropped.
+func (i *Instance) AddBalanceDeltas(deltas []models.BalanceDelta) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	balances := make([]models.AddressBalance, 0)
+	addresses := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, delta := range deltas {
+		if !seen[delta.Address+"/"+delta.Asset] {
+			seen[delta.Address+"/"+delta.Asset] = true
+			balances = append(balances, models.AddressBalance{
+				Address:    delta.Address,
+				Asset:      delta.Asset,
+				Coin:       delta.Coin,
+				Balance:    "0",
+				Checkpoint: "0",
+			})
+		}
+		if !seen[delta.Address] {
+			seen[delta.Address] = true
+			addresses = append(addresses, delta.Address)
+		}
+	}
+	return i.Transaction(func(tx *Instance) error {
+		if err := tx.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&balances).Error; err != nil {
+			return err
+		}
+		if err := tx.Gorm.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "address"}, {Name: "asset"}, {Name: "hash"}},
+			DoUpdates: clause.AssignmentColumns([]string{"coin", "block", "delta"}),
+		}).Create(&deltas).Error; err != nil {
+			return err
+		}
+		if err := tx.dropCheckpointedDeltas(addresses); err != nil {
+			return err
+		}
+		return tx.updateBalances(addresses)
+	})
+}
+
+// SeedBalances sets the checkpoints of the balances, e.g. read from the provider when the address is subscribed,
+// the deltas up to their checkpoint block are dropped
+func (i *Instance) SeedBalances(balances []models.AddressBalance) error {
+	if len(balances) == 0 {
+		return nil
+	}
+	addresses := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, balance := range balances {
+		if !seen[balance.Address] {
+			seen[balance.Address] = true
+			addresses = append(addresses, balance.Address)
+		}
+	}
+	return i.Transaction(func(tx *Instance) error {
+		if err := tx.Gorm.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "address"}, {Name: "asset"}},
+			DoUpdates: clause.AssignmentColumns([]string{"coin", "checkpoint", "checkpoint_block"}),
+		}).Create(&balances).Error; err != nil {
+			return err
+		}
+		if err := tx.dropCheckpointedDeltas(addresses); err != nil {
+			return err
+		}
+		return tx.updateBalances(addresses)
+	})
+}
+
+// RewindBalances drops the deltas of the coin from the block on, e.g. blocks replaced by a reorg,
+// and updates the balances they applied to
+func (i *Instance) RewindBalances(coin uint, fromBlock uint64) error {
+	return i.Transaction(func(tx *Instance) error {
+		var addresses []string
+		if err := tx.Gorm.Model(&models.BalanceDelta{}).
+			Distinct("address").
+			Where("coin = ? AND block >= ?", coin, fromBlock).
+			Pluck("address", &addresses).Error; err != nil {
+			return err
+		}
+		if len(addresses) == 0 {
+			return nil
+		}
+		if err := tx.Gorm.Delete(&models.BalanceDelta{}, "coin = ? AND block >= ?", coin, fromBlock).Error; err != nil {
+			return err
+		}
+		return tx.updateBalances(addresses)
+	})
+}
+
+// CheckpointBalances folds the deltas of the coin up to the block into the checkpoints of their balances,
+// the balances can't be rewound past it anymore
+func (i *Instance) CheckpointBalances(coin uint, block uint64) error {
+	return i.Transaction(func(tx *Instance) error {
+		if err := tx.Gorm.Exec(`UPDATE address_balances b SET checkpoint = b.checkpoint + s.total
+			FROM (SELECT address, asset, SUM(delta) AS total FROM balance_deltas WHERE coin = ? AND block <= ? GROUP BY address, asset) s
+			WHERE b.address = s.address AND b.asset = s.asset`,
+			coin, block).Error; err != nil {
+			return err
+		}
+		if err := tx.Gorm.Delete(&models.BalanceDelta{}, "coin = ? AND block <= ?", coin, block).Error; err != nil {
+			return err
+		}
+		return tx.Gorm.Model(&models.AddressBalance{}).
+			Where("coin = ? AND checkpoint_block < ?", coin, block).
+			Update("checkpoint_block", block).Error
+	})
+}
+
+// dropCheckpointedDeltas drops the deltas of the addresses already counted by their balance checkpoint
+func (i *Instance) dropCheckpointedDeltas(addresses []string) error {
+	return i.Gorm.Exec(`DELETE FROM balance_deltas d USING address_balances b
+		WHERE d.address = b.address AND d.asset = b.asset AND d.block <= b.checkpoint_block AND d.address IN ?`,
+		addresses).Error
+}
+
+// updateBalances sets the balances of the addresses to their checkpoint plus their deltas
+func (i *Instance) updateBalances(addresses []string) error {
+	return i.Gorm.Exec(`UPDATE address_balances b
+		SET balance = b.checkpoint + COALESCE((SELECT SUM(d.delta) FROM balance_deltas d WHERE d.address = b.address AND d.asset = b.asset), 0),
+		updated_at = ?
+		WHERE b.address IN ?`,
+		time.Now(), addresses).Error
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Cache is the in-memory caching used alongside the database
+type Cache interface {
+	MemorySet(key string, data []byte, exp time.Duration) error
+	MemoryGet(key string) ([]byte, error)
+}
+
+var (
+	_ Cache = (*Instance)(nil)
+	_ Cache = (*LRUCache)(nil)
+)
+
+// LRUCache is a size bounded Cache evicting the least recently used entries,
+// used as a local fallback when no database is configured
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// MemorySet stores the data for the given duration, a non positive duration never expires
+func (c *LRUCache) MemorySet(key string, data []byte, exp time.Duration) error {
+	entry := &lruEntry{key: key, data: data}
+	if exp > 0 {
+		entry.expiresAt = time.Now().Add(exp)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRUCache) MemoryGet(key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, errors.New("not found")
+	}
+	c.order.MoveToFront(element)
+	return entry.data, nil
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache_Eviction(t *testing.T) {
+	c := NewLRUCache(2)
+	assert.Nil(t, c.MemorySet("a", []byte("1"), 0))
+	assert.Nil(t, c.MemorySet("b", []byte("2"), 0))
+
+	_, err := c.MemoryGet("a")
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.MemorySet("c", []byte("3"), 0))
+
+	_, err = c.MemoryGet("b")
+	assert.NotNil(t, err)
+	data, err := c.MemoryGet("a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("1"), data)
+	data, err = c.MemoryGet("c")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("3"), data)
+}
+
+func TestLRUCache_Expiration(t *testing.T) {
+	c := NewLRUCache(10)
+	assert.Nil(t, c.MemorySet("a", []byte("1"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, err := c.MemoryGet("a")
+	assert.NotNil(t, err)
+}
+
+func TestLRUCache_Concurrent(t *testing.T) {
+	c := NewLRUCache(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := string(rune('a' + i))
+			_ = c.MemorySet(key, []byte(key), 0)
+			_, _ = c.MemoryGet(key)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 8, c.order.Len())
+	assert.Len(t, c.entries, 8)
+}
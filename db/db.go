@@ -16,6 +16,8 @@ import (
 type Instance struct {
 	Gorm        *gorm.DB
 	MemoryCache *gocache.Cache
+	// parentCache is the cache of the instance a transaction was started on, read past MemoryCache
+	parentCache *gocache.Cache
 }
 
 func New(url string, log bool) (*Instance, error) {
@@ -43,6 +45,10 @@ func Setup(db *gorm.DB) error {
 		&models.Asset{},
 		&models.Subscription{},
 		&models.SubscriptionsAssetAssociation{},
+		&models.ConsumerSequence{},
+		&models.TxLabel{},
+		&models.AddressBalance{},
+		&models.BalanceDelta{},
 	)
 }
 
@@ -53,8 +59,39 @@ func (i *Instance) MemorySet(key string, data []byte, exp time.Duration) error {
 
 func (i *Instance) MemoryGet(key string) ([]byte, error) {
 	res, ok := i.MemoryCache.Get(key)
+	if !ok && i.parentCache != nil {
+		res, ok = i.parentCache.Get(key)
+	}
 	if !ok {
 		return nil, errors.New("not found")
 	}
 	return res.([]byte), nil
 }
+
+// Transaction runs fn on an instance bound to a single database transaction, committed if fn succeeds.
+// Memory cache writes are kept aside and only applied on commit, a rollback must not leave cached rows behind.
+func (i *Instance) Transaction(fn func(tx *Instance) error) error {
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	err := i.Gorm.Transaction(func(tx *gorm.DB) error {
+		return fn(&Instance{Gorm: tx, MemoryCache: cache, parentCache: i.MemoryCache})
+	})
+	if err != nil {
+		return err
+	}
+	commitCache(i.MemoryCache, cache, time.Now())
+	return nil
+}
+
+// commitCache copies the entries written in a transaction to the cache with their remaining time to live
+func commitCache(cache, written *gocache.Cache, now time.Time) {
+	for key, item := range written.Items() {
+		exp := gocache.NoExpiration
+		if item.Expiration > 0 {
+			exp = time.Unix(0, item.Expiration).Sub(now)
+			if exp <= 0 {
+				continue
+			}
+		}
+		cache.Set(key, item.Object, exp)
+	}
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstance_MemoryGet(t *testing.T) {
+	parent := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	parent.Set("cached", []byte("parent"), gocache.NoExpiration)
+	tx := &Instance{MemoryCache: gocache.New(gocache.NoExpiration, gocache.NoExpiration), parentCache: parent}
+
+	data, err := tx.MemoryGet("cached")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("parent"), data)
+
+	assert.Nil(t, tx.MemorySet("cached", []byte("tx"), gocache.NoExpiration))
+	data, err = tx.MemoryGet("cached")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("tx"), data)
+
+	_, err = tx.MemoryGet("missing")
+	assert.NotNil(t, err)
+}
+
+func TestCommitCache(t *testing.T) {
+	cache := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	written := gocache.New(gocache.NoExpiration, gocache.NoExpiration)
+	written.Set("forever", []byte("1"), gocache.NoExpiration)
+	written.Set("expiring", []byte("2"), time.Hour)
+	written.Set("expired", []byte("3"), time.Millisecond)
+
+	commitCache(cache, written, time.Now().Add(time.Second))
+	_, exp, ok := cache.GetWithExpiration("forever")
+	assert.True(t, ok)
+	assert.True(t, exp.IsZero())
+
+	_, exp, ok = cache.GetWithExpiration("expiring")
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), exp, time.Minute)
+
+	_, ok = cache.Get("expired")
+	assert.False(t, ok)
+}
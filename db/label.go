@@ -0,0 +1,27 @@
+package db
+
+import (
+	"github.com/trustwallet/blockatlas/db/models"
+	"gorm.io/gorm/clause"
+)
+
+func (i *Instance) AddTxLabel(label models.TxLabel) error {
+	return i.Gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&label).Error
+}
+
+func (i *Instance) DeleteTxLabel(label models.TxLabel) error {
+	return i.Gorm.Delete(&models.TxLabel{},
+		"coin = ? AND address = ? AND hash = ? AND label = ?",
+		label.Coin, label.Address, label.Hash, label.Label,
+	).Error
+}
+
+func (i *Instance) GetTxLabels(coin uint, address string) ([]models.TxLabel, error) {
+	var labels []models.TxLabel
+	if err := i.Gorm.
+		Order("created_at").
+		Find(&labels, "coin = ? AND address = ?", coin, address).Error; err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
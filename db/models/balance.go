@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type (
+	// AddressBalance is the balance of an asset of a subscribed address derived from the observed transactions,
+	// the checkpoint plus the deltas stored since. Amounts are in the smallest unit of the asset
+	AddressBalance struct {
+		UpdatedAt time.Time
+		// Address is the coin_address subscription id
+		Address string `gorm:"primary_key:true; type:varchar(256)"`
+		Asset   string `gorm:"primary_key:true; type:varchar(128)"`
+		Coin    uint   `gorm:"index"`
+		Balance string `gorm:"type:numeric; not null; default:0"`
+		// Checkpoint is the balance up to CheckpointBlock, its deltas are no longer kept
+		Checkpoint      string `gorm:"type:numeric; not null; default:0"`
+		CheckpointBlock uint64
+	}
+
+	// BalanceDelta is the net effect of a transaction on a balance, kept until the balance checkpoint passes its block
+	BalanceDelta struct {
+		Address string `gorm:"primary_key:true; type:varchar(256)"`
+		Asset   string `gorm:"primary_key:true; type:varchar(128)"`
+		Hash    string `gorm:"primary_key:true; type:varchar(128)"`
+		Coin    uint   `gorm:"index:idx_balance_deltas_coin_block"`
+		Block   uint64 `gorm:"index:idx_balance_deltas_coin_block"`
+		Delta   string `gorm:"type:numeric; not null"`
+	}
+)
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// TxLabel is a label attached by a user to a transaction of an address
+type TxLabel struct {
+	CreatedAt time.Time
+	Coin      uint   `gorm:"primary_key:true"`
+	Address   string `gorm:"primary_key:true; type:varchar(128)"`
+	Hash      string `gorm:"primary_key:true; type:varchar(128)"`
+	Label     string `gorm:"primary_key:true; type:varchar(64)"`
+}
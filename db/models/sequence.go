@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ConsumerSequence is the last sequence processed by a consumer for a coin
+type ConsumerSequence struct {
+	UpdatedAt time.Time
+	Consumer  string `gorm:"primary_key:true; type:varchar(64)"`
+	Coin      uint   `gorm:"primary_key:true"`
+	Sequence  uint64
+	// BlockHash is the hash of the block of the sequence, see internal.BlockHash
+	BlockHash string `gorm:"type:varchar(64)"`
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"time"
+
+	"github.com/trustwallet/blockatlas/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (i *Instance) GetConsumerSequence(consumer string, coin uint) (uint64, error) {
+	var sequence models.ConsumerSequence
+	if err := i.Gorm.
+		Find(&sequence, "consumer = ? AND coin = ?", consumer, coin).Error; err != nil {
+		return 0, err
+	}
+	return sequence.Sequence, nil
+}
+
+// GetConsumerSequenceHash returns the last sequence stored with the hash of its block
+func (i *Instance) GetConsumerSequenceHash(consumer string, coin uint) (uint64, string, error) {
+	var sequence models.ConsumerSequence
+	if err := i.Gorm.
+		Find(&sequence, "consumer = ? AND coin = ?", consumer, coin).Error; err != nil {
+		return 0, "", err
+	}
+	return sequence.Sequence, sequence.BlockHash, nil
+}
+
+// SetConsumerSequence stores the sequence unless a higher one was already stored
+func (i *Instance) SetConsumerSequence(consumer string, coin uint, value uint64) error {
+	sequence := models.ConsumerSequence{
+		Consumer: consumer,
+		Coin:     coin,
+		Sequence: value,
+	}
+	return i.Gorm.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "consumer"},
+			{Name: "coin"},
+		},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"sequence":   gorm.Expr("GREATEST(consumer_sequences.sequence, excluded.sequence)"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&sequence).Error
+}
+
+// SetConsumerSequenceHash stores the sequence with the hash of its block unless a higher one was already stored
+func (i *Instance) SetConsumerSequenceHash(consumer string, coin uint, value uint64, hash string) error {
+	sequence := models.ConsumerSequence{
+		Consumer:  consumer,
+		Coin:      coin,
+		Sequence:  value,
+		BlockHash: hash,
+	}
+	return i.Gorm.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "consumer"},
+			{Name: "coin"},
+		},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_hash": gorm.Expr("CASE WHEN excluded.sequence >= consumer_sequences.sequence THEN excluded.block_hash ELSE consumer_sequences.block_hash END"),
+			"sequence":   gorm.Expr("GREATEST(consumer_sequences.sequence, excluded.sequence)"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&sequence).Error
+}
+
+// RewindConsumerSequence stores the sequence with the hash of its block even if a higher one was already stored
+func (i *Instance) RewindConsumerSequence(consumer string, coin uint, value uint64, hash string) error {
+	sequence := models.ConsumerSequence{
+		Consumer:  consumer,
+		Coin:      coin,
+		Sequence:  value,
+		BlockHash: hash,
+	}
+	return i.Gorm.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "consumer"},
+			{Name: "coin"},
+		},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"sequence":   value,
+			"block_hash": hash,
+			"updated_at": time.Now(),
+		}),
+	}).Create(&sequence).Error
+}
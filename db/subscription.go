@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// CreateSubscriptions stores the subscriptions, adding an already stored address is a no-op
 func (i *Instance) CreateSubscriptions(addresses []types.Subscription) error {
 	if len(addresses) == 0 {
 		return nil
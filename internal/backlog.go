@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+// QueueInspector reads the depth of queues over a dedicated connection, golibs mq doesn't expose inspections.
+// Queue names get the prefix of the environment.
+type QueueInspector struct {
+	URL    string
+	Prefix string
+}
+
+// InspectQueue returns the number of messages ready for the consumers of the queue
+func (i QueueInspector) InspectQueue(name string) (int, error) {
+	conn, err := amqp.Dial(i.URL)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	channel, err := conn.Channel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+	queue, err := channel.QueueInspect(prefixedWith(i.Prefix, name))
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return 0, ErrQueueNotFound
+	}
+	return queue.Messages, err
+}
+
+// QueueBacklog keeps the latest depth of a queue, for the API to tell clients the data the queue feeds may lag
+type QueueBacklog struct {
+	Inspector QueueInspector
+	Queue     string
+
+	mu    sync.RWMutex
+	depth int
+	known bool
+}
+
+// Depth returns the latest depth of the queue, unknown before the first inspection and after a failed one
+func (b *QueueBacklog) Depth() (int, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.depth, b.known
+}
+
+// Run inspects the queue at every interval until the context is done
+func (b *QueueBacklog) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		depth, err := b.Inspector.InspectQueue(b.Queue)
+		if err != nil {
+			log.WithFields(log.Fields{"queue": b.Queue, "error": err}).Warn("Failed to inspect queue")
+		}
+		b.mu.Lock()
+		b.depth, b.known = depth, err == nil
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/blockatlas/db"
+	"github.com/trustwallet/blockatlas/internal/metrics"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+// BulkConsumer processes the messages of a queue in batches.
+// A batch is acked at once when it succeeds and all its messages are requeued when it fails.
+// Meant to drain a queue quickly, e.g. for backfills, messages of a batch are processed sequentially.
+// The deliveries only read the database, a requeued batch is processed again whole.
+type BulkConsumer struct {
+	Database *db.Instance
+	Delivery func(*db.Instance, amqp.Delivery) error
+	Queue    mq.Queue
+	// Size is the maximum number of messages of a batch
+	Size int
+	// Wait is how long a batch waits to be filled after its first message
+	Wait time.Duration
+	// MaxSize moves messages bigger than MaxSize bytes to the DeadLetters queue, disabled if not positive
+	MaxSize int
+}
+
+// ErrDeliveriesClosed is returned by BulkConsumer.Run when the broker closes the channel or the connection
+var ErrDeliveriesClosed = errors.New("MQ deliveries channel closed")
+
+// Run consumes the queue on a dedicated channel until the context is done or the deliveries channel closes,
+// golibs mq acks messages one by one
+func (c BulkConsumer) Run(url string, ctx context.Context) error {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	channel, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer channel.Close()
+	if err := channel.Qos(c.Size, 0, false); err != nil {
+		return err
+	}
+	deliveries, err := channel.Consume(string(c.Queue), "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		batch, open := collectBatch(ctx, deliveries, c.Size, c.Wait)
+		if !open {
+			// the messages of a closed channel can't be acked, the broker redelivers them
+			return ErrDeliveriesClosed
+		}
+		if len(batch) == 0 {
+			return ctx.Err()
+		}
+		if err := c.Process(batch); err != nil {
+			log.WithFields(log.Fields{"queue": c.Queue, "size": len(batch), "error": err}).Error("Failed to process MQ batch")
+		}
+	}
+}
+
+// Process runs the batch then acks it, or nacks it with requeue on failure.
+// Invalid messages don't fail the batch, they are moved to the DeadLetters queue once the batch succeeded
+// so that a requeued batch doesn't dead letter them again.
+func (c BulkConsumer) Process(batch []amqp.Delivery) error {
+	start := time.Now()
+	invalid, err := c.process(batch)
+	for _, msg := range invalid {
+		if err != nil {
+			break
+		}
+		err = DeadLetter(msg)
+	}
+	metrics.ObserveConsumerCallback(string(c.Queue), time.Since(start), err != nil)
+	return settleBatch(batch, err)
+}
+
+// process runs the valid messages of the batch, it returns the invalid ones
+func (c BulkConsumer) process(batch []amqp.Delivery) ([]amqp.Delivery, error) {
+	invalid := make([]amqp.Delivery, 0)
+	for _, msg := range batch {
+		if err := validateBulkDelivery(msg, c.MaxSize); err != nil {
+			DeliveryLogger(msg).WithFields(log.Fields{"queue": c.Queue, "message_id": msg.MessageId, "error": err}).Error("Rejected MQ message")
+			invalid = append(invalid, msg)
+			continue
+		}
+		if err := c.Delivery(c.Database, msg); err != nil {
+			return invalid, err
+		}
+	}
+	return invalid, nil
+}
+
+func validateBulkDelivery(msg amqp.Delivery, maxSize int) error {
+	if err := ValidateDelivery(msg, maxSize); err != nil {
+		return err
+	}
+	return CheckMessageVersion(msg)
+}
+
+// collectBatch reads up to size messages, waiting at most wait for the batch to fill once it has a message.
+// It reports whether the deliveries channel is still open.
+func collectBatch(ctx context.Context, deliveries <-chan amqp.Delivery, size int, wait time.Duration) ([]amqp.Delivery, bool) {
+	batch := make([]amqp.Delivery, 0, size)
+	var timeout <-chan time.Time
+	for len(batch) < size {
+		select {
+		case msg, ok := <-deliveries:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, msg)
+			if timeout == nil {
+				timer := time.NewTimer(wait)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+		case <-timeout:
+			return batch, true
+		case <-ctx.Done():
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+// settleBatch acks or nacks all the messages of the batch with the delivery tag of the last one,
+// the channel consuming them must not be shared
+func settleBatch(batch []amqp.Delivery, err error) error {
+	last := batch[len(batch)-1]
+	if err != nil {
+		if nackErr := last.Nack(true, true); nackErr != nil {
+			log.Error("Failed to nack MQ batch: ", nackErr)
+		}
+		return err
+	}
+	return last.Ack(true)
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db"
+)
+
+type acknowledgerMock struct {
+	acked, nacked []uint64
+	multiple      bool
+	requeue       bool
+}
+
+func (a *acknowledgerMock) Ack(tag uint64, multiple bool) error {
+	a.acked = append(a.acked, tag)
+	a.multiple = multiple
+	return nil
+}
+
+func (a *acknowledgerMock) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.nacked = append(a.nacked, tag)
+	a.multiple = multiple
+	a.requeue = requeue
+	return nil
+}
+
+func (a *acknowledgerMock) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func TestCollectBatch(t *testing.T) {
+	deliveries := make(chan amqp.Delivery, 5)
+	for i := 1; i <= 5; i++ {
+		deliveries <- amqp.Delivery{DeliveryTag: uint64(i)}
+	}
+	batch, open := collectBatch(context.Background(), deliveries, 3, time.Second)
+	assert.True(t, open)
+	assert.Len(t, batch, 3)
+	assert.Equal(t, uint64(3), batch[2].DeliveryTag)
+
+	start := time.Now()
+	batch, open = collectBatch(context.Background(), deliveries, 3, 50*time.Millisecond)
+	assert.True(t, open)
+	assert.Len(t, batch, 2)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	batch, open = collectBatch(ctx, deliveries, 3, time.Second)
+	assert.True(t, open)
+	assert.Empty(t, batch)
+
+	deliveries <- amqp.Delivery{DeliveryTag: 6}
+	close(deliveries)
+	batch, open = collectBatch(context.Background(), deliveries, 3, time.Second)
+	assert.False(t, open)
+	assert.Len(t, batch, 1)
+}
+
+func TestSettleBatch(t *testing.T) {
+	ack := &acknowledgerMock{}
+	batch := []amqp.Delivery{
+		{Acknowledger: ack, DeliveryTag: 1},
+		{Acknowledger: ack, DeliveryTag: 2},
+	}
+	assert.Nil(t, settleBatch(batch, nil))
+	assert.Equal(t, []uint64{2}, ack.acked)
+	assert.True(t, ack.multiple)
+
+	ack = &acknowledgerMock{}
+	batch[0].Acknowledger, batch[1].Acknowledger = ack, ack
+	assert.EqualError(t, settleBatch(batch, errors.New("deadlock")), "deadlock")
+	assert.Empty(t, ack.acked)
+	assert.Equal(t, []uint64{2}, ack.nacked)
+	assert.True(t, ack.multiple)
+	assert.True(t, ack.requeue)
+}
+
+func TestBulkConsumer_Process(t *testing.T) {
+	var delivered []uint64
+	consumer := BulkConsumer{
+		Delivery: func(_ *db.Instance, msg amqp.Delivery) error {
+			delivered = append(delivered, msg.DeliveryTag)
+			if string(msg.Body) == `{}` {
+				return errors.New("deadlock")
+			}
+			return nil
+		},
+		MaxSize: 8,
+	}
+	batch := []amqp.Delivery{
+		{DeliveryTag: 1, Body: []byte("[]")},
+		{DeliveryTag: 2, Body: []byte("too big to be processed")},
+		{DeliveryTag: 3, Body: []byte("[]")},
+	}
+	invalid, err := consumer.process(batch)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 3}, delivered)
+	assert.Len(t, invalid, 1)
+	assert.Equal(t, uint64(2), invalid[0].DeliveryTag)
+
+	// a failed batch is requeued whole, the invalid messages are dead lettered with the batch succeeding
+	ack := &acknowledgerMock{}
+	batch = append(batch, amqp.Delivery{DeliveryTag: 4, Body: []byte(`{}`)})
+	for i := range batch {
+		batch[i].Acknowledger = ack
+	}
+	assert.EqualError(t, consumer.Process(batch), "deadlock")
+	assert.Equal(t, []uint64{4}, ack.nacked)
+	assert.True(t, ack.requeue)
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/blockatlas/internal/metrics"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+var (
+	errEmptyMessage     = errors.New("empty message")
+	errMalformedMessage = errors.New("message is not a JSON object or array")
+)
+
+// ValidatedConsumer checks the size and format of messages before handing them to the consumer.
+// Invalid messages are moved to the DeadLetters queue instead of reaching the callback.
+type ValidatedConsumer struct {
+	mq.Consumer
+	MaxSize int
+}
+
+func (c ValidatedConsumer) Callback(msg amqp.Delivery) error {
+	if err := ValidateDelivery(msg, c.MaxSize); err != nil {
+		DeliveryLogger(msg).WithFields(log.Fields{
+			"message_id":   msg.MessageId,
+			"delivery_tag": msg.DeliveryTag,
+			"size":         len(msg.Body),
+			"error":        err,
+		}).Error("Rejected MQ message")
+		return DeadLetter(msg)
+	}
+	return c.Consumer.Callback(msg)
+}
+
+// SignedConsumer hands the payload of blockatlas.SignedMessage messages signed with the secret to the consumer.
+// Messages not signed with the secret are moved to the DeadLetters queue.
+type SignedConsumer struct {
+	mq.Consumer
+	Secret string
+}
+
+func (c SignedConsumer) Callback(msg amqp.Delivery) error {
+	payload, err := blockatlas.VerifyMessage(msg.Body, c.Secret)
+	if err != nil {
+		DeliveryLogger(msg).WithFields(log.Fields{
+			"message_id":   msg.MessageId,
+			"delivery_tag": msg.DeliveryTag,
+			"error":        err,
+		}).Error("Rejected MQ message")
+		return DeadLetter(msg)
+	}
+	msg.Body = payload
+	return c.Consumer.Callback(msg)
+}
+
+// TimedConsumer records the processing time of the messages of the queue by the consumer
+type TimedConsumer struct {
+	mq.Consumer
+	Queue mq.Queue
+}
+
+func (c TimedConsumer) Callback(msg amqp.Delivery) error {
+	start := time.Now()
+	err := c.Consumer.Callback(msg)
+	metrics.ObserveConsumerCallback(string(c.Queue), time.Since(start), err != nil)
+	return err
+}
+
+// ValidateDelivery runs quick sanity checks on the message body, a non positive maxSize disables the size check
+func ValidateDelivery(msg amqp.Delivery, maxSize int) error {
+	size := len(msg.Body)
+	if size == 0 {
+		return errEmptyMessage
+	}
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("message size %d exceeds limit %d", size, maxSize)
+	}
+	if first := msg.Body[0]; first != '{' && first != '[' {
+		return errMalformedMessage
+	}
+	if !json.Valid(msg.Body) {
+		return errMalformedMessage
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+func TestValidateDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		maxSize int
+		wantErr bool
+	}{
+		{"Test valid array", `[{"id":"1"}]`, 100, false},
+		{"Test valid object", `{"operation":"AddSubscription"}`, 100, false},
+		{"Test size check disabled", `[{"id":"1"}]`, 0, false},
+		{"Test empty", ``, 100, true},
+		{"Test oversized", `[{"id":"1"}]`, 5, true},
+		{"Test not JSON", `hello`, 100, true},
+		{"Test truncated JSON", `[{"id":"1"`, 100, true},
+		{"Test JSON string", `"text"`, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDelivery(amqp.Delivery{Body: []byte(tt.body)}, tt.maxSize)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestTimedConsumer_Callback(t *testing.T) {
+	failing := TimedConsumer{
+		Consumer: mq.ConsumerDefaultCallback{Delivery: func(amqp.Delivery) error { return errors.New("timeout") }},
+		Queue:    RawTransactions,
+	}
+	assert.EqualError(t, failing.Callback(amqp.Delivery{Body: []byte(`[]`)}), "timeout")
+
+	succeeding := TimedConsumer{
+		Consumer: mq.ConsumerDefaultCallback{Delivery: func(amqp.Delivery) error { return nil }},
+		Queue:    RawTransactions,
+	}
+	assert.Nil(t, succeeding.Callback(amqp.Delivery{Body: []byte(`[]`)}))
+}
+
+func TestSignedConsumer_Callback(t *testing.T) {
+	var received []string
+	consumer := SignedConsumer{
+		Consumer: mq.ConsumerDefaultCallback{Delivery: func(msg amqp.Delivery) error {
+			received = append(received, string(msg.Body))
+			return nil
+		}},
+		Secret: "secret",
+	}
+	signed, err := blockatlas.SignMessage([]byte(`{"operation":"AddSubscription"}`), "secret")
+	assert.Nil(t, err)
+	forged, err := blockatlas.SignMessage([]byte(`{"operation":"AddSubscription"}`), "other")
+	assert.Nil(t, err)
+
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: signed}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: forged}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`{"operation":"AddSubscription"}`)}))
+	assert.Equal(t, []string{`{"operation":"AddSubscription"}`}, received)
+}
@@ -40,6 +40,9 @@ func InitConfig(confPath string) {
 	}
 
 	config.Init(confPath)
+	if err := InitLogging(config.Default.Log.Level, config.Default.Log.Format); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func InitEngine(ginMode string) *gin.Engine {
@@ -52,9 +55,15 @@ func InitEngine(ginMode string) *gin.Engine {
 	return engine
 }
 
-func InitMQ(url string) {
+// InitMQ connects to the broker, the queue and exchange names get the prefix of the environment
+func InitMQ(url, prefix string) {
+	SetQueuePrefix(prefix)
 	err := mq.Init(url)
 	if err != nil {
 		log.Fatal("Failed to init Rabbit MQ", err)
 	}
+	if err := initPublisher(url); err != nil {
+		log.Fatal("Failed to init Rabbit MQ publisher", err)
+	}
+	mqConfigured = true
 }
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// InitLogging sets the level and format of the logs, golibs logs with the same logger.
+// Empty values keep the info level and the text format.
+func InitLogging(level, format string) error {
+	logLevel := log.InfoLevel
+	if level != "" {
+		var err error
+		if logLevel, err = log.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+	switch format {
+	case "", LogFormatText:
+		log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	case LogFormatJSON:
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	log.SetLevel(logLevel)
+	return nil
+}
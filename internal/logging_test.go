@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitLogging(t *testing.T) {
+	defer func() {
+		log.SetLevel(log.InfoLevel)
+		log.SetFormatter(&log.TextFormatter{})
+	}()
+
+	assert.Nil(t, InitLogging("debug", LogFormatJSON))
+	assert.Equal(t, log.DebugLevel, log.GetLevel())
+	assert.IsType(t, &log.JSONFormatter{}, log.StandardLogger().Formatter)
+
+	assert.Nil(t, InitLogging("", ""))
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+	assert.IsType(t, &log.TextFormatter{}, log.StandardLogger().Formatter)
+
+	assert.NotNil(t, InitLogging("verbose", LogFormatText))
+	assert.NotNil(t, InitLogging("warn", "logfmt"))
+	assert.Equal(t, log.InfoLevel, log.GetLevel())
+}
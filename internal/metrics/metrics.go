@@ -25,8 +25,54 @@ var (
 			"enabled",
 		},
 	)
+
+	providerRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "provider",
+			Name:      "requests_total",
+			Help:      "Transaction lookups served by the upstream providers",
+		},
+		[]string{
+			"coin",
+			"provider",
+			"status",
+		},
+	)
+
+	consumerCallbackDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "consumer",
+			Name:      "callback_duration_seconds",
+			Help:      "Processing time of the MQ messages by the consumers",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{
+			"queue",
+			"status",
+		},
+	)
 )
 
+// ObserveProviderRequest counts a transaction lookup of an upstream provider
+func ObserveProviderRequest(coin, provider string, failed bool) {
+	status := "success"
+	if failed {
+		status = "failure"
+	}
+	providerRequests.With(prometheus.Labels{"coin": coin, "provider": provider, "status": status}).Inc()
+}
+
+// ObserveConsumerCallback records the processing time of a message consumed from the queue
+func ObserveConsumerCallback(queue string, duration time.Duration, failed bool) {
+	status := "success"
+	if failed {
+		status = "failure"
+	}
+	consumerCallbackDuration.With(prometheus.Labels{"queue": queue, "status": status}).Observe(duration.Seconds())
+}
+
 func setupUpdateTrackerMetrics(db *db.Instance) {
 	go func() {
 		for {
@@ -48,6 +94,10 @@ func Setup(db *db.Instance) {
 	prometheus.DefaultRegisterer.Unregister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
 
 	prometheus.MustRegister(workerBlockParsing)
+	prometheus.MustRegister(providerRequests)
+	prometheus.MustRegister(consumerCallbackDuration)
 
-	setupUpdateTrackerMetrics(db)
+	if db != nil {
+		setupUpdateTrackerMetrics(db)
+	}
 }
@@ -1,24 +1,250 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 	"github.com/trustwallet/blockatlas/db"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/network/mq"
 )
 
-const (
+// Queue and exchange names are prefixed by InitMQ, they must not be used before
+var (
 	// End consumer of published transactions. Not consumed on blockatlas
 	TxNotifications mq.Queue = "txNotifications"
 	// Address:coin subscriptions
 	Subscriptions       mq.Queue = "subscriptions"
 	SubscriptionsTokens mq.Queue = "subscriptions_tokens"
+	// New subscriptions seeding the cached balances
+	SubscriptionsBalances mq.Queue = "subscriptions_balances"
 
 	// Transactions to process, if match subscriptions, pushed to TxNotifications
 	RawTransactions         mq.Queue    = "rawTransactions"
 	RawTokens               mq.Queue    = "rawTokens"
 	RawTransactionsExchange mq.Exchange = "raw_transactions"
+
+	// Transactions applied to the cached balances of the subscribed addresses
+	RawBalances mq.Queue = "rawBalances"
+
+	// Messages rejected by consumers before processing
+	DeadLetters mq.Queue = "deadLetters"
+)
+
+var (
+	// queuePrefix namespaces the queues and exchanges of an environment sharing the broker
+	queuePrefix string
+
+	// unprefixed names, the prefix can be set again
+	queueNames = map[*mq.Queue]mq.Queue{
+		&TxNotifications:       TxNotifications,
+		&Subscriptions:         Subscriptions,
+		&SubscriptionsTokens:   SubscriptionsTokens,
+		&SubscriptionsBalances: SubscriptionsBalances,
+		&RawTransactions:       RawTransactions,
+		&RawTokens:             RawTokens,
+		&RawBalances:           RawBalances,
+		&DeadLetters:           DeadLetters,
+	}
+	rawTransactionsExchangeName = RawTransactionsExchange
+)
+
+// SetQueuePrefix prefixes the names of the queues and exchanges, e.g. prod.rawTransactions.
+// An empty prefix keeps the names unchanged.
+func SetQueuePrefix(prefix string) {
+	queuePrefix = prefix
+	for queue, name := range queueNames {
+		*queue = mq.Queue(prefixed(string(name)))
+	}
+	RawTransactionsExchange = mq.Exchange(prefixed(string(rawTransactionsExchangeName)))
+}
+
+func prefixed(name string) string {
+	return prefixedWith(queuePrefix, name)
+}
+
+func prefixedWith(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// ErrMQNotInitialized signals an MQ operation before InitMQ connected to the broker
+var ErrMQNotInitialized = errors.New("MQ is not initialized")
+
+// mqConfigured is set once InitMQ connected to the broker
+var mqConfigured bool
+
+// MQConfigured reports whether a broker is connected, the API runs without one
+func MQConfigured() bool {
+	return mqConfigured
+}
+
+// publisher publishes with headers on a dedicated connection, golibs mq doesn't expose message headers
+var (
+	publisherConn *amqp.Connection
+	publisher     *amqp.Channel
 )
 
+func initPublisher(url string) error {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	publisherConn, publisher = conn, channel
+	return nil
+}
+
+// Publish sends the body to the queue, it is a no-op without a broker
+// as golibs mq panics publishing before Init
+func Publish(queue mq.Queue, body []byte) error {
+	return PublishWithHeaders(queue, body, nil)
+}
+
+// PublishWithHeaders sends the body to the queue with the headers, on top of the version
+func PublishWithHeaders(queue mq.Queue, body []byte, headers amqp.Table) error {
+	if !mqConfigured {
+		log.WithField("queue", queue).Debug("MQ is not configured, message dropped")
+		return nil
+	}
+	return publish("", string(queue), body, versioned(headers, MessageVersion))
+}
+
+// DeadLetter moves the message to the DeadLetters queue, keeping its headers
+func DeadLetter(msg amqp.Delivery) error {
+	if !mqConfigured {
+		log.WithField("queue", DeadLetters).Debug("MQ is not configured, message dropped")
+		return nil
+	}
+	return publish("", string(DeadLetters), msg.Body, versioned(msg.Headers, ""))
+}
+
+// PublishSigned sends the body wrapped in a blockatlas.SignedMessage signed with the secret,
+// for consumers verifying their messages with a SignedConsumer
+func PublishSigned(queue mq.Queue, body []byte, secret string) error {
+	signed, err := blockatlas.SignMessage(body, secret)
+	if err != nil {
+		return err
+	}
+	return Publish(queue, signed)
+}
+
+// PublishToExchange sends the body to the exchange, it is a no-op without a broker
+func PublishToExchange(exchange mq.Exchange, body []byte) error {
+	return PublishToExchangeWithHeaders(exchange, body, nil)
+}
+
+// PublishToExchangeWithHeaders sends the body to the exchange with the headers, on top of the version
+func PublishToExchangeWithHeaders(exchange mq.Exchange, body []byte, headers amqp.Table) error {
+	if !mqConfigured {
+		log.WithField("exchange", exchange).Debug("MQ is not configured, message dropped")
+		return nil
+	}
+	return publish(string(exchange), "", body, versioned(headers, MessageVersion))
+}
+
+// versioned returns a copy of the headers with the version, unversioned messages are major version 1
+func versioned(headers amqp.Table, version string) amqp.Table {
+	result := amqp.Table{}
+	for key, value := range headers {
+		result[key] = value
+	}
+	if version != "" {
+		result[MessageVersionHeader] = version
+	}
+	return result
+}
+
+func publish(exchange, key string, body []byte, headers amqp.Table) error {
+	return publisher.Publish(exchange, key, false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "text/plain",
+		Headers:      headers,
+		Body:         body,
+	})
+}
+
+// RunConsumer starts consuming the queue in the background,
+// golibs mq panics on a nil channel when consuming before Init
+func RunConsumer(queue mq.Queue, consumer mq.Consumer, options mq.ConsumerOptions, ctx context.Context) error {
+	if !mqConfigured {
+		return ErrMQNotInitialized
+	}
+	go queue.RunConsumer(consumer, options, ctx)
+	return nil
+}
+
+// QueueConsumer is the consumer of a queue with its options
+type QueueConsumer struct {
+	Consumer mq.Consumer
+	Options  mq.ConsumerOptions
+}
+
+// RunConsumers runs the consumers of the queues concurrently. It blocks until the context
+// is done or a shutdown signal is received, then waits for all of them to stop.
+func RunConsumers(consumers map[mq.Queue]QueueConsumer, ctx context.Context) error {
+	if !mqConfigured {
+		return ErrMQNotInitialized
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var wg sync.WaitGroup
+	for queue, consumer := range consumers {
+		wg.Add(1)
+		go func(queue mq.Queue, consumer QueueConsumer) {
+			defer wg.Done()
+			queue.RunConsumer(consumer.Consumer, consumer.Options, ctx)
+		}(queue, consumer)
+	}
+	select {
+	case <-ctx.Done():
+	case sig := <-quit:
+		log.Info("Stopping consumers: ", sig)
+		cancel()
+	}
+	wg.Wait()
+	return nil
+}
+
+// CloseMQ closes the channels to the broker
+func CloseMQ() error {
+	if !mqConfigured {
+		return ErrMQNotInitialized
+	}
+	if err := publisher.Close(); err != nil {
+		log.Error(err)
+	}
+	if err := publisherConn.Close(); err != nil {
+		log.Error(err)
+	}
+	return mq.Close()
+}
+
+// GetTransactionsQueue returns the queue the transactions of a coin are consumed from,
+// RawTransactions unless the coin is isolated on a dedicated queue
+func GetTransactionsQueue(name string) mq.Queue {
+	if name == "" {
+		return RawTransactions
+	}
+	return mq.Queue(prefixed(name))
+}
+
 type ConsumerDatabase struct {
 	Database *db.Instance
 	Delivery func(*db.Instance, amqp.Delivery) error
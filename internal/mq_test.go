@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+func TestSetQueuePrefix(t *testing.T) {
+	defer SetQueuePrefix("")
+
+	SetQueuePrefix("prod")
+	assert.Equal(t, mq.Queue("prod.rawTransactions"), RawTransactions)
+	assert.Equal(t, mq.Queue("prod.deadLetters"), DeadLetters)
+	assert.Equal(t, mq.Exchange("prod.raw_transactions"), RawTransactionsExchange)
+	assert.Equal(t, mq.Queue("prod.rawTransactions"), GetTransactionsQueue(""))
+	assert.Equal(t, mq.Queue("prod.rawTransactionsBitcoin"), GetTransactionsQueue("rawTransactionsBitcoin"))
+
+	SetQueuePrefix("staging")
+	assert.Equal(t, mq.Queue("staging.subscriptions"), Subscriptions)
+
+	SetQueuePrefix("")
+	assert.Equal(t, mq.Queue("txNotifications"), TxNotifications)
+	assert.Equal(t, mq.Queue("rawTransactionsBitcoin"), GetTransactionsQueue("rawTransactionsBitcoin"))
+}
+
+func TestVersioned(t *testing.T) {
+	headers := amqp.Table{SequenceHeader: "1"}
+	assert.Equal(t, amqp.Table{SequenceHeader: "1", MessageVersionHeader: MessageVersion}, versioned(headers, MessageVersion))
+	assert.Equal(t, amqp.Table{SequenceHeader: "1"}, headers)
+	assert.Equal(t, amqp.Table{}, versioned(nil, ""))
+}
+
+func TestPublish_NotConfigured(t *testing.T) {
+	assert.False(t, MQConfigured())
+	assert.Nil(t, Publish(RawTransactions, []byte(`[]`)))
+	assert.Nil(t, PublishToExchange(RawTransactionsExchange, []byte(`[]`)))
+}
+
+func TestRunConsumer_NotInitialized(t *testing.T) {
+	consumer := mq.ConsumerDefaultCallback{Delivery: func(amqp.Delivery) error { return nil }}
+	err := RunConsumer(RawTransactions, consumer, mq.InitDefaultConsumerOptions(1), context.Background())
+	assert.Equal(t, ErrMQNotInitialized, err)
+	assert.Equal(t, ErrMQNotInitialized, CloseMQ())
+}
+
+func TestRunConsumers(t *testing.T) {
+	assert.Equal(t, ErrMQNotInitialized, RunConsumers(nil, context.Background()))
+
+	mqConfigured = true
+	defer func() { mqConfigured = false }()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Nil(t, RunConsumers(map[mq.Queue]QueueConsumer{}, ctx))
+}
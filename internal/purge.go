@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/streadway/amqp"
+)
+
+// ErrQueueNotFound signals a purge of a queue missing from the broker
+var ErrQueueNotFound = errors.New("queue not found")
+
+// QueuePurger drops the ready messages of queues over a dedicated connection, golibs mq doesn't expose purges.
+// Queue names get the prefix of the environment.
+type QueuePurger struct {
+	URL    string
+	Prefix string
+}
+
+// PurgeQueue returns the number of dropped messages, unacknowledged deliveries are kept
+func (p QueuePurger) PurgeQueue(name string) (int, error) {
+	conn, err := amqp.Dial(p.URL)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	channel, err := conn.Channel()
+	if err != nil {
+		return 0, err
+	}
+	defer channel.Close()
+	purged, err := channel.QueuePurge(prefixedWith(p.Prefix, name), false)
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return 0, ErrQueueNotFound
+	}
+	return purged, err
+}
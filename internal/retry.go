@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+// RetryAttemptHeader counts the retries of a message
+const RetryAttemptHeader = "x-retry-attempt"
+
+type (
+	// Retrier delays a failed message before it is delivered again to its queue
+	Retrier interface {
+		Attempts() int
+		Retry(queue mq.Queue, attempt int, msg amqp.Delivery) error
+	}
+
+	// RetryQueues holds a message queue per retry delay. Messages expire after the delay
+	// and are dead lettered back to the queue they failed on.
+	RetryQueues struct {
+		conn    *amqp.Connection
+		channel *amqp.Channel
+		delays  []time.Duration
+	}
+
+	// RetryConsumer schedules a delayed retry of the messages the consumer fails to process,
+	// instead of requeueing them immediately. Messages failing after the last retry are moved
+	// to the DeadLetters queue.
+	RetryConsumer struct {
+		mq.Consumer
+		Queue   mq.Queue
+		Retrier Retrier
+	}
+)
+
+// InitRetryQueues opens a dedicated channel, golibs mq doesn't expose queue arguments and message headers
+func InitRetryQueues(url string, delays []time.Duration) (*RetryQueues, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &RetryQueues{conn: conn, channel: channel, delays: delays}, nil
+}
+
+// Declare creates the retry queues of the queue, one per delay
+func (r *RetryQueues) Declare(queue mq.Queue) error {
+	for i, delay := range r.delays {
+		_, err := r.channel.QueueDeclare(retryQueueName(queue, i), true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": string(queue),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RetryQueues) Attempts() int {
+	return len(r.delays)
+}
+
+// Retry schedules the message, keeping its headers
+func (r *RetryQueues) Retry(queue mq.Queue, attempt int, msg amqp.Delivery) error {
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[RetryAttemptHeader] = int32(attempt + 1)
+	return r.channel.Publish("", retryQueueName(queue, attempt), false, false, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "text/plain",
+		Headers:      headers,
+		Body:         msg.Body,
+	})
+}
+
+func (r *RetryQueues) Close() error {
+	if err := r.channel.Close(); err != nil {
+		log.Error(err)
+	}
+	return r.conn.Close()
+}
+
+func retryQueueName(queue mq.Queue, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queue, attempt)
+}
+
+func (c RetryConsumer) Callback(msg amqp.Delivery) error {
+	err := c.Consumer.Callback(msg)
+	if err == nil {
+		return nil
+	}
+	attempt := RetryAttempt(msg)
+	fields := log.Fields{
+		"queue":   c.Queue,
+		"attempt": attempt,
+		"error":   err,
+	}
+	if attempt >= c.Retrier.Attempts() {
+		DeliveryLogger(msg).WithFields(fields).Error("MQ message retries exhausted")
+		return DeadLetter(msg)
+	}
+	if retryErr := c.Retrier.Retry(c.Queue, attempt, msg); retryErr != nil {
+		DeliveryLogger(msg).WithFields(fields).Error("Failed to schedule MQ message retry: ", retryErr)
+		return err
+	}
+	DeliveryLogger(msg).WithFields(fields).Warn("Scheduled MQ message retry")
+	return nil
+}
+
+// RetryAttempt returns the number of retries of the message so far
+func RetryAttempt(msg amqp.Delivery) int {
+	switch attempt := msg.Headers[RetryAttemptHeader].(type) {
+	case int32:
+		return int(attempt)
+	case int64:
+		return int(attempt)
+	case int:
+		return attempt
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+type retrierMock struct {
+	attempts int
+	retried  []int
+}
+
+func (m *retrierMock) Attempts() int {
+	return m.attempts
+}
+
+func (m *retrierMock) Retry(queue mq.Queue, attempt int, msg amqp.Delivery) error {
+	m.retried = append(m.retried, attempt)
+	return nil
+}
+
+func TestRetryConsumer_Callback(t *testing.T) {
+	failing := mq.ConsumerDefaultCallback{Delivery: func(amqp.Delivery) error {
+		return errors.New("timeout")
+	}}
+	retrier := &retrierMock{attempts: 3}
+	consumer := RetryConsumer{Consumer: failing, Queue: RawTransactions, Retrier: retrier}
+
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`[]`)}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{
+		Headers: amqp.Table{RetryAttemptHeader: int32(2)},
+		Body:    []byte(`[]`),
+	}))
+	assert.Equal(t, []int{0, 2}, retrier.retried)
+
+	succeeding := RetryConsumer{
+		Consumer: mq.ConsumerDefaultCallback{Delivery: func(amqp.Delivery) error { return nil }},
+		Queue:    RawTransactions,
+		Retrier:  retrier,
+	}
+	assert.Nil(t, succeeding.Callback(amqp.Delivery{Body: []byte(`[]`)}))
+	assert.Len(t, retrier.retried, 2)
+}
+
+func TestRetryAttempt(t *testing.T) {
+	assert.Equal(t, 0, RetryAttempt(amqp.Delivery{}))
+	assert.Equal(t, 1, RetryAttempt(amqp.Delivery{Headers: amqp.Table{RetryAttemptHeader: int32(1)}}))
+	assert.Equal(t, 2, RetryAttempt(amqp.Delivery{Headers: amqp.Table{RetryAttemptHeader: int64(2)}}))
+	assert.Equal(t, 0, RetryAttempt(amqp.Delivery{Headers: amqp.Table{RetryAttemptHeader: "3"}}))
+}
+
+func TestRetryQueueName(t *testing.T) {
+	assert.Equal(t, "rawTransactions.retry.0", retryQueueName(RawTransactions, 0))
+}
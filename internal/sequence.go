@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/golibs/network/mq"
+	"github.com/trustwallet/golibs/types"
+)
+
+// Published transactions messages carry their sequence and the hash of their highest block, see TxsSequence and BlockHash
+const (
+	SequenceHeader  = "x-sequence"
+	BlockHashHeader = "x-block-hash"
+)
+
+type (
+	// SequenceStore keeps the last sequence processed per consumer and coin, with the hash of its block
+	SequenceStore interface {
+		GetConsumerSequenceHash(consumer string, coin uint) (uint64, string, error)
+		SetConsumerSequenceHash(consumer string, coin uint, sequence uint64, hash string) error
+		RewindConsumerSequence(consumer string, coin uint, sequence uint64, hash string) error
+	}
+
+	// SequencedConsumer skips the transaction messages older than the last one processed for their coin,
+	// redeliveries included, so that stale data doesn't overwrite newer state. A message of the last block
+	// processed with another block hash was republished after a reorg: it is processed and rewinds the sequence.
+	// Messages without the sequence headers, published before them, are sequenced from their body.
+	// Ordering is only guaranteed with a single worker, concurrent workers may finish out of order.
+	SequencedConsumer struct {
+		mq.Consumer
+		Store SequenceStore
+		Tag   string
+	}
+)
+
+// SequenceHeaders returns the sequence headers of a transactions message
+func SequenceHeaders(txs types.Txs) amqp.Table {
+	sequence := TxsSequence(txs)
+	return amqp.Table{
+		SequenceHeader:  strconv.FormatUint(sequence, 10),
+		BlockHashHeader: BlockHash(txs, SequenceBlock(sequence)),
+	}
+}
+
+// MessageSequence returns the sequence of a transactions message and the hash of its highest block,
+// from its headers or from the transactions for messages published without them
+func MessageSequence(msg amqp.Delivery, txs types.Txs) (uint64, string) {
+	if raw, ok := msg.Headers[SequenceHeader].(string); ok {
+		if sequence, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			hash, _ := msg.Headers[BlockHashHeader].(string)
+			return sequence, hash
+		}
+	}
+	sequence := TxsSequence(txs)
+	return sequence, BlockHash(txs, SequenceBlock(sequence))
+}
+
+func (c SequencedConsumer) Callback(msg amqp.Delivery) error {
+	var txs types.Txs
+	if err := json.Unmarshal(msg.Body, &txs); err != nil || len(txs) == 0 {
+		return c.Consumer.Callback(msg)
+	}
+	coin := txs[0].Coin
+	sequence, hash := MessageSequence(msg, txs)
+
+	last, lastHash, err := c.Store.GetConsumerSequenceHash(c.Tag, coin)
+	if err != nil {
+		return err
+	}
+	fields := log.Fields{"consumer": c.Tag, "coin": coin, "sequence": sequence, "last": last}
+	reorg := last > 0 && SequenceBlock(sequence) == SequenceBlock(last) && hash != "" && lastHash != "" && hash != lastHash
+	switch {
+	case reorg:
+		DeliveryLogger(msg).WithFields(fields).Warn("Rewinding consumer sequence")
+	case sequence == last:
+		DeliveryLogger(msg).WithFields(fields).Warn("Skipped duplicate MQ message")
+		return nil
+	case sequence < last:
+		DeliveryLogger(msg).WithFields(fields).Warn("Skipped stale MQ message")
+		return nil
+	}
+
+	if err := c.Consumer.Callback(msg); err != nil {
+		return err
+	}
+	if reorg {
+		return c.Store.RewindConsumerSequence(c.Tag, coin, sequence, hash)
+	}
+	return c.Store.SetConsumerSequenceHash(c.Tag, coin, sequence, hash)
+}
+
+// txIndexBits is the number of low bits of a sequence holding the transaction index within its block
+const txIndexBits = 20
+
+// TxsSequence is the monotonic sequence of a published transactions message: the highest block it contains,
+// then the index within that block of its last transaction, since the parser publishes blocks and their
+// transactions in ascending order
+func TxsSequence(txs types.Txs) uint64 {
+	block, count := TxsBlock(txs), uint64(0)
+	for _, tx := range txs {
+		if tx.Block == block {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	index := count - 1
+	if max := uint64(1)<<txIndexBits - 1; index > max {
+		index = max
+	}
+	return block<<txIndexBits | index
+}
+
+// SequenceBlock is the block of a sequence
+func SequenceBlock(sequence uint64) uint64 {
+	return sequence >> txIndexBits
+}
+
+// TxsBlock is the highest block of the transactions
+func TxsBlock(txs types.Txs) uint64 {
+	var block uint64
+	for _, tx := range txs {
+		if tx.Block > block {
+			block = tx.Block
+		}
+	}
+	return block
+}
+
+// BlockHash identifies the block of the transactions at the height by the hashes of its transactions,
+// the parser is not handed the block hashes by the providers. It differs once a reorg replaced the block.
+func BlockHash(txs types.Txs, block uint64) string {
+	ids := make([]string, 0)
+	for _, tx := range txs {
+		if tx.Block == block {
+			ids = append(ids, tx.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	sort.Strings(ids)
+	hash := sha256.New()
+	for _, id := range ids {
+		hash.Write([]byte(id))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
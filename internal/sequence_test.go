@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+type sequenceStoreMock map[uint]storedSequence
+
+type storedSequence struct {
+	sequence uint64
+	hash     string
+}
+
+func (m sequenceStoreMock) GetConsumerSequenceHash(consumer string, coin uint) (uint64, string, error) {
+	return m[coin].sequence, m[coin].hash, nil
+}
+
+func (m sequenceStoreMock) SetConsumerSequenceHash(consumer string, coin uint, sequence uint64, hash string) error {
+	if sequence >= m[coin].sequence {
+		m[coin] = storedSequence{sequence: sequence, hash: hash}
+	}
+	return nil
+}
+
+func (m sequenceStoreMock) RewindConsumerSequence(consumer string, coin uint, sequence uint64, hash string) error {
+	m[coin] = storedSequence{sequence: sequence, hash: hash}
+	return nil
+}
+
+type consumerMock struct {
+	calls int
+}
+
+func (c *consumerMock) Callback(msg amqp.Delivery) error {
+	c.calls++
+	return nil
+}
+
+// sequencedDelivery returns the message the parser publishes for the transactions, by block
+func sequencedDelivery(blocks ...uint64) amqp.Delivery {
+	return sequencedDeliveryOf("", blocks...)
+}
+
+// sequencedDeliveryOf returns the message of transactions with the ids suffixed, for blocks replaced by a reorg
+func sequencedDeliveryOf(suffix string, blocks ...uint64) amqp.Delivery {
+	txs := make(types.Txs, 0)
+	for i, block := range blocks {
+		id := strconv.Itoa(i) + suffix
+		txs = append(txs, types.Tx{ID: id, Coin: 60, Block: block, Fee: "0", Type: types.TxTransfer, Meta: types.Transfer{Value: "1"}})
+	}
+	body, _ := json.Marshal(txs)
+	return amqp.Delivery{Body: body, Headers: SequenceHeaders(txs)}
+}
+
+func TestSequencedConsumer_Callback(t *testing.T) {
+	store := sequenceStoreMock{}
+	consumer := &consumerMock{}
+	sequenced := SequencedConsumer{Consumer: consumer, Store: store, Tag: "transactions"}
+
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(100, 101, 101)))
+	assert.Equal(t, uint64(101<<txIndexBits|1), store[60].sequence)
+
+	// redelivery
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(100, 101, 101)))
+	assert.Equal(t, 1, consumer.calls)
+
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(102)))
+	assert.Equal(t, uint64(102<<txIndexBits), store[60].sequence)
+
+	// stale redelivery of older blocks doesn't overwrite the newer state
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(100, 101, 101)))
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(99, 100)))
+	assert.Equal(t, 2, consumer.calls)
+	assert.Equal(t, uint64(102<<txIndexBits), store[60].sequence)
+
+	// the last block was replaced by a reorg, with fewer transactions
+	assert.Nil(t, sequenced.Callback(sequencedDeliveryOf("-reorg", 102, 102)))
+	assert.Nil(t, sequenced.Callback(sequencedDeliveryOf("-reorg", 102)))
+	assert.Equal(t, 4, consumer.calls)
+	assert.Equal(t, uint64(102<<txIndexBits), store[60].sequence)
+	assert.Equal(t, BlockHash(types.Txs{{ID: "0-reorg", Block: 102}}, 102), store[60].hash)
+
+	assert.Nil(t, sequenced.Callback(sequencedDelivery(103)))
+	assert.Equal(t, 5, consumer.calls)
+	assert.Equal(t, uint64(103<<txIndexBits), store[60].sequence)
+}
+
+func TestMessageSequence(t *testing.T) {
+	txs := types.Txs{{ID: "a", Block: 10}, {ID: "b", Block: 12}}
+	sequence, hash := MessageSequence(amqp.Delivery{Headers: amqp.Table{SequenceHeader: "42", BlockHashHeader: "hash"}}, txs)
+	assert.Equal(t, uint64(42), sequence)
+	assert.Equal(t, "hash", hash)
+
+	// messages published before the headers
+	sequence, hash = MessageSequence(amqp.Delivery{}, txs)
+	assert.Equal(t, uint64(12<<txIndexBits), sequence)
+	assert.Equal(t, BlockHash(txs, 12), hash)
+}
+
+func TestBlockHash(t *testing.T) {
+	assert.Equal(t, "", BlockHash(types.Txs{{ID: "a", Block: 10}}, 11))
+	assert.Equal(t, BlockHash(types.Txs{{ID: "a", Block: 10}, {ID: "b", Block: 10}}, 10), BlockHash(types.Txs{{ID: "b", Block: 10}, {ID: "a", Block: 10}, {ID: "c", Block: 9}}, 10))
+	assert.NotEqual(t, BlockHash(types.Txs{{ID: "a", Block: 10}}, 10), BlockHash(types.Txs{{ID: "b", Block: 10}}, 10))
+}
+
+func TestTxsSequence(t *testing.T) {
+	assert.Equal(t, uint64(0), TxsSequence(types.Txs{}))
+	assert.Equal(t, uint64(12<<txIndexBits|1), TxsSequence(types.Txs{{Block: 10}, {Block: 12}, {Block: 11}, {Block: 12}}))
+	assert.Equal(t, uint64(12), SequenceBlock(TxsSequence(types.Txs{{Block: 12}})))
+	assert.Equal(t, uint64(12), TxsBlock(types.Txs{{Block: 10}, {Block: 12}, {Block: 11}}))
+}
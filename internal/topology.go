@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+// QueueDeclaration is the state of a queue found by DeclareQueues
+type QueueDeclaration struct {
+	Queue     mq.Queue
+	Created   bool
+	Messages  int
+	Consumers int
+}
+
+// DeclareQueues declares the durable queues over a dedicated connection and logs whether each one was created
+// or already existed, with its messages and consumers. A queue existing with other flags fails the declaration
+// instead of being used as is, golibs mq doesn't expose passive declarations.
+func DeclareQueues(url string, queues []mq.Queue) ([]QueueDeclaration, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result := make([]QueueDeclaration, 0, len(queues))
+	for _, queue := range queues {
+		declaration, err := declareQueue(conn, queue)
+		if err != nil {
+			return result, fmt.Errorf("queue %s: %w", queue, err)
+		}
+		log.WithFields(log.Fields{
+			"queue":     queue,
+			"created":   declaration.Created,
+			"messages":  declaration.Messages,
+			"consumers": declaration.Consumers,
+		}).Info("Queue declared")
+		result = append(result, declaration)
+	}
+	return result, nil
+}
+
+// declareQueue checks the queue passively first, the broker closes the channel of a failed check
+func declareQueue(conn *amqp.Connection, queue mq.Queue) (QueueDeclaration, error) {
+	channel, err := conn.Channel()
+	if err != nil {
+		return QueueDeclaration{}, err
+	}
+	_, err = channel.QueueDeclarePassive(string(queue), true, false, false, false, nil)
+	created := false
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		if channel, err = conn.Channel(); err != nil {
+			return QueueDeclaration{}, err
+		}
+		created = true
+	} else if err != nil {
+		return QueueDeclaration{}, err
+	}
+	defer channel.Close()
+
+	declared, err := channel.QueueDeclare(string(queue), true, false, false, false, nil)
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.PreconditionFailed {
+		return QueueDeclaration{}, fmt.Errorf("exists with other flags, delete it to declare it again: %w", err)
+	}
+	if err != nil {
+		return QueueDeclaration{}, err
+	}
+	return QueueDeclaration{
+		Queue:     queue,
+		Created:   created,
+		Messages:  declared.Messages,
+		Consumers: declared.Consumers,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+// RequestIDHeader carries the correlation ID of the request a message comes from, see blockatlas.RequestID
+const RequestIDHeader = "X-Request-ID"
+
+// TraceHeaders returns the headers carrying the correlation ID of the context to the published messages,
+// nil without one
+func TraceHeaders(ctx context.Context) amqp.Table {
+	id := blockatlas.RequestID(ctx)
+	if id == "" {
+		return nil
+	}
+	return amqp.Table{RequestIDHeader: id}
+}
+
+// DeliveryContext returns the context of the message, carrying the correlation ID of its header
+// for the log entries and the messages published while processing it
+func DeliveryContext(msg amqp.Delivery) context.Context {
+	id, _ := msg.Headers[RequestIDHeader].(string)
+	return blockatlas.WithRequestID(context.Background(), id)
+}
+
+// DeliveryLogger returns the log entry of the message, see DeliveryContext
+func DeliveryLogger(msg amqp.Delivery) *log.Entry {
+	return blockatlas.Logger(DeliveryContext(msg))
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+func TestTraceHeaders(t *testing.T) {
+	assert.Nil(t, TraceHeaders(context.Background()))
+
+	msg := amqp.Delivery{Headers: amqp.Table{RequestIDHeader: "abc"}}
+	ctx := DeliveryContext(msg)
+	assert.Equal(t, "abc", blockatlas.RequestID(ctx))
+	assert.Equal(t, amqp.Table{RequestIDHeader: "abc"}, TraceHeaders(ctx))
+	assert.Equal(t, "abc", DeliveryLogger(msg).Data[blockatlas.RequestIDField])
+
+	assert.Equal(t, "", blockatlas.RequestID(DeliveryContext(amqp.Delivery{})))
+	assert.NotContains(t, DeliveryLogger(amqp.Delivery{}).Data, blockatlas.RequestIDField)
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+// Published messages carry the version of their format in the MessageVersionHeader header as major.minor,
+// the body is left unchanged so consumers predating the header keep working.
+//
+// Compatibility policy:
+//   - adding optional fields bumps the minor version, consumers ignore the fields they don't know
+//   - removing or renaming fields, changing their type or the shape of the message bumps the major version
+//   - consumers process the messages up to their major version, newer ones are moved to the DeadLetters
+//     queue to be replayed once the consumers are upgraded. Upgrade the consumers before the producers
+//   - messages without the header predate versioning and are major version 1
+const (
+	MessageVersionHeader = "x-message-version"
+	MessageVersion       = "1.0"
+)
+
+// messageMajor is the major version of MessageVersion
+const messageMajor = 1
+
+// MessageMajor returns the major version of the message
+func MessageMajor(msg amqp.Delivery) (int, error) {
+	version, ok := msg.Headers[MessageVersionHeader].(string)
+	if !ok {
+		return 1, nil
+	}
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil || major < 1 {
+		return 0, fmt.Errorf("invalid message version %q", version)
+	}
+	return major, nil
+}
+
+// CheckMessageVersion fails messages of a major version newer than the one of the consumer
+func CheckMessageVersion(msg amqp.Delivery) error {
+	major, err := MessageMajor(msg)
+	if err != nil {
+		return err
+	}
+	if major > messageMajor {
+		return fmt.Errorf("unsupported message version %v", msg.Headers[MessageVersionHeader])
+	}
+	return nil
+}
+
+// VersionedConsumer moves the messages the consumer can't read to the DeadLetters queue, see CheckMessageVersion
+type VersionedConsumer struct {
+	mq.Consumer
+}
+
+func (c VersionedConsumer) Callback(msg amqp.Delivery) error {
+	if err := CheckMessageVersion(msg); err != nil {
+		DeliveryLogger(msg).WithFields(log.Fields{
+			"message_id":   msg.MessageId,
+			"delivery_tag": msg.DeliveryTag,
+			"error":        err,
+		}).Warn("Skipped MQ message")
+		return DeadLetter(msg)
+	}
+	return c.Consumer.Callback(msg)
+}
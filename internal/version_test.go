@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/network/mq"
+)
+
+func TestMessageMajor(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers amqp.Table
+		want    int
+		wantErr bool
+	}{
+		{"unversioned", nil, 1, false},
+		{"current", amqp.Table{MessageVersionHeader: MessageVersion}, 1, false},
+		{"minor", amqp.Table{MessageVersionHeader: "1.3"}, 1, false},
+		{"major", amqp.Table{MessageVersionHeader: "2.0"}, 2, false},
+		{"major only", amqp.Table{MessageVersionHeader: "3"}, 3, false},
+		{"invalid", amqp.Table{MessageVersionHeader: "v2"}, 0, true},
+		{"zero", amqp.Table{MessageVersionHeader: "0.1"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			major, err := MessageMajor(amqp.Delivery{Headers: tt.headers})
+			assert.Equal(t, tt.want, major)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestVersionedConsumer_Callback(t *testing.T) {
+	var received []string
+	consumer := VersionedConsumer{Consumer: mq.ConsumerDefaultCallback{Delivery: func(msg amqp.Delivery) error {
+		received = append(received, string(msg.Body))
+		return nil
+	}}}
+
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`["legacy"]`)}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`["current"]`), Headers: amqp.Table{MessageVersionHeader: "1.1"}}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`["next"]`), Headers: amqp.Table{MessageVersionHeader: "2.0"}}))
+	assert.Nil(t, consumer.Callback(amqp.Delivery{Body: []byte(`["invalid"]`), Headers: amqp.Table{MessageVersionHeader: "x"}}))
+	assert.Equal(t, []string{`["legacy"]`, `["current"]`}, received)
+}
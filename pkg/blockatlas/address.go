@@ -0,0 +1,115 @@
+package blockatlas
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	mapset "github.com/deckarep/golang-set"
+	"github.com/trustwallet/golibs/coin"
+)
+
+// addressParams describes the P2PKH and P2SH version bytes of a network of a coin, and its bech32 prefix
+// for coins where the same public key hash has both a legacy and a native segwit address
+type addressParams struct {
+	pubKeyHashVersion byte
+	scriptHashVersion byte
+	hrp               string
+	testnet           bool
+}
+
+var addressCoins = map[uint][]addressParams{
+	coin.BITCOIN: {
+		{pubKeyHashVersion: 0x00, scriptHashVersion: 0x05, hrp: "bc"},
+		{pubKeyHashVersion: 0x6f, scriptHashVersion: 0xc4, hrp: "tb", testnet: true},
+	},
+	coin.LITECOIN: {
+		{pubKeyHashVersion: 0x30, scriptHashVersion: 0x32, hrp: "ltc"},
+		{pubKeyHashVersion: 0x6f, scriptHashVersion: 0x3a, hrp: "tltc", testnet: true},
+	},
+	coin.VIACOIN:  {{pubKeyHashVersion: 0x47, scriptHashVersion: 0x21, hrp: "via"}},
+	coin.DIGIBYTE: {{pubKeyHashVersion: 0x1e, scriptHashVersion: 0x3f, hrp: "dgb"}},
+	coin.DOGE: {
+		{pubKeyHashVersion: 0x1e, scriptHashVersion: 0x16},
+		{pubKeyHashVersion: 0x71, scriptHashVersion: 0xc4, testnet: true},
+	},
+	coin.DASH: {
+		{pubKeyHashVersion: 0x4c, scriptHashVersion: 0x10},
+		{pubKeyHashVersion: 0x8c, scriptHashVersion: 0x13, testnet: true},
+	},
+}
+
+// GetAddressEncodings returns the address with its other encodings of the same public key hash,
+// e.g. the legacy P2PKH address of a P2WPKH bech32 address, on the main and test networks
+func GetAddressEncodings(coinID uint, address string) []string {
+	result := []string{address}
+	for _, params := range addressCoins[coinID] {
+		if params.hrp == "" {
+			continue
+		}
+		if hash, ok := decodeWitnessPubKeyHash(params, address); ok {
+			return append(result, base58.CheckEncode(hash, params.pubKeyHashVersion))
+		}
+		if hash, version, err := base58.CheckDecode(address); err == nil && version == params.pubKeyHashVersion && len(hash) == 20 {
+			if encoded, ok := encodeWitnessPubKeyHash(params, hash); ok {
+				return append(result, encoded)
+			}
+		}
+	}
+	return result
+}
+
+// IsTestnetAddress tells whether the address belongs to the test network of the coin,
+// known is false for the coins and addresses of unknown formats
+func IsTestnetAddress(coinID uint, address string) (testnet bool, known bool) {
+	for _, params := range addressCoins[coinID] {
+		if params.hrp != "" && strings.HasPrefix(strings.ToLower(address), params.hrp+"1") {
+			if hrp, _, err := bech32.Decode(address); err == nil && hrp == params.hrp {
+				return params.testnet, true
+			}
+		}
+		if _, version, err := base58.CheckDecode(address); err == nil &&
+			(version == params.pubKeyHashVersion || version == params.scriptHashVersion) {
+			return params.testnet, true
+		}
+	}
+	return false, false
+}
+
+// NewAddressSet builds the set of addresses including all their encodings
+func NewAddressSet(coinID uint, addresses ...string) mapset.Set {
+	set := mapset.NewSet()
+	for _, address := range addresses {
+		for _, encoding := range GetAddressEncodings(coinID, address) {
+			set.Add(encoding)
+		}
+	}
+	return set
+}
+
+func decodeWitnessPubKeyHash(params addressParams, address string) ([]byte, bool) {
+	if !strings.HasPrefix(strings.ToLower(address), params.hrp+"1") {
+		return nil, false
+	}
+	hrp, data, err := bech32.Decode(address)
+	if err != nil || hrp != params.hrp || len(data) == 0 || data[0] != 0 {
+		return nil, false
+	}
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil || len(program) != 20 {
+		return nil, false
+	}
+	return program, true
+}
+
+func encodeWitnessPubKeyHash(params addressParams, hash []byte) (string, bool) {
+	program, err := bech32.ConvertBits(hash, 8, 5, true)
+	if err != nil {
+		return "", false
+	}
+	encoded, err := bech32.Encode(params.hrp, append([]byte{0}, program...))
+	if err != nil {
+		return "", false
+	}
+	return encoded, true
+}
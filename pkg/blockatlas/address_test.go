@@ -0,0 +1,70 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+)
+
+func TestGetAddressEncodings(t *testing.T) {
+	tests := []struct {
+		name    string
+		coin    uint
+		address string
+		want    []string
+	}{
+		{"Test bitcoin bech32", coin.BITCOIN, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			[]string{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"}},
+		{"Test bitcoin legacy", coin.BITCOIN, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+			[]string{"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"}},
+		{"Test bitcoin script hash", coin.BITCOIN, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+			[]string{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"}},
+		{"Test coin without segwit", coin.DOGE, "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L",
+			[]string{"DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L"}},
+		{"Test bitcoin testnet bech32", coin.BITCOIN, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			[]string{"tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"}},
+		{"Test bitcoin testnet legacy", coin.BITCOIN, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+			[]string{"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"}},
+		{"Test invalid address", coin.BITCOIN, "bc1qinvalid", []string{"bc1qinvalid"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetAddressEncodings(tt.coin, tt.address))
+		})
+	}
+}
+
+func TestIsTestnetAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		coin    uint
+		address string
+		testnet bool
+		known   bool
+	}{
+		{"Test bitcoin bech32", coin.BITCOIN, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false, true},
+		{"Test bitcoin legacy", coin.BITCOIN, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", false, true},
+		{"Test bitcoin script hash", coin.BITCOIN, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", false, true},
+		{"Test bitcoin testnet bech32", coin.BITCOIN, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", true, true},
+		{"Test bitcoin testnet legacy", coin.BITCOIN, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", true, true},
+		{"Test bitcoin testnet script hash", coin.BITCOIN, "2N3vVYSK5XRgVSGWy21PnsRmBUywSQNdCsf", true, true},
+		{"Test doge", coin.DOGE, "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", false, true},
+		{"Test doge testnet", coin.DOGE, "nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2", true, true},
+		{"Test invalid address", coin.BITCOIN, "bc1qinvalid", false, false},
+		{"Test coin of unknown formats", coin.ETHEREUM, "0x0875BCab22dE3d02402bc38aEe4104e1239374a7", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testnet, known := IsTestnetAddress(tt.coin, tt.address)
+			assert.Equal(t, tt.testnet, testnet)
+			assert.Equal(t, tt.known, known)
+		})
+	}
+}
+
+func TestNewAddressSet(t *testing.T) {
+	set := NewAddressSet(coin.BITCOIN, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+	assert.True(t, set.Contains("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"))
+	assert.Equal(t, 2, set.Cardinality())
+}
@@ -0,0 +1,70 @@
+package blockatlas
+
+import "github.com/trustwallet/golibs/types"
+
+const (
+	AssetTypeAll      AssetType = ""
+	AssetTypeNative   AssetType = "native"
+	AssetTypeFungible AssetType = "fungible"
+	AssetTypeNFT      AssetType = "nft"
+)
+
+// AssetType is the kind of asset a transaction moves
+type AssetType string
+
+func (a AssetType) IsValid() bool {
+	switch a {
+	case AssetTypeAll, AssetTypeNative, AssetTypeFungible, AssetTypeNFT:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetAssetType derives the kind of asset moved from the normalized transaction metadata.
+// Platforms normalize ERC-721 and ERC-1155 transfers to collectible transfers, other tokens are fungible.
+// Transactions moving several assets, e.g. multi currency transfers, have no asset type.
+func GetAssetType(tx types.Tx) AssetType {
+	switch meta := tx.Meta.(type) {
+	case types.Transfer, *types.Transfer, types.ContractCall, *types.ContractCall:
+		return AssetTypeNative
+	case types.TokenTransfer, *types.TokenTransfer, types.NativeTokenTransfer, *types.NativeTokenTransfer,
+		types.TokenSwap, *types.TokenSwap:
+		return AssetTypeFungible
+	case types.CollectibleTransfer, *types.CollectibleTransfer:
+		return AssetTypeNFT
+	case types.AnyAction:
+		return getActionAssetType(meta)
+	case *types.AnyAction:
+		return getActionAssetType(*meta)
+	default:
+		return AssetTypeAll
+	}
+}
+
+func getActionAssetType(action types.AnyAction) AssetType {
+	if action.TokenID == "" {
+		return AssetTypeNative
+	}
+	return AssetTypeFungible
+}
+
+func FilterTxsByAssetType(txs types.Txs, assetType AssetType) types.Txs {
+	if assetType == AssetTypeAll {
+		return txs
+	}
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if GetAssetType(tx) == assetType {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// SetAssetTypes surfaces the kind of asset moved by each transaction
+func (txs Txs) SetAssetTypes() {
+	for i := range txs {
+		txs[i].AssetType = GetAssetType(txs[i].Tx)
+	}
+}
@@ -0,0 +1,42 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetAssetType(t *testing.T) {
+	tests := []struct {
+		name string
+		meta interface{}
+		want AssetType
+	}{
+		{"transfer", types.Transfer{Value: "1"}, AssetTypeNative},
+		{"contract call", &types.ContractCall{Value: "1"}, AssetTypeNative},
+		{"token transfer", types.TokenTransfer{TokenID: "0xa0b8"}, AssetTypeFungible},
+		{"native token transfer", &types.NativeTokenTransfer{TokenID: "BUSD-BD1"}, AssetTypeFungible},
+		{"collectible transfer", types.CollectibleTransfer{Contract: "0x06012c8c"}, AssetTypeNFT},
+		{"native delegation", types.AnyAction{Key: types.KeyStakeDelegate}, AssetTypeNative},
+		{"token action", &types.AnyAction{TokenID: "BUSD-BD1"}, AssetTypeFungible},
+		{"multi currency transfer", types.MultiCurrencyTransfer{}, AssetTypeAll},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetAssetType(types.Tx{Meta: tt.meta}))
+		})
+	}
+}
+
+func TestFilterTxsByAssetType(t *testing.T) {
+	txs := types.Txs{
+		{ID: "native", Meta: types.Transfer{}},
+		{ID: "fungible", Meta: types.TokenTransfer{}},
+		{ID: "nft", Meta: types.CollectibleTransfer{}},
+	}
+	assert.Equal(t, txs, FilterTxsByAssetType(txs, AssetTypeAll))
+	assert.Equal(t, types.Txs{txs[2]}, FilterTxsByAssetType(txs, AssetTypeNFT))
+	assert.Equal(t, types.Txs{txs[1]}, FilterTxsByAssetType(txs, AssetTypeFungible))
+	assert.False(t, AssetType("erc721").IsValid())
+}
@@ -0,0 +1,59 @@
+package blockatlas
+
+import "time"
+
+type (
+	// BatchResponse is the response of a request fanned out to several coins.
+	// Results of the coins which succeeded are kept when other coins fail.
+	BatchResponse struct {
+		Results map[string]interface{} `json:"results"`
+		Errors  map[string]string      `json:"errors"`
+	}
+
+	batchResult struct {
+		key    string
+		result interface{}
+		err    error
+	}
+)
+
+// FanOut runs the request of every key concurrently and collects the results and errors by key.
+// Requests still running after the timeout are reported with ErrTimeout.
+func FanOut(keys []string, timeout time.Duration, request func(key string) (interface{}, error)) BatchResponse {
+	response := BatchResponse{
+		Results: make(map[string]interface{}),
+		Errors:  make(map[string]string),
+	}
+	pending := make(map[string]bool, len(keys))
+	results := make(chan batchResult, len(keys))
+	for _, key := range keys {
+		if pending[key] {
+			continue
+		}
+		pending[key] = true
+		go func(key string) {
+			result, err := request(key)
+			results <- batchResult{key: key, result: result, err: err}
+		}(key)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.key)
+			if r.err != nil {
+				response.Errors[r.key] = r.err.Error()
+				continue
+			}
+			response.Results[r.key] = r.result
+		case <-timer.C:
+			for key := range pending {
+				response.Errors[key] = ErrTimeout.Error()
+			}
+			return response
+		}
+	}
+	return response
+}
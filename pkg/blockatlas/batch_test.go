@@ -0,0 +1,40 @@
+package blockatlas
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanOut(t *testing.T) {
+	response := FanOut([]string{"bitcoin", "ethereum", "tezos", "bitcoin"}, 50*time.Millisecond, func(key string) (interface{}, error) {
+		switch key {
+		case "ethereum":
+			return nil, ErrSourceConn
+		case "tezos":
+			time.Sleep(time.Second)
+			return "late", nil
+		default:
+			return "675000", nil
+		}
+	})
+
+	assert.Equal(t, map[string]interface{}{"bitcoin": "675000"}, response.Results)
+	assert.Equal(t, map[string]string{
+		"ethereum": ErrSourceConn.Error(),
+		"tezos":    ErrTimeout.Error(),
+	}, response.Errors)
+}
+
+func TestFanOut_Empty(t *testing.T) {
+	response := FanOut(nil, time.Second, func(key string) (interface{}, error) {
+		return nil, errors.New("unexpected")
+	})
+
+	raw, err := json.Marshal(response)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"results":{},"errors":{}}`, string(raw))
+}
@@ -0,0 +1,115 @@
+package blockatlas
+
+import "github.com/trustwallet/golibs/types"
+
+// The category taxonomy is shared across coins: platforms normalize their transactions to the shared
+// metadata types, the categories are derived from these only.
+const (
+	// TxCategorySend moves coins, tokens or collectibles between addresses
+	TxCategorySend TxCategory = "send"
+	// TxCategorySwap exchanges an asset for another, token swaps and DEX orders
+	TxCategorySwap TxCategory = "swap"
+	// TxCategoryStake delegates or undelegates to a validator
+	TxCategoryStake TxCategory = "stake"
+	// TxCategoryReward claims staking rewards
+	TxCategoryReward TxCategory = "reward"
+	// TxCategoryFee only spent its fee, the transaction failed
+	TxCategoryFee TxCategory = "fee"
+	// TxCategoryContract calls a contract or manages a token: approvals, issuance, mints and burns
+	TxCategoryContract TxCategory = "contract"
+)
+
+// Groups of categories, only used to filter. The groups match the transactions by what they attempted,
+// failed ones included, as the category filter always did.
+const (
+	TxCategoryAll TxCategory = "all"
+	// TxCategoryStaking groups stake and reward
+	TxCategoryStaking TxCategory = "staking"
+	// TxCategoryTransfer groups everything but staking: send, swap, contract and the fees of these
+	TxCategoryTransfer TxCategory = "transfer"
+)
+
+// TxCategory is the kind of activity a transaction represents, more granular than the transaction type
+type TxCategory string
+
+func (c TxCategory) IsValid() bool {
+	switch c {
+	case TxCategoryAll, TxCategoryStaking, TxCategoryTransfer, TxCategorySend, TxCategorySwap,
+		TxCategoryStake, TxCategoryReward, TxCategoryFee, TxCategoryContract:
+		return true
+	default:
+		return false
+	}
+}
+
+// Includes reports whether the category, or group of categories, includes a transaction
+func (c TxCategory) Includes(tx types.Tx) bool {
+	switch c {
+	case TxCategoryAll:
+		return true
+	case TxCategoryStaking:
+		return isStaking(tx)
+	case TxCategoryTransfer:
+		return !isStaking(tx)
+	default:
+		return c == GetTxCategory(tx)
+	}
+}
+
+// GetTxCategory derives the category from the normalized transaction metadata.
+// Failed transactions only spent their fee whatever they attempted.
+func GetTxCategory(tx types.Tx) TxCategory {
+	if tx.Status == types.StatusError {
+		return TxCategoryFee
+	}
+	return getAttemptedCategory(tx)
+}
+
+// isStaking reports whether the transaction delegates, undelegates or claims rewards, even if it failed
+func isStaking(tx types.Tx) bool {
+	category := getAttemptedCategory(tx)
+	return category == TxCategoryStake || category == TxCategoryReward
+}
+
+func getAttemptedCategory(tx types.Tx) TxCategory {
+	switch meta := tx.Meta.(type) {
+	case types.AnyAction:
+		return getActionCategory(meta.Key)
+	case *types.AnyAction:
+		return getActionCategory(meta.Key)
+	case types.TokenSwap, *types.TokenSwap:
+		return TxCategorySwap
+	case types.ContractCall, *types.ContractCall:
+		return TxCategoryContract
+	default:
+		return TxCategorySend
+	}
+}
+
+func getActionCategory(key types.KeyType) TxCategory {
+	switch key {
+	case types.KeyStakeDelegate:
+		return TxCategoryStake
+	case types.KeyStakeClaimRewards:
+		return TxCategoryReward
+	case types.KeyPlaceOrder, types.KeyCancelOrder:
+		return TxCategorySwap
+	case types.KeyApproveToken, types.KeyIssueToken, types.KeyBurnToken, types.KeyMintToken:
+		return TxCategoryContract
+	default:
+		return TxCategorySend
+	}
+}
+
+func FilterTxsByCategory(txs types.Txs, category TxCategory) types.Txs {
+	if category == TxCategoryAll {
+		return txs
+	}
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if category.Includes(tx) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
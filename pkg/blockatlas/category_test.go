@@ -0,0 +1,80 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+var delegationTx = types.Tx{
+	ID:   "E4B5D1A01B5F3E1AC6B8E9A8B6A2D4C7E0F1A2B3C4D5E6F708192A3B4C5D6E7F",
+	Coin: coin.COSMOS,
+	From: "cosmos1rw62phusuv9vzraezr55k0vsqssvz6ed52zyrl",
+	To:   "cosmosvaloper1ey69r37gfxvxg62sh4r0ktpuc46pzjrm873ae8",
+	Type: types.TxAnyAction,
+	Meta: types.AnyAction{
+		Coin:  coin.COSMOS,
+		Title: types.AnyActionDelegation,
+		Key:   types.KeyStakeDelegate,
+		Value: "1000000",
+	},
+}
+
+func TestTxCategory_IsValid(t *testing.T) {
+	assert.True(t, TxCategoryAll.IsValid())
+	assert.True(t, TxCategoryStaking.IsValid())
+	assert.True(t, TxCategoryTransfer.IsValid())
+	assert.True(t, TxCategorySend.IsValid())
+	assert.True(t, TxCategorySwap.IsValid())
+	assert.False(t, TxCategory("trade").IsValid())
+	assert.False(t, TxCategory("").IsValid())
+}
+
+func TestGetTxCategory(t *testing.T) {
+	assert.Equal(t, TxCategoryStake, GetTxCategory(delegationTx))
+	assert.Equal(t, TxCategorySend, GetTxCategory(transferTx))
+
+	tests := []struct {
+		meta interface{}
+		want TxCategory
+	}{
+		{&types.AnyAction{Key: types.KeyStakeClaimRewards}, TxCategoryReward},
+		{&types.AnyAction{Key: types.KeyPlaceOrder}, TxCategorySwap},
+		{types.AnyAction{Key: types.KeyApproveToken}, TxCategoryContract},
+		{types.TokenSwap{}, TxCategorySwap},
+		{types.ContractCall{Input: "0xd0e30db0", Value: "1000"}, TxCategoryContract},
+		{types.TokenTransfer{Value: "1"}, TxCategorySend},
+		{types.CollectibleTransfer{Name: "Kitty"}, TxCategorySend},
+	}
+	for _, tt := range tests {
+		tx := transferTx
+		tx.Meta = tt.meta
+		assert.Equal(t, tt.want, GetTxCategory(tx), tt.meta)
+	}
+
+	failed := delegationTx
+	failed.Status = types.StatusError
+	assert.Equal(t, TxCategoryFee, GetTxCategory(failed))
+}
+
+func TestFilterTxsByCategory(t *testing.T) {
+	reward := delegationTx
+	reward.Meta = types.AnyAction{Key: types.KeyStakeClaimRewards}
+	swap := transferTx
+	swap.Meta = types.TokenSwap{}
+	failedDelegation := delegationTx
+	failedDelegation.Status = types.StatusError
+	failedSwap := swap
+	failedSwap.Status = types.StatusError
+	txs := types.Txs{transferTx, delegationTx, reward, swap, failedDelegation, failedSwap}
+	assert.Equal(t, txs, FilterTxsByCategory(txs, TxCategoryAll))
+	assert.Equal(t, types.Txs{delegationTx, reward, failedDelegation}, FilterTxsByCategory(txs, TxCategoryStaking))
+	assert.Equal(t, types.Txs{reward}, FilterTxsByCategory(txs, TxCategoryReward))
+	assert.Equal(t, types.Txs{swap}, FilterTxsByCategory(txs, TxCategorySwap))
+	assert.Equal(t, types.Txs{transferTx}, FilterTxsByCategory(txs, TxCategorySend))
+	assert.Equal(t, types.Txs{failedDelegation, failedSwap}, FilterTxsByCategory(txs, TxCategoryFee))
+	// transfer keeps its meaning of the category filter: everything but staking
+	assert.Equal(t, types.Txs{transferTx, swap, failedSwap}, FilterTxsByCategory(txs, TxCategoryTransfer))
+}
@@ -0,0 +1,57 @@
+package blockatlas
+
+import (
+	"sync"
+
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type (
+	// MemoFilter clears the memos not allowed, see FilterTxsByMemo
+	MemoFilter func(txs types.Txs, trusted TrustedTokens) types.Txs
+
+	// TokenFilter keeps the transactions of the token, see FilterTxsByToken
+	TokenFilter func(txs types.Txs, token string, trusted TrustedTokens) types.Txs
+
+	// CoinTxFilters replace the memo and token filters of a coin with quirks, e.g. IBC transfers.
+	// A nil filter keeps the default one.
+	CoinTxFilters struct {
+		Memo  MemoFilter
+		Token TokenFilter
+	}
+)
+
+var (
+	coinTxFiltersMu sync.RWMutex
+	coinTxFilters   = make(map[uint]CoinTxFilters)
+)
+
+// RegisterCoinTxFilters sets the filters applied to the transactions of the coin in place of the defaults
+func RegisterCoinTxFilters(coinID uint, filters CoinTxFilters) {
+	coinTxFiltersMu.Lock()
+	defer coinTxFiltersMu.Unlock()
+	coinTxFilters[coinID] = filters
+}
+
+func getCoinTxFilters(c coin.Coin) CoinTxFilters {
+	coinTxFiltersMu.RLock()
+	defer coinTxFiltersMu.RUnlock()
+	return coinTxFilters[c.ID]
+}
+
+// FilterCoinTxsByMemo applies the memo filter registered for the coin, FilterTxsByMemo by default
+func FilterCoinTxsByMemo(c coin.Coin, txs types.Txs, trusted TrustedTokens) types.Txs {
+	if filter := getCoinTxFilters(c).Memo; filter != nil {
+		return filter(txs, trusted)
+	}
+	return FilterTxsByMemo(txs, trusted)
+}
+
+// FilterCoinTxsByToken applies the token filter registered for the coin, FilterTxsByToken by default
+func FilterCoinTxsByToken(c coin.Coin, txs types.Txs, token string, trusted TrustedTokens) types.Txs {
+	if filter := getCoinTxFilters(c).Token; filter != nil {
+		return filter(txs, token, trusted)
+	}
+	return FilterTxsByToken(txs, token, trusted)
+}
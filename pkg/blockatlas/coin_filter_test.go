@@ -0,0 +1,35 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestFilterCoinTxs(t *testing.T) {
+	txs := types.Txs{
+		{ID: "a", Coin: coin.KAVA, Memo: "ibc transfer", Meta: types.TokenTransfer{TokenID: "ibc/27394FB"}},
+		{ID: "b", Coin: coin.KAVA, Memo: "123", Meta: types.Transfer{}},
+	}
+	RegisterCoinTxFilters(coin.KAVA, CoinTxFilters{
+		Token: func(txs types.Txs, token string, _ TrustedTokens) types.Txs {
+			return txs[:1]
+		},
+	})
+	defer RegisterCoinTxFilters(coin.KAVA, CoinTxFilters{})
+
+	// the memo filter is not registered, the default one applies
+	filtered := FilterCoinTxsByMemo(coin.Kava(), txs, nil)
+	assert.Equal(t, "", filtered[0].Memo)
+	assert.Equal(t, "123", filtered[1].Memo)
+
+	filtered = FilterCoinTxsByToken(coin.Kava(), txs, "ukava", nil)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "a", filtered[0].ID)
+
+	// other coins keep the default filters
+	filtered = FilterCoinTxsByToken(coin.Cosmos(), txs, "ukava", nil)
+	assert.Empty(t, filtered)
+}
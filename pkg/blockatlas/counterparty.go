@@ -0,0 +1,114 @@
+package blockatlas
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+// ContractCacheExpiration bounds the time a contract lookup is shared, accounts may get code deployed
+const ContractCacheExpiration = 24 * time.Hour
+
+const (
+	CounterpartyTypeAll      CounterpartyType = ""
+	CounterpartyTypeContract CounterpartyType = "contract"
+	CounterpartyTypeEOA      CounterpartyType = "eoa"
+)
+
+// CounterpartyType tells whether the other party of a transaction is a contract or an externally owned account
+type CounterpartyType string
+
+func (t CounterpartyType) IsValid() bool {
+	switch t {
+	case CounterpartyTypeAll, CounterpartyTypeContract, CounterpartyTypeEOA:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContractCache shares the contract lookups across requests, see db.Cache
+type ContractCache interface {
+	MemorySet(key string, data []byte, exp time.Duration) error
+	MemoryGet(key string) ([]byte, error)
+}
+
+// ContractLookup memoizes the contract lookups of a request, counterparties repeat across transactions.
+// Lookups are also shared across requests through the shared cache, when set.
+type ContractLookup struct {
+	api    ContractAPI
+	cache  map[string]bool
+	shared ContractCache
+}
+
+func NewContractLookup(api ContractAPI, shared ContractCache) *ContractLookup {
+	return &ContractLookup{api: api, cache: make(map[string]bool), shared: shared}
+}
+
+func (l *ContractLookup) IsContract(address string) (bool, error) {
+	key := strings.ToLower(address)
+	if isContract, ok := l.cache[key]; ok {
+		return isContract, nil
+	}
+	sharedKey := "contract_" + strconv.Itoa(int(l.api.Coin().ID)) + "_" + key
+	if l.shared != nil {
+		if data, err := l.shared.MemoryGet(sharedKey); err == nil {
+			l.cache[key] = string(data) == "1"
+			return l.cache[key], nil
+		}
+	}
+	isContract, err := l.api.IsContract(address)
+	if err != nil {
+		return false, err
+	}
+	l.cache[key] = isContract
+	if l.shared != nil {
+		data := "0"
+		if isContract {
+			data = "1"
+		}
+		_ = l.shared.MemorySet(sharedKey, []byte(data), ContractCacheExpiration)
+	}
+	return isContract, nil
+}
+
+// GetCounterparty returns the other party of the transaction of the address. Token transfers are
+// between the addresses of their metadata, the transaction is sent to the token contract.
+func GetCounterparty(tx types.Tx, address string) string {
+	from, to := tx.From, tx.To
+	switch meta := tx.Meta.(type) {
+	case types.TokenTransfer:
+		from, to = meta.From, meta.To
+	case *types.TokenTransfer:
+		from, to = meta.From, meta.To
+	}
+	if strings.EqualFold(from, address) {
+		return to
+	}
+	return from
+}
+
+// FilterTxsByCounterpartyType keeps the transactions of the address whose counterparty has the type
+func FilterTxsByCounterpartyType(txs types.Txs, address string, counterpartyType CounterpartyType, lookup *ContractLookup) (types.Txs, error) {
+	if counterpartyType == CounterpartyTypeAll {
+		return txs, nil
+	}
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		counterparty := GetCounterparty(tx, address)
+		// contract creations have no recipient
+		isContract := counterparty == ""
+		if !isContract {
+			var err error
+			if isContract, err = lookup.IsContract(counterparty); err != nil {
+				return nil, err
+			}
+		}
+		if isContract == (counterpartyType == CounterpartyTypeContract) {
+			result = append(result, tx)
+		}
+	}
+	return result, nil
+}
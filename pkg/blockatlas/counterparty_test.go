@@ -0,0 +1,107 @@
+package blockatlas
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type contractPlatform struct {
+	contracts map[string]bool
+	lookups   *int
+}
+
+func (p contractPlatform) Coin() coin.Coin {
+	return coin.Ethereum()
+}
+
+func (p contractPlatform) IsContract(address string) (bool, error) {
+	*p.lookups++
+	if address == "0xbroken" {
+		return false, errors.New("lookup failed")
+	}
+	return p.contracts[address], nil
+}
+
+func TestGetCounterparty(t *testing.T) {
+	sent := types.Tx{From: "0xOwn", To: "0xother", Meta: types.Transfer{Value: "1"}}
+	received := types.Tx{From: "0xother", To: "0xown", Meta: types.Transfer{Value: "1"}}
+	token := types.Tx{From: "0xown", To: "0xtoken", Meta: types.TokenTransfer{From: "0xown", To: "0xother", Value: "1"}}
+	assert.Equal(t, "0xother", GetCounterparty(sent, "0xown"))
+	assert.Equal(t, "0xother", GetCounterparty(received, "0xown"))
+	assert.Equal(t, "0xother", GetCounterparty(token, "0xown"))
+}
+
+func TestFilterTxsByCounterpartyType(t *testing.T) {
+	toEOA := types.Tx{ID: "eoa", From: "0xown", To: "0xalice", Meta: types.Transfer{Value: "1"}}
+	fromEOA := types.Tx{ID: "eoa2", From: "0xAlice", To: "0xown", Meta: types.Transfer{Value: "1"}}
+	toContract := types.Tx{ID: "contract", From: "0xown", To: "0xdex", Meta: types.ContractCall{Value: "0"}}
+	creation := types.Tx{ID: "creation", From: "0xown", Meta: types.ContractCall{Value: "0"}}
+	txs := types.Txs{toEOA, fromEOA, toContract, creation}
+
+	lookups := 0
+	lookup := NewContractLookup(contractPlatform{contracts: map[string]bool{"0xdex": true}, lookups: &lookups}, nil)
+	result, err := FilterTxsByCounterpartyType(txs, "0xown", CounterpartyTypeContract, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, types.Txs{toContract, creation}, result)
+	// the counterparties are looked up once per request, whatever their case
+	assert.Equal(t, 2, lookups)
+
+	result, err = FilterTxsByCounterpartyType(txs, "0xown", CounterpartyTypeEOA, lookup)
+	assert.Nil(t, err)
+	assert.Equal(t, types.Txs{toEOA, fromEOA}, result)
+	assert.Equal(t, 2, lookups)
+
+	result, err = FilterTxsByCounterpartyType(txs, "0xown", CounterpartyTypeAll, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, txs, result)
+
+	broken := types.Tx{ID: "broken", From: "0xown", To: "0xbroken", Meta: types.Transfer{Value: "1"}}
+	_, err = FilterTxsByCounterpartyType(types.Txs{broken}, "0xown", CounterpartyTypeEOA, lookup)
+	assert.EqualError(t, err, "lookup failed")
+}
+
+// memoryCache is a ContractCache without expiration
+type memoryCache map[string][]byte
+
+func (c memoryCache) MemorySet(key string, data []byte, exp time.Duration) error {
+	c[key] = data
+	return nil
+}
+
+func (c memoryCache) MemoryGet(key string) ([]byte, error) {
+	data, ok := c[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func TestContractLookup_Shared(t *testing.T) {
+	lookups := 0
+	api := contractPlatform{contracts: map[string]bool{"0xdex": true}, lookups: &lookups}
+	shared := memoryCache{}
+
+	isContract, err := NewContractLookup(api, shared).IsContract("0xdex")
+	assert.Nil(t, err)
+	assert.True(t, isContract)
+	isContract, err = NewContractLookup(api, shared).IsContract("0xDEX")
+	assert.Nil(t, err)
+	assert.True(t, isContract)
+	isContract, err = NewContractLookup(api, shared).IsContract("0xalice")
+	assert.Nil(t, err)
+	assert.False(t, isContract)
+	isContract, err = NewContractLookup(api, shared).IsContract("0xalice")
+	assert.Nil(t, err)
+	assert.False(t, isContract)
+	// a lookup per address across the requests
+	assert.Equal(t, 2, lookups)
+
+	_, err = NewContractLookup(api, shared).IsContract("0xbroken")
+	assert.EqualError(t, err, "lookup failed")
+	assert.Len(t, shared, 2)
+}
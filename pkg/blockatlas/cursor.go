@@ -0,0 +1,67 @@
+package blockatlas
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// TxCursor references the newest transaction a client has with the height of its block.
+// The height tells transactions dropped or moved by a reorg apart from transactions
+// older than the lookup window of the provider.
+type TxCursor struct {
+	Height uint64
+	Hash   string
+}
+
+func NewTxCursor(tx types.Tx) TxCursor {
+	return TxCursor{Height: tx.Block, Hash: tx.ID}
+}
+
+// ParseTxCursor reads a <height>:<hash> cursor, a plain hash is a cursor without height
+func ParseTxCursor(value string) (TxCursor, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return TxCursor{Hash: value}, nil
+	}
+	height, err := strconv.ParseUint(value[:i], 10, 64)
+	if err != nil || i == len(value)-1 {
+		return TxCursor{}, errInvalidCursor
+	}
+	return TxCursor{Height: height, Hash: value[i+1:]}, nil
+}
+
+func (c TxCursor) String() string {
+	if c.Height == 0 {
+		return c.Hash
+	}
+	return fmt.Sprintf("%d:%s", c.Height, c.Hash)
+}
+
+// FilterTxsAfterCursor keeps the transactions preceding the cursor in the sorted order like FilterTxsAfterHash.
+// The cursor is reorged when its transaction moved to another block, or is missing while transactions older
+// than its block are known. All transactions are returned if the cursor is not found or reorged.
+func FilterTxsAfterCursor(txs types.Txs, cursor TxCursor) (result types.Txs, found, reorged bool) {
+	result, found = FilterTxsAfterHash(txs, cursor.Hash)
+	if cursor.Height == 0 {
+		return result, found, false
+	}
+	if found {
+		tx := txs[len(result)]
+		if tx.Block != 0 && tx.Block != cursor.Height {
+			return txs, true, true
+		}
+		return result, true, false
+	}
+	for _, tx := range txs {
+		if tx.Block != 0 && tx.Block < cursor.Height {
+			return txs, false, true
+		}
+	}
+	return txs, false, false
+}
@@ -0,0 +1,62 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestParseTxCursor(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    TxCursor
+		wantErr bool
+	}{
+		{"0xabc", TxCursor{Hash: "0xabc"}, false},
+		{"592400:0xabc", TxCursor{Height: 592400, Hash: "0xabc"}, false},
+		{"tip:0xabc", TxCursor{}, true},
+		{"592400:", TxCursor{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTxCursor(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+			if !tt.wantErr {
+				assert.Equal(t, tt.value, got.String())
+			}
+		})
+	}
+}
+
+func TestFilterTxsAfterCursor(t *testing.T) {
+	newest := types.Tx{ID: "newest", Block: 103}
+	pending := types.Tx{ID: "pending"}
+	middle := types.Tx{ID: "middle", Block: 102}
+	oldest := types.Tx{ID: "oldest", Block: 100}
+	txs := types.Txs{pending, newest, middle, oldest}
+
+	tests := []struct {
+		name        string
+		cursor      TxCursor
+		want        types.Txs
+		wantFound   bool
+		wantReorged bool
+	}{
+		{"hash only", TxCursor{Hash: "middle"}, types.Txs{pending, newest}, true, false},
+		{"same block", TxCursor{Height: 102, Hash: "middle"}, types.Txs{pending, newest}, true, false},
+		{"moved to another block", TxCursor{Height: 101, Hash: "middle"}, txs, true, true},
+		{"dropped", TxCursor{Height: 101, Hash: "dropped"}, txs, false, true},
+		{"older than the window", TxCursor{Height: 90, Hash: "old"}, txs, false, false},
+		{"pending cursor", TxCursor{Hash: "pending"}, types.Txs{}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, reorged := FilterTxsAfterCursor(txs, tt.cursor)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantReorged, reorged)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package blockatlas
+
+import "github.com/trustwallet/golibs/types"
+
+const (
+	TxDetailsLight TxDetails = ""
+	TxDetailsFull  TxDetails = "full"
+)
+
+// TxDetails defines the level of detail of UTXO transactions in responses
+type TxDetails string
+
+func (d TxDetails) IsValid() bool {
+	switch d {
+	case TxDetailsLight, TxDetailsFull:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyTxDetails returns a copy of the transactions without their per input and per output
+// arrays unless full details are requested
+func ApplyTxDetails(txs types.Txs, details TxDetails) types.Txs {
+	if details == TxDetailsFull {
+		return txs
+	}
+	result := make(types.Txs, len(txs))
+	for i, tx := range txs {
+		result[i] = tx
+		result[i].Inputs = nil
+		result[i].Outputs = nil
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestApplyTxDetails(t *testing.T) {
+	txs := types.Txs{{
+		ID:      "utxo",
+		Inputs:  []types.TxOutput{{Address: "bc1qin", Value: "100"}},
+		Outputs: []types.TxOutput{{Address: "bc1qout", Value: "90"}},
+	}}
+
+	assert.Equal(t, txs, ApplyTxDetails(txs, TxDetailsFull))
+
+	light := ApplyTxDetails(txs, TxDetailsLight)
+	assert.Nil(t, light[0].Inputs)
+	assert.Nil(t, light[0].Outputs)
+	assert.Len(t, txs[0].Inputs, 1)
+}
+
+func TestTxDetails_IsValid(t *testing.T) {
+	assert.True(t, TxDetailsLight.IsValid())
+	assert.True(t, TxDetailsFull.IsValid())
+	assert.False(t, TxDetails("none").IsValid())
+}
@@ -0,0 +1,87 @@
+package blockatlas
+
+import (
+	mapset "github.com/deckarep/golang-set"
+	"github.com/trustwallet/golibs/types"
+)
+
+// HasMissingDirection reports whether any transaction was returned without a direction
+func HasMissingDirection(txs types.Txs) bool {
+	for _, tx := range txs {
+		if tx.Direction == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTxDirection returns the direction of the transaction for the given addresses.
+// Addresses are matched in all their encodings, so a transfer to the legacy encoding
+// of a bech32 address is still incoming. Directions already set by the platform are kept.
+func GetTxDirection(tx types.Tx, addresses ...string) types.Direction {
+	if tx.Direction != "" {
+		return tx.Direction
+	}
+	return inferDirection(&tx, NewAddressSet(tx.Coin, addresses...))
+}
+
+// SetDirections returns a copy of the transactions with the direction computed relative to
+// one or more owned addresses, e.g. the queried address or the addresses derived from an xpub
+func SetDirections(txs types.Txs, ownedAddresses ...string) types.Txs {
+	result := make(types.Txs, len(txs))
+	for i, tx := range txs {
+		result[i] = tx
+		result[i].Direction = GetTxDirection(tx, ownedAddresses...)
+	}
+	return result
+}
+
+func inferDirection(tx *types.Tx, addressSet mapset.Set) types.Direction {
+	if len(tx.Inputs) > 0 && len(tx.Outputs) > 0 {
+		return types.InferDirection(tx, addressSet)
+	}
+	if len(tx.TokenTransfers) > 1 {
+		return inferBatchDirection(tx.TokenTransfers, addressSet)
+	}
+	from, to := tx.From, tx.To
+	switch meta := tx.Meta.(type) {
+	case types.TokenTransfer:
+		from, to = meta.From, meta.To
+	case *types.TokenTransfer:
+		from, to = meta.From, meta.To
+	case types.NativeTokenTransfer:
+		from, to = meta.From, meta.To
+	case *types.NativeTokenTransfer:
+		from, to = meta.From, meta.To
+	}
+	switch {
+	case addressSet.Contains(from) && addressSet.Contains(to):
+		return types.DirectionSelf
+	case addressSet.Contains(to):
+		return types.DirectionIncoming
+	default:
+		return types.DirectionOutgoing
+	}
+}
+
+// inferBatchDirection considers all the transfers of a batch send like the outputs of UTXO transactions:
+// sending to owned addresses only is self, receiving any of the transfers without sending is incoming
+func inferBatchDirection(transfers []types.TokenTransfer, addressSet mapset.Set) types.Direction {
+	sent, received, receivedAll := false, false, true
+	for _, transfer := range transfers {
+		sent = sent || addressSet.Contains(transfer.From)
+		owned := addressSet.Contains(transfer.To)
+		received = received || owned
+		receivedAll = receivedAll && owned
+	}
+	switch {
+	case sent && receivedAll:
+		return types.DirectionSelf
+	case sent:
+		return types.DirectionOutgoing
+	case received:
+		return types.DirectionIncoming
+	default:
+		return types.DirectionOutgoing
+	}
+}
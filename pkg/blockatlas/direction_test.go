@@ -0,0 +1,61 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestSetDirections(t *testing.T) {
+	addresses := []string{"bc1qown", "bc1qchange"}
+	incoming := types.Tx{
+		ID:      "incoming",
+		Inputs:  []types.TxOutput{{Address: "bc1qother", Value: "100"}},
+		Outputs: []types.TxOutput{{Address: "bc1qown", Value: "90"}},
+	}
+	outgoing := types.Tx{
+		ID:      "outgoing",
+		Inputs:  []types.TxOutput{{Address: "bc1qown", Value: "100"}},
+		Outputs: []types.TxOutput{{Address: "bc1qother", Value: "60"}, {Address: "bc1qchange", Value: "30"}},
+	}
+	self := types.Tx{
+		ID:      "self",
+		Inputs:  []types.TxOutput{{Address: "bc1qown", Value: "100"}},
+		Outputs: []types.TxOutput{{Address: "bc1qchange", Value: "90"}},
+	}
+	preset := types.Tx{ID: "preset", Direction: types.DirectionIncoming, From: "bc1qown", To: "bc1qother"}
+	account := types.Tx{ID: "account", From: "bc1qother", To: "bc1qchange"}
+
+	txs := types.Txs{incoming, outgoing, self, preset, account}
+	assert.True(t, HasMissingDirection(txs))
+
+	result := SetDirections(txs, addresses...)
+	assert.False(t, HasMissingDirection(result))
+	assert.Equal(t, types.DirectionIncoming, result[0].Direction)
+	assert.Equal(t, types.DirectionOutgoing, result[1].Direction)
+	assert.Equal(t, types.DirectionSelf, result[2].Direction)
+	assert.Equal(t, types.DirectionIncoming, result[3].Direction)
+	assert.Equal(t, types.DirectionIncoming, result[4].Direction)
+	assert.Equal(t, types.Direction(""), txs[0].Direction)
+}
+
+func TestGetTxDirection_Encodings(t *testing.T) {
+	tx := types.Tx{
+		Coin:    coin.BITCOIN,
+		Inputs:  []types.TxOutput{{Address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", Value: "100"}},
+		Outputs: []types.TxOutput{{Address: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", Value: "90"}},
+	}
+	assert.Equal(t, types.DirectionIncoming, GetTxDirection(tx, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"))
+
+	tx = types.Tx{
+		Coin: coin.BITCOIN,
+		From: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		To:   "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+	}
+	assert.Equal(t, types.DirectionSelf, GetTxDirection(tx, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"))
+
+	tx.Direction = types.DirectionOutgoing
+	assert.Equal(t, types.DirectionOutgoing, GetTxDirection(tx, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"))
+}
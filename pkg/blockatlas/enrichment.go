@@ -0,0 +1,86 @@
+package blockatlas
+
+import "fmt"
+
+// Names of the enrichers, see NewEnrichmentPipeline
+const (
+	EnricherBlockHash = "block_hash"
+	EnricherLabels    = "labels"
+	EnricherSpam      = "spam"
+	EnricherAssetType = "asset_type"
+)
+
+// DefaultEnrichments are the enrichers applied when a deployment doesn't configure them
+var DefaultEnrichments = []string{EnricherBlockHash, EnricherLabels, EnricherSpam, EnricherAssetType}
+
+type (
+	// TxEnrichment holds the request scoped data available to the enrichers, zero values are skipped
+	TxEnrichment struct {
+		Extras TxExtrasIndex
+		Labels TxLabels
+	}
+
+	// TxEnricher adds derived fields to a page of transactions
+	TxEnricher interface {
+		Enrich(txs Txs, e TxEnrichment)
+	}
+
+	// EnrichmentPipeline applies its enrichers in order
+	EnrichmentPipeline []TxEnricher
+
+	// BlockHashEnricher fills the block hashes returned by the provider
+	BlockHashEnricher struct{}
+
+	// LabelEnricher fills the labels attached to the transactions
+	LabelEnricher struct{}
+
+	// SpamEnricher flags the transfers of spam tokens
+	SpamEnricher struct {
+		Tokens *SpamTokens
+	}
+
+	// AssetTypeEnricher surfaces the kind of asset moved
+	AssetTypeEnricher struct{}
+)
+
+// NewEnrichmentPipeline returns the pipeline of the named enrichers in order
+func NewEnrichmentPipeline(names []string, spam *SpamTokens) (EnrichmentPipeline, error) {
+	pipeline := make(EnrichmentPipeline, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case EnricherBlockHash:
+			pipeline = append(pipeline, BlockHashEnricher{})
+		case EnricherLabels:
+			pipeline = append(pipeline, LabelEnricher{})
+		case EnricherSpam:
+			pipeline = append(pipeline, SpamEnricher{Tokens: spam})
+		case EnricherAssetType:
+			pipeline = append(pipeline, AssetTypeEnricher{})
+		default:
+			return nil, fmt.Errorf("unknown enricher %q", name)
+		}
+	}
+	return pipeline, nil
+}
+
+func (p EnrichmentPipeline) Enrich(txs Txs, e TxEnrichment) {
+	for _, enricher := range p {
+		enricher.Enrich(txs, e)
+	}
+}
+
+func (BlockHashEnricher) Enrich(txs Txs, e TxEnrichment) {
+	txs.SetBlockHashes(e.Extras)
+}
+
+func (LabelEnricher) Enrich(txs Txs, e TxEnrichment) {
+	txs.SetLabels(e.Labels)
+}
+
+func (s SpamEnricher) Enrich(txs Txs, _ TxEnrichment) {
+	txs.SetSpamFlags(s.Tokens)
+}
+
+func (AssetTypeEnricher) Enrich(txs Txs, _ TxEnrichment) {
+	txs.SetAssetTypes()
+}
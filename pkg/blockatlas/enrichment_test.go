@@ -0,0 +1,45 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestNewEnrichmentPipeline(t *testing.T) {
+	pipeline, err := NewEnrichmentPipeline(DefaultEnrichments, nil)
+	assert.Nil(t, err)
+	assert.Len(t, pipeline, 4)
+
+	_, err = NewEnrichmentPipeline([]string{EnricherSpam, "fiat"}, nil)
+	assert.EqualError(t, err, `unknown enricher "fiat"`)
+}
+
+func TestEnrichmentPipeline_Enrich(t *testing.T) {
+	transfer := types.Tx{ID: "transfer", Block: 592400, Meta: types.Transfer{Value: "1"}}
+	spam := types.Tx{ID: "spam", Meta: types.TokenTransfer{TokenID: "0xSpam", Value: "1"}}
+	enrichment := TxEnrichment{
+		Extras: TxExtrasIndex{"transfer": {BlockHash: "0000000000000000000a7b"}},
+		Labels: TxLabels{"transfer": {"rent"}},
+	}
+
+	pipeline, err := NewEnrichmentPipeline(DefaultEnrichments, NewSpamTokens([]string{"0xspam"}))
+	assert.Nil(t, err)
+	page := NewTxs(types.Txs{transfer, spam})
+	pipeline.Enrich(page, enrichment)
+	assert.Equal(t, "0000000000000000000a7b", page[0].BlockHash)
+	assert.Equal(t, []string{"rent"}, page[0].Labels)
+	assert.Equal(t, AssetTypeNative, page[0].AssetType)
+	assert.True(t, page[1].IsSpam)
+	assert.Equal(t, AssetTypeFungible, page[1].AssetType)
+
+	pipeline, err = NewEnrichmentPipeline([]string{EnricherAssetType}, nil)
+	assert.Nil(t, err)
+	page = NewTxs(types.Txs{transfer, spam})
+	pipeline.Enrich(page, enrichment)
+	assert.Empty(t, page[0].BlockHash)
+	assert.Nil(t, page[0].Labels)
+	assert.False(t, page[1].IsSpam)
+	assert.Equal(t, AssetTypeFungible, page[1].AssetType)
+}
@@ -1,6 +1,15 @@
 package blockatlas
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+
+	"github.com/trustwallet/golibs/client"
+)
 
 var (
 	// ErrSourceConn signals that the connection to the source API failed
@@ -14,4 +23,103 @@ var (
 
 	// ErrInvalidKey signals that the requested key is invalid
 	ErrInvalidKey = errors.New("invalid key")
+
+	// ErrNotSupported signals that the requested operation is not supported by the coin
+	ErrNotSupported = errors.New("not supported")
+
+	// ErrTimeout signals that the source API didn't respond in time
+	ErrTimeout = errors.New("request timed out")
+
+	// ErrResponseTooLarge signals that the source API response exceeded the size limit
+	ErrResponseTooLarge = errors.New("response too large")
+)
+
+const (
+	SourceErrorUnknown     SourceErrorReason = "unknown"
+	SourceErrorTimeout     SourceErrorReason = "timeout"
+	SourceErrorConnRefused SourceErrorReason = "connection_refused"
+	SourceErrorRateLimited SourceErrorReason = "rate_limited"
+	SourceErrorServer      SourceErrorReason = "server_error"
+	SourceErrorTooLarge    SourceErrorReason = "response_too_large"
+)
+
+type (
+	// SourceErrorReason tells why the source API failed
+	SourceErrorReason string
+
+	// SourceError is an ErrSourceConn carrying the reason and the error of the source API
+	SourceError struct {
+		Reason SourceErrorReason
+		Err    error
+	}
 )
+
+func (e *SourceError) Error() string {
+	return ErrSourceConn.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SourceError) Is(target error) bool {
+	return target == ErrSourceConn
+}
+
+// NewSourceError wraps a failure of the source API with its reason
+func NewSourceError(err error) error {
+	if err == nil || err == ErrSourceConn {
+		return ErrSourceConn
+	}
+	var sourceErr *SourceError
+	if errors.As(err, &sourceErr) {
+		return sourceErr
+	}
+	return &SourceError{Reason: GetSourceErrorReason(err), Err: err}
+}
+
+// IsSourceConnError reports whether the error is a failure of the source API
+// rather than an error of the request, e.g. a timeout or a server error
+func IsSourceConnError(err error) bool {
+	if errors.Is(err, ErrSourceConn) || err == ErrTimeout || errors.Is(err, ErrResponseTooLarge) {
+		return true
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var httpErr *client.HttpError
+	return errors.As(err, &httpErr) &&
+		(httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests)
+}
+
+// GetSourceErrorReason classifies a failure of the source API
+func GetSourceErrorReason(err error) SourceErrorReason {
+	var sourceErr *SourceError
+	if errors.As(err, &sourceErr) {
+		return sourceErr.Reason
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return SourceErrorTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return SourceErrorConnRefused
+	}
+	if errors.Is(err, ErrResponseTooLarge) {
+		return SourceErrorTooLarge
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return SourceErrorTimeout
+	}
+	var httpErr *client.HttpError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return SourceErrorRateLimited
+		case httpErr.StatusCode >= http.StatusInternalServerError:
+			return SourceErrorServer
+		}
+	}
+	return SourceErrorUnknown
+}
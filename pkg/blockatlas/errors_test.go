@@ -0,0 +1,43 @@
+package blockatlas
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/client"
+)
+
+func TestGetSourceErrorReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want SourceErrorReason
+	}{
+		{"timeout", ErrTimeout, SourceErrorTimeout},
+		{"connection refused", &url.Error{Op: "Get", URL: "http://node", Err: syscall.ECONNREFUSED}, SourceErrorConnRefused},
+		{"rate limited", &client.HttpError{StatusCode: http.StatusTooManyRequests}, SourceErrorRateLimited},
+		{"server error", &client.HttpError{StatusCode: http.StatusBadGateway}, SourceErrorServer},
+		{"wrapped", &SourceError{Reason: SourceErrorServer}, SourceErrorServer},
+		{"unknown", ErrSourceConn, SourceErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetSourceErrorReason(tt.err))
+		})
+	}
+}
+
+func TestNewSourceError(t *testing.T) {
+	err := NewSourceError(&client.HttpError{StatusCode: http.StatusTooManyRequests})
+	assert.True(t, errors.Is(err, ErrSourceConn))
+	assert.True(t, IsSourceConnError(err))
+	assert.Equal(t, ErrSourceConn.Error(), err.Error())
+	assert.Equal(t, SourceErrorRateLimited, GetSourceErrorReason(err))
+	assert.Equal(t, err, NewSourceError(err))
+	assert.Equal(t, ErrSourceConn, NewSourceError(ErrSourceConn))
+	assert.False(t, IsSourceConnError(&client.HttpError{StatusCode: http.StatusBadRequest}))
+}
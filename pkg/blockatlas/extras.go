@@ -0,0 +1,63 @@
+package blockatlas
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// TxExtras are the details a provider returns along with a transaction, beyond the golibs types.Tx
+	TxExtras struct {
+		// BlockHash is the hash of the block the transaction was included in, empty if pending
+		BlockHash string
+		// Size is zero when the provider doesn't report it
+		Size TxSize
+		// RBF marks pending transactions signalling replace-by-fee
+		RBF bool
+	}
+
+	// TxExtrasIndex indexes the extras of the transactions of a request by transaction ID
+	TxExtrasIndex map[string]TxExtras
+
+	// txExtras holds the extras of the transactions returned to a request
+	txExtras struct {
+		mu    sync.Mutex
+		index TxExtrasIndex
+	}
+
+	txExtrasKey struct{}
+)
+
+// TrackTxExtras returns a context the platforms bound to it return the extras of their transactions in, see BindContext
+func TrackTxExtras(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(txExtrasKey{}).(*txExtras); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, txExtrasKey{}, &txExtras{index: make(TxExtrasIndex)})
+}
+
+// AddTxExtras returns the extras of a transaction of a lookup to the request, it is ignored for untracked contexts
+func AddTxExtras(ctx context.Context, id string, extras TxExtras) {
+	e, ok := ctx.Value(txExtrasKey{}).(*txExtras)
+	if !ok {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.index[id] = extras
+}
+
+// GetTxExtras returns the extras of the transactions the lookups of the request returned, nil if untracked
+func GetTxExtras(ctx context.Context) TxExtrasIndex {
+	e, ok := ctx.Value(txExtrasKey{}).(*txExtras)
+	if !ok {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	index := make(TxExtrasIndex, len(e.index))
+	for id, extras := range e.index {
+		index[id] = extras
+	}
+	return index
+}
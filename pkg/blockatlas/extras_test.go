@@ -0,0 +1,25 @@
+package blockatlas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTxExtras(t *testing.T) {
+	untracked := context.Background()
+	AddTxExtras(untracked, "tx", TxExtras{RBF: true})
+	assert.Nil(t, GetTxExtras(untracked))
+
+	ctx := TrackTxExtras(untracked)
+	assert.Empty(t, GetTxExtras(ctx))
+
+	AddTxExtras(ctx, "tx", TxExtras{BlockHash: "0000000000000000000a7b", Size: TxSize{Size: 225, VSize: 144}})
+	extras := GetTxExtras(ctx)
+	assert.Equal(t, TxExtrasIndex{"tx": {BlockHash: "0000000000000000000a7b", Size: TxSize{Size: 225, VSize: 144}}}, extras)
+
+	// tracking again keeps the extras returned so far, the index returned is a copy
+	extras["other"] = TxExtras{}
+	assert.Len(t, GetTxExtras(TrackTxExtras(ctx)), 1)
+}
@@ -0,0 +1,79 @@
+package blockatlas
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// metadataFieldPrefix selects the fields of the transaction metadata
+const metadataFieldPrefix = "metadata."
+
+type (
+	// TxFields holds the JSON fields of a transaction selected by the client
+	TxFields map[string]json.RawMessage
+
+	// SparseTxPage is a TxPage whose transactions only carry the selected fields
+	SparseTxPage struct {
+		TxPage
+		Docs []TxFields `json:"docs"`
+	}
+)
+
+// ParseTxFields splits a comma separated list of transaction fields
+func ParseTxFields(param string) []string {
+	fields := make([]string, 0)
+	for _, field := range strings.Split(param, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// SelectFields projects the transactions on the given JSON fields, unknown fields are ignored.
+// Fields of the metadata are selected with the metadata. prefix, e.g. metadata.value.
+func (txs Txs) SelectFields(fields []string) ([]TxFields, error) {
+	result := make([]TxFields, 0, len(txs))
+	for _, tx := range txs {
+		selected, err := tx.selectFields(fields)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, selected)
+	}
+	return result, nil
+}
+
+func (t Tx) selectFields(fields []string) (TxFields, error) {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var all, metadata map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+	result := make(TxFields)
+	selectedMetadata := make(map[string]json.RawMessage)
+	for _, field := range fields {
+		if !strings.HasPrefix(field, metadataFieldPrefix) {
+			if value, ok := all[field]; ok {
+				result[field] = value
+			}
+			continue
+		}
+		if metadata == nil {
+			// metadata of unknown shape is not selectable
+			_ = json.Unmarshal(all["metadata"], &metadata)
+		}
+		if value, ok := metadata[strings.TrimPrefix(field, metadataFieldPrefix)]; ok {
+			selectedMetadata[strings.TrimPrefix(field, metadataFieldPrefix)] = value
+		}
+	}
+	if _, ok := result["metadata"]; !ok && len(selectedMetadata) > 0 {
+		if result["metadata"], err = json.Marshal(selectedMetadata); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
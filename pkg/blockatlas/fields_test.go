@@ -0,0 +1,52 @@
+package blockatlas
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestParseTxFields(t *testing.T) {
+	assert.Equal(t, []string{"id", "date", "metadata.value"}, ParseTxFields("id, date,,metadata.value"))
+	assert.Empty(t, ParseTxFields(""))
+}
+
+func TestTxs_SelectFields(t *testing.T) {
+	tx := transferTx
+	tx.Direction = types.DirectionOutgoing
+	txs := NewTxs(types.Txs{tx})
+
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{"top level", []string{"id", "date", "direction"}, `{"id":"95CF63FAA27579A9B6AF84EF8B2DFEAC29627479E9C98E7F5AE4535E213FA4C9","date":1555117625,"direction":"outgoing"}`},
+		{"metadata", []string{"id", "metadata.value", "metadata.unknown"}, `{"id":"95CF63FAA27579A9B6AF84EF8B2DFEAC29627479E9C98E7F5AE4535E213FA4C9","metadata":{"value":"100000"}}`},
+		{"whole metadata", []string{"metadata", "metadata.value"}, `{"metadata":{"value":"100000","symbol":"BTC","decimals":8}}`},
+		{"unknown", []string{"hash", "metadata."}, `{}`},
+		{"extension", []string{"block_height"}, `{"block_height":592400}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := txs.SelectFields(tt.fields)
+			assert.Nil(t, err)
+			assert.Len(t, docs, 1)
+			raw, err := json.Marshal(docs[0])
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.want, string(raw))
+		})
+	}
+}
+
+func TestSparseTxPage_MarshalJSON(t *testing.T) {
+	txs := NewTxs(types.Txs{transferTx})
+	docs, err := txs.SelectFields([]string{"id"})
+	assert.Nil(t, err)
+
+	raw, err := json.Marshal(SparseTxPage{TxPage: NewTxPage(txs, 8), Docs: docs})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"total":1,"docs":[{"id":"95CF63FAA27579A9B6AF84EF8B2DFEAC29627479E9C98E7F5AE4535E213FA4C9"}],"status":true,"decimals":8,"provider":"","fetched_at":0,"from_cache":false}`, string(raw))
+}
@@ -0,0 +1,235 @@
+package blockatlas
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+// TxFilter holds the history filters a provider may apply upstream, see TxFilterAPI
+type TxFilter struct {
+	AssetType AssetType
+}
+
+// GetConfirmations returns the confirmation depth of a transaction for the given chain head.
+// Pending transactions and transactions above the head have no confirmations.
+func GetConfirmations(tx types.Tx, currentBlock int64) int64 {
+	if tx.Status == types.StatusPending || tx.Block == 0 || currentBlock < int64(tx.Block) {
+		return 0
+	}
+	return currentBlock - int64(tx.Block) + 1
+}
+
+// FilterUniqueTxs keeps one transaction by ID, at the position of its first occurrence. Unlike
+// types.Txs.FilterUniqueID it keeps the most finalized version whatever the order: confirmed over
+// pending, then the one with the most confirmations, i.e. in the lowest block, then the first one.
+func FilterUniqueTxs(txs types.Txs) types.Txs {
+	index := make(map[string]int, len(txs))
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		i, ok := index[tx.ID]
+		if !ok {
+			index[tx.ID] = len(result)
+			result = append(result, tx)
+			continue
+		}
+		if isMoreFinalized(tx, result[i]) {
+			result[i] = tx
+		}
+	}
+	return result
+}
+
+// isMoreFinalized reports whether tx is a more finalized version of the same transaction than other
+func isMoreFinalized(tx, other types.Tx) bool {
+	txPending, otherPending := IsPendingTx(tx) || tx.Block == 0, IsPendingTx(other) || other.Block == 0
+	if txPending != otherPending {
+		return !txPending
+	}
+	return !txPending && tx.Block < other.Block
+}
+
+func FilterTxsByConfirmations(txs types.Txs, currentBlock, minConfirmations int64) types.Txs {
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if tx.Status == types.StatusError {
+			continue
+		}
+		if GetConfirmations(tx, currentBlock) >= minConfirmations {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+func FilterTxsByDirection(txs types.Txs, address string, direction types.Direction) types.Txs {
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if GetTxDirection(tx, address) == direction {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// FilterTxsSince keeps transactions included after the given block and timestamp, zero values are ignored
+func FilterTxsSince(txs types.Txs, block uint64, timestamp int64) types.Txs {
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if block > 0 && tx.Block <= block {
+			continue
+		}
+		if timestamp > 0 && tx.Date <= timestamp {
+			continue
+		}
+		result = append(result, tx)
+	}
+	return result
+}
+
+// FilterTxsUntil keeps transactions included before or at the given timestamp, zero is ignored
+func FilterTxsUntil(txs types.Txs, timestamp int64) types.Txs {
+	if timestamp == 0 {
+		return txs
+	}
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if tx.Date <= timestamp {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// HasZeroValue reports whether the transaction moves no value: token approvals,
+// contract calls without transfer and transfers of a zero amount
+func HasZeroValue(tx types.Tx) bool {
+	switch meta := tx.Meta.(type) {
+	case types.ContractCall:
+		return isZeroAmount(meta.Value)
+	case *types.ContractCall:
+		return isZeroAmount(meta.Value)
+	case types.AnyAction:
+		return meta.Key == types.KeyApproveToken || isZeroAmount(string(meta.Value))
+	case *types.AnyAction:
+		return meta.Key == types.KeyApproveToken || isZeroAmount(string(meta.Value))
+	}
+	value, ok := GetTxValue(tx)
+	return ok && isZeroAmount(string(value))
+}
+
+// FilterTxsZeroValue drops the transactions moving no value
+func FilterTxsZeroValue(txs types.Txs) types.Txs {
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		if !HasZeroValue(tx) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// FilterTxsByValue keeps the transactions moving a value within the bounds, in the smallest unit
+// of the asset moved. Nil bounds are ignored, transactions without a single value are dropped when bounded
+func FilterTxsByValue(txs types.Txs, min, max *big.Int) types.Txs {
+	if min == nil && max == nil {
+		return txs
+	}
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		raw, ok := GetTxValue(tx)
+		if !ok {
+			continue
+		}
+		value, ok := new(big.Int).SetString(string(raw), 10)
+		if !ok || (min != nil && value.Cmp(min) < 0) || (max != nil && value.Cmp(max) > 0) {
+			continue
+		}
+		result = append(result, tx)
+	}
+	return result
+}
+
+func isZeroAmount(amount string) bool {
+	if amount == "" {
+		return true
+	}
+	value, ok := new(big.Int).SetString(amount, 10)
+	return ok && value.Sign() == 0
+}
+
+// FilterTxsNotIn drops the transactions with one of the given hashes, compared case insensitively
+func FilterTxsNotIn(txs types.Txs, hashes []string) types.Txs {
+	known := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		known[strings.ToLower(hash)] = true
+	}
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		if !known[strings.ToLower(tx.ID)] {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// FilterTxsAfterHash keeps the transactions preceding the one with the given hash in the sorted order.
+// All transactions are returned if the hash is not found.
+func FilterTxsAfterHash(txs types.Txs, hash string) (types.Txs, bool) {
+	for i, tx := range txs {
+		if tx.ID == hash {
+			return txs[:i], true
+		}
+	}
+	return txs, false
+}
+
+// IsPendingTx tells transactions waiting in the mempool, their date is the time they were first seen
+func IsPendingTx(tx types.Tx) bool {
+	return tx.Status == types.StatusPending
+}
+
+// SortTxs sorts transactions from the newest to the oldest. Pending transactions come first by first seen
+// time as they are not in a block yet, whatever the block time of the confirmed ones. Transactions of the
+// same time are ordered by block height, then sequence, then hash so that pages are deterministic.
+func SortTxs(txs types.Txs) types.Txs {
+	sort.SliceStable(txs, func(i, j int) bool {
+		if pending := IsPendingTx(txs[i]); pending != IsPendingTx(txs[j]) {
+			return pending
+		}
+		if txs[i].Date != txs[j].Date {
+			return txs[i].Date > txs[j].Date
+		}
+		if txs[i].Block != txs[j].Block {
+			return txs[i].Block > txs[j].Block
+		}
+		if txs[i].Sequence != txs[j].Sequence {
+			return txs[i].Sequence > txs[j].Sequence
+		}
+		return txs[i].ID < txs[j].ID
+	})
+	return txs
+}
+
+// SortTxsAscending sorts transactions from the oldest to the newest, pending transactions last.
+// Transactions of the same block and time are in the reverse order of SortTxs.
+func SortTxsAscending(txs types.Txs) types.Txs {
+	sort.SliceStable(txs, func(i, j int) bool {
+		if pending := IsPendingTx(txs[i]); pending != IsPendingTx(txs[j]) {
+			return !pending
+		}
+		if txs[i].Block != txs[j].Block {
+			return txs[i].Block < txs[j].Block
+		}
+		if txs[i].Date != txs[j].Date {
+			return txs[i].Date < txs[j].Date
+		}
+		if txs[i].Sequence != txs[j].Sequence {
+			return txs[i].Sequence < txs[j].Sequence
+		}
+		return txs[i].ID > txs[j].ID
+	})
+	return txs
+}
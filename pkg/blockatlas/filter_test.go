@@ -0,0 +1,231 @@
+package blockatlas
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetConfirmations(t *testing.T) {
+	pending := transferTx
+	pending.Status = types.StatusPending
+
+	assert.Equal(t, int64(1), GetConfirmations(transferTx, 592400))
+	assert.Equal(t, int64(6), GetConfirmations(transferTx, 592405))
+	assert.Equal(t, int64(0), GetConfirmations(transferTx, 592399))
+	assert.Equal(t, int64(0), GetConfirmations(pending, 592405))
+}
+
+func TestFilterUniqueTxs(t *testing.T) {
+	pending := transferTx
+	pending.Status, pending.Block = types.StatusPending, 0
+	confirmed := transferTx
+	other := transferTx
+	other.ID = "other"
+	reorged := transferTx
+	reorged.Block = transferTx.Block + 1
+
+	tests := []struct {
+		name string
+		txs  types.Txs
+		want types.Txs
+	}{
+		{"pending then confirmed", types.Txs{pending, other, confirmed}, types.Txs{confirmed, other}},
+		{"confirmed then pending", types.Txs{confirmed, other, pending}, types.Txs{confirmed, other}},
+		{"most confirmations", types.Txs{reorged, confirmed}, types.Txs{confirmed}},
+		{"same version", types.Txs{confirmed, other, confirmed}, types.Txs{confirmed, other}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FilterUniqueTxs(tt.txs))
+		})
+	}
+}
+
+func TestFilterTxsByConfirmations(t *testing.T) {
+	recent := transferTx
+	recent.ID = "recent"
+	recent.Block = 592404
+	failed := transferTx
+	failed.ID = "failed"
+	failed.Status = types.StatusError
+
+	txs := types.Txs{transferTx, recent, failed}
+	assert.Equal(t, types.Txs{transferTx}, FilterTxsByConfirmations(txs, 592405, 3))
+	assert.Equal(t, types.Txs{transferTx, recent}, FilterTxsByConfirmations(txs, 592405, 1))
+}
+
+func TestFilterTxsByDirection(t *testing.T) {
+	txs := types.Txs{transferTx}
+	assert.Equal(t, txs, FilterTxsByDirection(txs, transferTx.To, types.DirectionIncoming))
+	assert.Equal(t, types.Txs{}, FilterTxsByDirection(txs, transferTx.From, types.DirectionIncoming))
+}
+
+func TestFilterTxsSince(t *testing.T) {
+	older := transferTx
+	older.ID = "older"
+	older.Block = 592300
+	older.Date = 1555000000
+
+	txs := types.Txs{transferTx, older}
+	assert.Equal(t, txs, FilterTxsSince(txs, 0, 0))
+	assert.Equal(t, types.Txs{transferTx}, FilterTxsSince(txs, 592300, 0))
+	assert.Equal(t, types.Txs{transferTx}, FilterTxsSince(txs, 0, 1555000000))
+	assert.Equal(t, types.Txs{}, FilterTxsSince(txs, 592400, 0))
+}
+
+func TestFilterTxsUntil(t *testing.T) {
+	older := transferTx
+	older.ID = "older"
+	older.Date = 1555000000
+
+	txs := types.Txs{transferTx, older}
+	assert.Equal(t, txs, FilterTxsUntil(txs, 0))
+	assert.Equal(t, types.Txs{older}, FilterTxsUntil(txs, 1555000000))
+	assert.Equal(t, types.Txs{}, FilterTxsUntil(txs, 1554999999))
+}
+
+func TestSortTxsAscending(t *testing.T) {
+	older := transferTx
+	older.ID = "older"
+	older.Block = 592300
+
+	txs := SortTxsAscending(types.Txs{transferTx, older})
+	assert.Equal(t, "older", txs[0].ID)
+	assert.Equal(t, transferTx.ID, txs[1].ID)
+}
+
+func TestSortTxs(t *testing.T) {
+	newer := transferTx
+	newer.ID = "newer"
+	newer.Date++
+	higher := transferTx
+	higher.ID = "higher"
+	higher.Block++
+	a := transferTx
+	a.ID = "a"
+	b := transferTx
+	b.ID = "b"
+	nonce := transferTx
+	nonce.ID = "c"
+	nonce.Sequence++
+
+	want := []string{"newer", "higher", "c", "a", "b"}
+	for _, txs := range []types.Txs{{b, a, nonce, higher, newer}, {a, newer, b, higher, nonce}} {
+		ids := make([]string, 0)
+		for _, tx := range SortTxs(txs) {
+			ids = append(ids, tx.ID)
+		}
+		assert.Equal(t, want, ids)
+	}
+}
+
+func TestSortTxs_Pending(t *testing.T) {
+	pending := transferTx
+	pending.ID = "pending"
+	pending.Status = types.StatusPending
+	pending.Block = 0
+	pending.Date = transferTx.Date - 600
+	confirmed := transferTx
+	confirmed.ID = "confirmed"
+
+	txs := SortTxs(types.Txs{confirmed, pending})
+	assert.Equal(t, []string{"pending", "confirmed"}, []string{txs[0].ID, txs[1].ID})
+	txs = SortTxsAscending(types.Txs{pending, confirmed})
+	assert.Equal(t, []string{"confirmed", "pending"}, []string{txs[0].ID, txs[1].ID})
+
+	page := NewTxs(types.Txs{pending, confirmed})
+	assert.Equal(t, pending.Date, page[0].FirstSeen)
+	assert.Zero(t, page[0].BlockTime)
+	assert.Equal(t, confirmed.Date, page[1].BlockTime)
+	assert.Zero(t, page[1].FirstSeen)
+}
+
+func TestFilterTxsAfterHash(t *testing.T) {
+	newer := transferTx
+	newer.ID = "newer"
+	older := transferTx
+	older.ID = "older"
+	txs := types.Txs{newer, transferTx, older}
+
+	result, found := FilterTxsAfterHash(txs, transferTx.ID)
+	assert.True(t, found)
+	assert.Equal(t, types.Txs{newer}, result)
+
+	result, found = FilterTxsAfterHash(txs, newer.ID)
+	assert.True(t, found)
+	assert.Empty(t, result)
+
+	result, found = FilterTxsAfterHash(txs, "unknown")
+	assert.False(t, found)
+	assert.Equal(t, txs, result)
+}
+
+func TestFilterTxsZeroValue(t *testing.T) {
+	zero := transferTx
+	zero.ID = "zero"
+	zero.Meta = types.TokenTransfer{TokenID: "0xdAC17F958D2ee523a2206206994597C13D831ec7", Value: "0"}
+	call := transferTx
+	call.ID = "call"
+	call.Meta = types.ContractCall{Input: "0x095ea7b3", Value: "0"}
+	payableCall := transferTx
+	payableCall.ID = "payable_call"
+	payableCall.Meta = types.ContractCall{Input: "0xd0e30db0", Value: "1000"}
+	approval := transferTx
+	approval.ID = "approval"
+	approval.Meta = types.AnyAction{Key: types.KeyApproveToken, Value: "1000"}
+	delegation := transferTx
+	delegation.ID = "delegation"
+	delegation.Meta = types.AnyAction{Key: types.KeyStakeDelegate, Value: "1000"}
+	collectible := transferTx
+	collectible.ID = "collectible"
+	collectible.Meta = types.CollectibleTransfer{Name: "Kitty"}
+
+	result := FilterTxsZeroValue(types.Txs{transferTx, zero, call, payableCall, approval, delegation, collectible})
+	ids := make([]string, 0)
+	for _, tx := range result {
+		ids = append(ids, tx.ID)
+	}
+	assert.Equal(t, []string{transferTx.ID, "payable_call", "delegation", "collectible"}, ids)
+}
+
+func TestFilterTxsByValue(t *testing.T) {
+	small := transferTx
+	small.ID = "small"
+	small.Meta = types.Transfer{Value: "10"}
+	large := transferTx
+	large.ID = "large"
+	large.Meta = types.TokenTransfer{Value: "1000000000000000000000000"}
+	action := transferTx
+	action.ID = "action"
+	action.Meta = types.AnyAction{Key: types.KeyStakeDelegate, Value: "100"}
+	txs := types.Txs{small, large, action}
+
+	ids := func(txs types.Txs) []string {
+		result := make([]string, 0)
+		for _, tx := range txs {
+			result = append(result, tx.ID)
+		}
+		return result
+	}
+	assert.Equal(t, txs, FilterTxsByValue(txs, nil, nil))
+	assert.Equal(t, []string{"large"}, ids(FilterTxsByValue(txs, big.NewInt(11), nil)))
+	assert.Equal(t, []string{"small"}, ids(FilterTxsByValue(txs, nil, big.NewInt(10))))
+	huge, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	assert.Equal(t, []string{"small", "large"}, ids(FilterTxsByValue(txs, big.NewInt(10), huge)))
+}
+
+func TestFilterTxsNotIn(t *testing.T) {
+	a := transferTx
+	a.ID = "0xAA"
+	b := transferTx
+	b.ID = "0xbb"
+	txs := types.Txs{a, b, transferTx}
+
+	assert.Equal(t, txs, FilterTxsNotIn(txs, nil))
+	assert.Equal(t, types.Txs{b}, FilterTxsNotIn(txs, []string{"0xaa", strings.ToLower(transferTx.ID), "unknown"}))
+	assert.Empty(t, FilterTxsNotIn(txs, []string{"0xAA", "0xBB", transferTx.ID}))
+}
@@ -0,0 +1,145 @@
+package blockatlas
+
+import (
+	"sync"
+	"time"
+)
+
+// ProviderStatus summarizes the recent failure rate of a provider
+type ProviderStatus string
+
+const (
+	ProviderStatusOK       ProviderStatus = "ok"
+	ProviderStatusDegraded ProviderStatus = "degraded"
+	ProviderStatusDown     ProviderStatus = "down"
+)
+
+const (
+	// HealthWindowSize is the number of recent lookups the failure rate of a provider is computed on
+	HealthWindowSize = 100
+	// minHealthRequests is the number of lookups below which a provider is reported ok
+	minHealthRequests = 5
+	// degradedFailureRate and downFailureRate are the failure rates from which a provider is degraded or down
+	degradedFailureRate = 0.1
+	downFailureRate     = 0.5
+	// HealthDecayInterval is the time after which the counts of a window are halved, so that a provider
+	// which isn't called anymore after failures recovers
+	HealthDecayInterval = time.Minute
+	// maxHealthDecays empties any window
+	maxHealthDecays = 16
+)
+
+type (
+	// HealthWindow counts the recent lookups of a provider and their failures, it is safe for concurrent use.
+	// Counts are halved past HealthWindowSize lookups and every HealthDecayInterval so that older lookups weigh less.
+	HealthWindow struct {
+		mu        sync.Mutex
+		requests  int
+		failures  int
+		decayedAt time.Time
+	}
+
+	// ProviderHealth is the recent failure rate of a provider
+	ProviderHealth struct {
+		Provider    string         `json:"provider"`
+		Requests    int            `json:"requests"`
+		FailureRate float64        `json:"failure_rate"`
+		Status      ProviderStatus `json:"status"`
+	}
+
+	// CoinHealth is down when all the providers of the coin are down, degraded when any of them is not ok
+	CoinHealth struct {
+		Status    ProviderStatus   `json:"status"`
+		Providers []ProviderHealth `json:"providers"`
+	}
+)
+
+func (w *HealthWindow) Observe(failed bool) {
+	w.ObserveAt(failed, time.Now())
+}
+
+// ObserveAt counts a lookup done at the given time
+func (w *HealthWindow) ObserveAt(failed bool, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.decay(now)
+	if w.requests >= HealthWindowSize {
+		w.requests /= 2
+		w.failures /= 2
+	}
+	w.requests++
+	if failed {
+		w.failures++
+	}
+}
+
+func (w *HealthWindow) FailureRate() float64 {
+	return w.FailureRateAt(time.Now())
+}
+
+// FailureRateAt returns the failure rate of the window decayed to the given time
+func (w *HealthWindow) FailureRateAt(now time.Time) float64 {
+	return w.ProviderHealthAt("", now).FailureRate
+}
+
+// ProviderHealth returns the health of the provider the window counts the lookups of
+func (w *HealthWindow) ProviderHealth(provider string) ProviderHealth {
+	return w.ProviderHealthAt(provider, time.Now())
+}
+
+// ProviderHealthAt returns the health of the provider with the window decayed to the given time
+func (w *HealthWindow) ProviderHealthAt(provider string, now time.Time) ProviderHealth {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.decay(now)
+	health := ProviderHealth{Provider: provider, Requests: w.requests, Status: ProviderStatusOK}
+	if w.requests == 0 {
+		return health
+	}
+	health.FailureRate = float64(w.failures) / float64(w.requests)
+	if w.requests < minHealthRequests {
+		return health
+	}
+	switch {
+	case health.FailureRate >= downFailureRate:
+		health.Status = ProviderStatusDown
+	case health.FailureRate >= degradedFailureRate:
+		health.Status = ProviderStatusDegraded
+	}
+	return health
+}
+
+// decay halves the counts for every HealthDecayInterval elapsed since the last decay
+func (w *HealthWindow) decay(now time.Time) {
+	if w.decayedAt.IsZero() {
+		w.decayedAt = now
+		return
+	}
+	decays := int(now.Sub(w.decayedAt) / HealthDecayInterval)
+	if decays <= 0 {
+		return
+	}
+	w.decayedAt = w.decayedAt.Add(time.Duration(decays) * HealthDecayInterval)
+	if decays > maxHealthDecays {
+		decays = maxHealthDecays
+	}
+	w.requests >>= uint(decays)
+	w.failures >>= uint(decays)
+}
+
+func NewCoinHealth(providers ...ProviderHealth) CoinHealth {
+	health := CoinHealth{Status: ProviderStatusOK, Providers: providers}
+	down := 0
+	for _, p := range providers {
+		if p.Status != ProviderStatusOK {
+			health.Status = ProviderStatusDegraded
+		}
+		if p.Status == ProviderStatusDown {
+			down++
+		}
+	}
+	if len(providers) > 0 && down == len(providers) {
+		health.Status = ProviderStatusDown
+	}
+	return health
+}
@@ -0,0 +1,63 @@
+package blockatlas
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthWindow(t *testing.T) {
+	var w HealthWindow
+	assert.Equal(t, ProviderHealth{Provider: "0", Status: ProviderStatusOK}, w.ProviderHealth("0"))
+
+	// too few lookups to judge
+	w.Observe(true)
+	assert.Equal(t, ProviderStatusOK, w.ProviderHealth("0").Status)
+	assert.Equal(t, float64(1), w.FailureRate())
+
+	for i := 0; i < 9; i++ {
+		w.Observe(false)
+	}
+	assert.Equal(t, ProviderStatusDegraded, w.ProviderHealth("0").Status)
+
+	for i := 0; i < 10; i++ {
+		w.Observe(true)
+	}
+	assert.Equal(t, ProviderStatusDown, w.ProviderHealth("0").Status)
+
+	for i := 0; i < 2*HealthWindowSize; i++ {
+		w.Observe(false)
+	}
+	health := w.ProviderHealth("0")
+	assert.Equal(t, ProviderStatusOK, health.Status)
+	assert.LessOrEqual(t, health.Requests, HealthWindowSize)
+}
+
+func TestHealthWindow_Decay(t *testing.T) {
+	var w HealthWindow
+	start := time.Unix(1600000000, 0)
+	for i := 0; i < 10; i++ {
+		w.ObserveAt(true, start)
+	}
+	assert.Equal(t, ProviderStatusDown, w.ProviderHealthAt("0", start.Add(HealthDecayInterval-time.Second)).Status)
+
+	// a provider which isn't called anymore recovers
+	health := w.ProviderHealthAt("0", start.Add(2*HealthDecayInterval))
+	assert.Equal(t, 2, health.Requests)
+	assert.Equal(t, ProviderStatusOK, health.Status)
+	assert.Equal(t, float64(0), w.FailureRateAt(start.Add(time.Hour)))
+}
+
+func TestNewCoinHealth(t *testing.T) {
+	ok := ProviderHealth{Provider: "0", Status: ProviderStatusOK}
+	degraded := ProviderHealth{Provider: "1", Status: ProviderStatusDegraded}
+	down := ProviderHealth{Provider: "2", Status: ProviderStatusDown}
+
+	assert.Equal(t, ProviderStatusOK, NewCoinHealth(ok).Status)
+	assert.Equal(t, ProviderStatusOK, NewCoinHealth().Status)
+	assert.Equal(t, ProviderStatusDegraded, NewCoinHealth(ok, degraded).Status)
+	assert.Equal(t, ProviderStatusDegraded, NewCoinHealth(down, ok).Status)
+	assert.Equal(t, ProviderStatusDegraded, NewCoinHealth(down, degraded).Status)
+	assert.Equal(t, ProviderStatusDown, NewCoinHealth(down, down).Status)
+}
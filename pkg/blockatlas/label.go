@@ -0,0 +1,32 @@
+package blockatlas
+
+import "github.com/trustwallet/golibs/types"
+
+// TxLabels maps transaction hashes to the labels attached to them
+type TxLabels map[string][]string
+
+func (l TxLabels) HasLabel(hash, label string) bool {
+	for _, value := range l[hash] {
+		if value == label {
+			return true
+		}
+	}
+	return false
+}
+
+func FilterTxsByLabel(txs types.Txs, labels TxLabels, label string) types.Txs {
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if labels.HasLabel(tx.ID, label) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// SetLabels fills the labels of every labelled transaction
+func (txs Txs) SetLabels(labels TxLabels) {
+	for i := range txs {
+		txs[i].Labels = labels[txs[i].ID]
+	}
+}
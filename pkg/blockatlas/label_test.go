@@ -0,0 +1,25 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestFilterTxsByLabel(t *testing.T) {
+	labels := TxLabels{"1": {"salary"}, "2": {"refund", "salary"}, "3": {"refund"}}
+	txs := types.Txs{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}
+
+	assert.Equal(t, types.Txs{{ID: "1"}, {ID: "2"}}, FilterTxsByLabel(txs, labels, "salary"))
+	assert.Equal(t, types.Txs{{ID: "2"}, {ID: "3"}}, FilterTxsByLabel(txs, labels, "refund"))
+	assert.Empty(t, FilterTxsByLabel(txs, labels, "rent"))
+}
+
+func TestTxs_SetLabels(t *testing.T) {
+	txs := NewTxs(types.Txs{{ID: "1"}, {ID: "2"}})
+	txs.SetLabels(TxLabels{"2": {"refund"}})
+
+	assert.Nil(t, txs[0].Labels)
+	assert.Equal(t, []string{"refund"}, txs[1].Labels)
+}
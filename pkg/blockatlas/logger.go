@@ -0,0 +1,36 @@
+package blockatlas
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RequestIDField is the log field of the correlation ID of a request, see Logger
+const RequestIDField = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the correlation ID of a request, from the API request
+// to the MQ messages it leads to
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the correlation ID of the context, empty without one
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Logger returns the log entry of the request of the context, carrying its correlation ID
+func Logger(ctx context.Context) *log.Entry {
+	entry := log.NewEntry(log.StandardLogger())
+	if id := RequestID(ctx); id != "" {
+		return entry.WithField(RequestIDField, id)
+	}
+	return entry
+}
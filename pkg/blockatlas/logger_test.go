@@ -0,0 +1,19 @@
+package blockatlas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", RequestID(ctx))
+	assert.NotContains(t, Logger(ctx).Data, RequestIDField)
+	assert.Equal(t, ctx, WithRequestID(ctx, ""))
+
+	ctx = WithRequestID(ctx, "abc")
+	assert.Equal(t, "abc", RequestID(ctx))
+	assert.Equal(t, "abc", Logger(ctx).Data[RequestIDField])
+}
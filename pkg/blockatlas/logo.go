@@ -0,0 +1,31 @@
+package blockatlas
+
+import "strings"
+
+// TokenLogos maps the lowercased ids of the tokens listed in the asset registry to their logo URL
+type TokenLogos map[string]string
+
+// HasTokenTransfers reports whether one of the transactions moves a token
+func (txs Txs) HasTokenTransfers() bool {
+	for i := range txs {
+		if tokenID, ok := GetTokenID(txs[i].Tx); ok && tokenID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTokenLogos fills the logo of the transferred tokens, tokens missing from the registry are flagged unknown
+func (txs Txs) SetTokenLogos(logos TokenLogos) {
+	for i := range txs {
+		tokenID, ok := GetTokenID(txs[i].Tx)
+		if !ok || tokenID == "" {
+			continue
+		}
+		if logo, ok := logos[strings.ToLower(tokenID)]; ok {
+			txs[i].TokenLogo = logo
+		} else {
+			txs[i].UnknownToken = true
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestTxs_SetTokenLogos(t *testing.T) {
+	listed := transferTx
+	listed.Meta = types.NativeTokenTransfer{TokenID: "BUSD-BD1", Value: "1"}
+	unlisted := transferTx
+	unlisted.Meta = types.NativeTokenTransfer{TokenID: "SCAM-000", Value: "1"}
+	txs := NewTxs(types.Txs{transferTx, listed, unlisted})
+	assert.True(t, txs.HasTokenTransfers())
+	assert.False(t, NewTxs(types.Txs{transferTx}).HasTokenTransfers())
+
+	txs.SetTokenLogos(TokenLogos{"busd-bd1": "https://assets/busd.png"})
+	assert.Equal(t, TxExtension{BlockHeight: transferTx.Block, BlockTime: transferTx.Date, Category: TxCategorySend}, txs[0].TxExtension)
+	assert.Equal(t, "https://assets/busd.png", txs[1].TokenLogo)
+	assert.False(t, txs[1].UnknownToken)
+	assert.Empty(t, txs[2].TokenLogo)
+	assert.True(t, txs[2].UnknownToken)
+}
@@ -0,0 +1,118 @@
+package blockatlas
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// SanitizeMemo replaces invalid UTF-8 sequences with the replacement character and drops
+// control and bidirectional formatting characters, which can alter how clients display the memo
+func SanitizeMemo(memo string) string {
+	if memo == "" {
+		return memo
+	}
+	if !utf8.ValidString(memo) {
+		memo = strings.ToValidUTF8(memo, string(utf8.RuneError))
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) {
+			return -1
+		}
+		return r
+	}, memo)
+}
+
+// SanitizeMemos returns a copy of the transactions with sanitized memos
+func SanitizeMemos(txs types.Txs) types.Txs {
+	result := make(types.Txs, len(txs))
+	for i, tx := range txs {
+		result[i] = tx
+		result[i].Memo = SanitizeMemo(tx.Memo)
+	}
+	return result
+}
+
+// TrustedTokens holds the ids of tokens with unusual memo or transfer semantics.
+// Their transactions keep non numeric memos and are not filtered by token id.
+type TrustedTokens map[string]struct{}
+
+func NewTrustedTokens(ids []string) TrustedTokens {
+	tokens := make(TrustedTokens, len(ids))
+	for _, id := range ids {
+		tokens[strings.ToLower(id)] = struct{}{}
+	}
+	return tokens
+}
+
+func (t TrustedTokens) Contains(token string) bool {
+	_, ok := t[strings.ToLower(token)]
+	return ok
+}
+
+// FilterTxsByMemo clears the memos not allowed, except on transactions of trusted tokens
+func FilterTxsByMemo(txs types.Txs, trusted TrustedTokens) types.Txs {
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		tokenID, ok := GetTokenID(tx)
+		if (!ok || !trusted.Contains(tokenID)) && !types.AllowMemo(tx.Memo) {
+			tx.Memo = ""
+		}
+		result = append(result, tx)
+	}
+	return result
+}
+
+// memoCoins tell apart the users of a shared deposit address with a memo or destination tag
+var memoCoins = map[uint]bool{
+	coin.BINANCE: true,
+	coin.COSMOS:  true,
+	coin.KAVA:    true,
+	coin.RIPPLE:  true,
+	coin.STELLAR: true,
+}
+
+func SupportsMemo(c coin.Coin) bool {
+	return memoCoins[c.ID]
+}
+
+// SharedAddresses holds by coin handle the deposit addresses shared by several users, e.g. of exchanges
+type SharedAddresses map[string]map[string]struct{}
+
+func NewSharedAddresses(addresses map[string][]string) SharedAddresses {
+	shared := make(SharedAddresses, len(addresses))
+	for handle, list := range addresses {
+		shared[handle] = make(map[string]struct{}, len(list))
+		for _, address := range list {
+			shared[handle][address] = struct{}{}
+		}
+	}
+	return shared
+}
+
+func (s SharedAddresses) IsShared(c coin.Coin, address string) bool {
+	_, ok := s[c.Handle][address]
+	return ok
+}
+
+// FilterTxsByRequiredMemo keeps the transactions tagged with the memo
+func FilterTxsByRequiredMemo(txs types.Txs, memo string) types.Txs {
+	result := make(types.Txs, 0)
+	for _, tx := range txs {
+		if strings.TrimSpace(tx.Memo) == memo {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// FilterTxsByToken keeps the transactions of the token, all of them if the token is trusted
+func FilterTxsByToken(txs types.Txs, token string, trusted TrustedTokens) types.Txs {
+	if trusted.Contains(token) {
+		return txs
+	}
+	return txs.FilterTransactionsByToken(token)
+}
@@ -0,0 +1,97 @@
+package blockatlas
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestSanitizeMemo(t *testing.T) {
+	tests := []struct {
+		name string
+		memo string
+		want string
+	}{
+		{"empty", "", ""},
+		{"numeric", "105123456", "105123456"},
+		{"unicode", "café ☕ 👨‍👩‍👧", "café ☕ 👨‍👩‍👧"},
+		{"invalid utf8", "abc\xff\xfedef", "abc\ufffddef"},
+		{"truncated sequence", "memo\xe2\x82", "memo\ufffd"},
+		{"overlong encoding", "\xc0\xafetc", "\ufffdetc"},
+		{"null bytes", "12\x0034", "1234"},
+		{"newlines and escapes", "line\r\n\x1b[31mred\x1b[0m", "line[31mred[0m"},
+		{"c1 controls", "a\u0085b\u009bc", "abc"},
+		{"bidi override", "invoice\u202egpj.exe", "invoicegpj.exe"},
+		{"bidi isolates", "\u2066123\u2069", "123"},
+		{"html is kept as text", "<script>alert(1)</script>", "<script>alert(1)</script>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeMemo(tt.memo)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, utf8.ValidString(got))
+		})
+	}
+}
+
+func TestSanitizeMemos(t *testing.T) {
+	txs := types.Txs{{ID: "1", Memo: "12\x00\xff"}, {ID: "2", Memo: "42"}}
+
+	result := SanitizeMemos(txs)
+	assert.Equal(t, "12\ufffd", result[0].Memo)
+	assert.Equal(t, "42", result[1].Memo)
+	assert.Equal(t, "12\x00\xff", txs[0].Memo)
+}
+
+func TestFilterTxsByMemo(t *testing.T) {
+	trusted := NewTrustedTokens([]string{"BUSD-BD1"})
+	txs := types.Txs{
+		{ID: "1", Memo: "hello", Meta: types.Transfer{Value: "1"}},
+		{ID: "2", Memo: "42", Meta: types.Transfer{Value: "1"}},
+		{ID: "3", Memo: "hello", Meta: types.NativeTokenTransfer{TokenID: "busd-bd1", Value: "1"}},
+		{ID: "4", Memo: "hello", Meta: types.NativeTokenTransfer{TokenID: "TWT-8C2", Value: "1"}},
+	}
+
+	result := FilterTxsByMemo(txs, trusted)
+	assert.Equal(t, "", result[0].Memo)
+	assert.Equal(t, "42", result[1].Memo)
+	assert.Equal(t, "hello", result[2].Memo)
+	assert.Equal(t, "", result[3].Memo)
+	assert.Equal(t, "hello", txs[0].Memo)
+}
+
+func TestFilterTxsByToken(t *testing.T) {
+	trusted := NewTrustedTokens([]string{"BUSD-BD1"})
+	txs := types.Txs{
+		{ID: "1", Meta: types.Transfer{Value: "1"}},
+		{ID: "2", Meta: types.NativeTokenTransfer{TokenID: "TWT-8C2", Value: "1"}},
+	}
+
+	assert.Len(t, FilterTxsByToken(txs, "TWT-8C2", trusted), 1)
+	assert.Len(t, FilterTxsByToken(txs, "busd-bd1", trusted), 2)
+	assert.Len(t, FilterTxsByToken(txs, "BNB", nil), 0)
+}
+
+func TestFilterTxsByRequiredMemo(t *testing.T) {
+	txs := types.Txs{
+		{ID: "1", Memo: "104532"},
+		{ID: "2", Memo: " 104532 "},
+		{ID: "3", Memo: "exchange-user"},
+		{ID: "4"},
+	}
+	assert.Equal(t, types.Txs{txs[0], txs[1]}, FilterTxsByRequiredMemo(txs, "104532"))
+	assert.Equal(t, types.Txs{txs[2]}, FilterTxsByRequiredMemo(txs, "exchange-user"))
+	assert.Empty(t, FilterTxsByRequiredMemo(txs, "1"))
+}
+
+func TestSharedAddresses(t *testing.T) {
+	shared := NewSharedAddresses(map[string][]string{"ripple": {"rShared"}})
+	assert.True(t, shared.IsShared(coin.Ripple(), "rShared"))
+	assert.False(t, shared.IsShared(coin.Ripple(), "rOwn"))
+	assert.False(t, shared.IsShared(coin.Stellar(), "rShared"))
+	assert.True(t, SupportsMemo(coin.Ripple()))
+	assert.False(t, SupportsMemo(coin.Bitcoin()))
+}
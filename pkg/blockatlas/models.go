@@ -6,6 +6,12 @@ type (
 	ResultsResponse struct {
 		Results interface{} `json:"docs"`
 	}
+
+	// BlockHead is the latest block of a chain, the hash is omitted when the provider doesn't report it
+	BlockHead struct {
+		Height int64  `json:"height"`
+		Hash   string `json:"hash,omitempty"`
+	}
 )
 
 func MapJsonObject(from interface{}, to interface{}) error {
@@ -0,0 +1,72 @@
+package blockatlas
+
+import (
+	"math/big"
+
+	"github.com/trustwallet/golibs/asset"
+	"github.com/trustwallet/golibs/types"
+)
+
+// AddressBalances maps the asset ids of an address to its balances in their smallest unit
+type AddressBalances map[string]string
+
+// GetNetAmounts returns the net effect of the transaction on the balances of the address by asset id,
+// the direction must already be set relative to the address. Fees paid by the address are taken from
+// the native asset, failed transactions only pay their fee. Transactions moving several assets only
+// count for their fee.
+func GetNetAmounts(tx types.Tx) map[string]*big.Int {
+	amounts := make(map[string]*big.Int)
+	add := func(assetID string, amount *big.Int) {
+		if _, ok := amounts[assetID]; !ok {
+			amounts[assetID] = new(big.Int)
+		}
+		amounts[assetID].Add(amounts[assetID], amount)
+		if amounts[assetID].Sign() == 0 {
+			delete(amounts, assetID)
+		}
+	}
+	if tx.Direction == types.DirectionOutgoing || tx.Direction == types.DirectionSelf {
+		if fee, ok := new(big.Int).SetString(string(tx.Fee), 10); ok {
+			add(asset.BuildID(tx.Coin, ""), fee.Neg(fee))
+		}
+	}
+	if tx.Status == types.StatusError {
+		return amounts
+	}
+	value, ok := GetTxValue(tx)
+	if !ok {
+		return amounts
+	}
+	amount, ok := new(big.Int).SetString(string(value), 10)
+	if !ok {
+		return amounts
+	}
+	switch tx.Direction {
+	case types.DirectionIncoming:
+		add(getTxAssetID(tx), amount)
+	case types.DirectionOutgoing:
+		add(getTxAssetID(tx), amount.Neg(amount))
+	}
+	return amounts
+}
+
+// SetSignedValues sets the net amount moved by each transaction in the unit of its value:
+// negative when outgoing, fee included for native transfers, and only the fee for self transfers.
+// The direction must already be set.
+func (txs Txs) SetSignedValues() {
+	for i := range txs {
+		amount, ok := GetNetAmounts(txs[i].Tx)[getTxAssetID(txs[i].Tx)]
+		if !ok {
+			amount = new(big.Int)
+		}
+		txs[i].SignedValue = types.Amount(amount.String())
+	}
+}
+
+// getTxAssetID returns the asset id of the value of the transaction, its token or the native coin
+func getTxAssetID(tx types.Tx) string {
+	if token, ok := GetTokenID(tx); ok && token != "" {
+		return asset.BuildID(tx.Coin, token)
+	}
+	return asset.BuildID(tx.Coin, "")
+}
@@ -0,0 +1,44 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetNetAmounts(t *testing.T) {
+	tx := types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionOutgoing, Meta: &types.TokenTransfer{TokenID: "0xt", Value: "5"}}
+	amounts := GetNetAmounts(tx)
+	assert.Len(t, amounts, 2)
+	assert.Equal(t, "-21", amounts["c60"].String())
+	assert.Equal(t, "-5", amounts["c60_t0xt"].String())
+
+	tx.Direction = types.DirectionIncoming
+	amounts = GetNetAmounts(tx)
+	assert.Len(t, amounts, 1)
+	assert.Equal(t, "5", amounts["c60_t0xt"].String())
+}
+
+func TestTxs_SetSignedValues(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   types.Tx
+		want types.Amount
+	}{
+		{"incoming", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionIncoming, Meta: types.Transfer{Value: "1000"}}, "1000"},
+		{"outgoing with fee", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionOutgoing, Meta: types.Transfer{Value: "1000"}}, "-1021"},
+		{"self transfer nets to the fee", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionSelf, Meta: types.Transfer{Value: "1000"}}, "-21"},
+		{"failed outgoing", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionOutgoing, Status: types.StatusError, Meta: types.Transfer{Value: "1000"}}, "-21"},
+		{"outgoing token in token unit", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionOutgoing, Meta: types.TokenTransfer{TokenID: "0xt", Value: "5"}}, "-5"},
+		{"contract call", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionOutgoing, Meta: types.ContractCall{Value: "0"}}, "-21"},
+		{"incoming without value", types.Tx{Coin: 60, Fee: "21", Direction: types.DirectionIncoming, Meta: types.ContractCall{Value: "0"}}, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txs := NewTxs(types.Txs{tt.tx})
+			txs.SetSignedValues()
+			assert.Equal(t, tt.want, txs[0].SignedValue)
+		})
+	}
+}
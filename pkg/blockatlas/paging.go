@@ -0,0 +1,178 @@
+package blockatlas
+
+import (
+	"context"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+const (
+	// MaxPage bounds offset pagination, each page re-reads and sorts the transactions before it
+	MaxPage = 20
+	// MaxPerPage bounds the page size of offset pagination
+	MaxPerPage = 100
+)
+
+// GetTxsByAddressPages follows the pagination of the provider until the filter keeps size
+// transactions, reading at most maxPages pages. Pages read before a failing one are returned.
+// Providers without pagination serve their single page, filtered upstream when they support it.
+func GetTxsByAddressPages(api TxAPI, address string, upstream TxFilter, maxPages, size int, filter func(types.Txs) types.Txs) (types.Txs, error) {
+	pageAPI, ok := api.(TxPageAPI)
+	if !ok || maxPages <= 1 {
+		return getTxsByAddress(api, address, upstream)
+	}
+	result := make(types.Txs, 0)
+	for page := 1; page <= maxPages; page++ {
+		txs, more, err := pageAPI.GetTxsByAddressPage(address, page)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			break
+		}
+		result = append(result, txs...)
+		if !more || len(filter(FilterUniqueTxs(result))) >= size {
+			break
+		}
+	}
+	return result, nil
+}
+
+// GetLatestTxs returns the latest transactions of the address, at least limit of them matching the filter if
+// the history has them. Providers limiting their page natively serve limit transactions only, a page of the
+// provider is followed up when the filter drops some of them. Providers applying the upstream filter serve
+// their filtered page instead.
+func GetLatestTxs(api TxAPI, address string, upstream TxFilter, limit, maxPages int, filter func(types.Txs) types.Txs) (types.Txs, error) {
+	if limitAPI, ok := api.(TxLimitAPI); ok && !supportsTxFilter(api, upstream) {
+		txs, err := limitAPI.GetTxsByAddressLimit(address, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(txs) < limit || len(filter(FilterUniqueTxs(txs))) >= limit {
+			return txs, nil
+		}
+	}
+	return GetTxsByAddressPages(api, address, upstream, maxPages, limit, filter)
+}
+
+// getTxsByAddress serves the single page of the provider, filtered upstream when supported
+func getTxsByAddress(api TxAPI, address string, upstream TxFilter) (types.Txs, error) {
+	if supportsTxFilter(api, upstream) {
+		return api.(TxFilterAPI).GetTxsByAddressFiltered(address, upstream)
+	}
+	return api.GetTxsByAddress(address)
+}
+
+func supportsTxFilter(api TxAPI, upstream TxFilter) bool {
+	filterAPI, ok := api.(TxFilterAPI)
+	return ok && upstream != (TxFilter{}) && filterAPI.SupportsTxFilter(upstream)
+}
+
+// TxTruncation tells why a full history lookup stopped before the first transaction of the address
+type TxTruncation string
+
+const (
+	// TxTruncatedCap is set when the history exceeds the page or transaction limits
+	TxTruncatedCap TxTruncation = "cap"
+	// TxTruncatedDeadline is set when the provider pages took longer than the deadline
+	TxTruncatedDeadline TxTruncation = "deadline"
+	// TxTruncatedProvider is set when the provider has no pagination or failed on a later page
+	TxTruncatedProvider TxTruncation = "provider"
+)
+
+// GetFullTxHistory follows the pagination of the provider back to the first transaction of the address.
+// It stops after maxPages pages, maxTxs transactions or when the context is done, returning the newest
+// transactions with the reason of the truncation. Only a failing first page is an error.
+func GetFullTxHistory(ctx context.Context, api TxAPI, address string, maxPages, maxTxs int) (types.Txs, TxTruncation, error) {
+	pageAPI, ok := api.(TxPageAPI)
+	if !ok {
+		txs, err := api.GetTxsByAddress(address)
+		return txs, TxTruncatedProvider, err
+	}
+	result := make(types.Txs, 0)
+	truncation := TxTruncatedCap
+	for page := 1; page <= maxPages; page++ {
+		if ctx.Err() != nil {
+			truncation = TxTruncatedDeadline
+			break
+		}
+		txs, more, err := pageAPI.GetTxsByAddressPage(address, page)
+		if err != nil {
+			if page == 1 {
+				return nil, "", err
+			}
+			truncation = TxTruncatedProvider
+			break
+		}
+		result = append(result, txs...)
+		if !more {
+			truncation = ""
+			break
+		}
+	}
+	result = SortTxs(FilterUniqueTxs(result))
+	if len(result) > maxTxs {
+		result, truncation = result[:maxTxs], TxTruncatedCap
+	}
+	return result, truncation, nil
+}
+
+// GetTxsByAddressSince follows the pagination of the provider back to a page reaching the confirmed transactions
+// included at or before the block or timestamp, zero values being ignored, reading at most maxPages pages.
+// Like GetFullTxHistory, it returns why transactions after the block or timestamp may be missing.
+func GetTxsByAddressSince(api TxAPI, address string, block uint64, timestamp int64, maxPages int) (types.Txs, TxTruncation, error) {
+	pageAPI, ok := api.(TxPageAPI)
+	if !ok {
+		txs, err := api.GetTxsByAddress(address)
+		if err != nil || reachesSince(txs, block, timestamp) {
+			return txs, "", err
+		}
+		return txs, TxTruncatedProvider, nil
+	}
+	result := make(types.Txs, 0)
+	truncation := TxTruncatedCap
+	for page := 1; page <= maxPages; page++ {
+		txs, more, err := pageAPI.GetTxsByAddressPage(address, page)
+		if err != nil {
+			if page == 1 {
+				return nil, "", err
+			}
+			truncation = TxTruncatedProvider
+			break
+		}
+		result = append(result, txs...)
+		if !more || reachesSince(txs, block, timestamp) {
+			truncation = ""
+			break
+		}
+	}
+	return result, truncation, nil
+}
+
+// reachesSince tells whether a page holds confirmed transactions filtered out by FilterTxsSince
+func reachesSince(txs types.Txs, block uint64, timestamp int64) bool {
+	for _, tx := range txs {
+		if tx.Status == types.StatusPending {
+			continue
+		}
+		if (block > 0 && tx.Block > 0 && tx.Block <= block) || (timestamp > 0 && tx.Date <= timestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// PaginateTxs returns the transactions of a 1-based page with the number of pages.
+// Offset pages shift when new transactions come in, clients needing stable pages should use cursors.
+func PaginateTxs(txs types.Txs, page, perPage int) (types.Txs, int) {
+	totalPages := (len(txs) + perPage - 1) / perPage
+	start := (page - 1) * perPage
+	if start >= len(txs) {
+		return types.Txs{}, totalPages
+	}
+	end := start + perPage
+	if end > len(txs) {
+		end = len(txs)
+	}
+	return txs[start:end], totalPages
+}
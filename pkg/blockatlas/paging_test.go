@@ -0,0 +1,287 @@
+package blockatlas
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+// pagedPlatform serves pages of types.TxPerPage transactions, every other one being a contract call
+type pagedPlatform struct {
+	pages    int
+	failPage int
+	requests *int
+}
+
+func (p pagedPlatform) Coin() coin.Coin {
+	return coin.Ethereum()
+}
+
+func (p pagedPlatform) GetTxsByAddress(address string) (types.Txs, error) {
+	txs, _, err := p.GetTxsByAddressPage(address, 1)
+	return txs, err
+}
+
+func (p pagedPlatform) GetTxsByAddressPage(address string, page int) (types.Txs, bool, error) {
+	*p.requests++
+	if page == p.failPage {
+		return nil, false, ErrSourceConn
+	}
+	txs := make(types.Txs, 0, types.TxPerPage)
+	for i := 0; i < types.TxPerPage; i++ {
+		tx := types.Tx{ID: strconv.Itoa(page) + "_" + strconv.Itoa(i), Meta: types.Transfer{Value: "1"}}
+		if i%2 == 1 {
+			tx.Meta = types.ContractCall{Value: "0"}
+		}
+		txs = append(txs, tx)
+	}
+	return txs, page < p.pages, nil
+}
+
+// singlePagePlatform serves the latest transaction only, without pagination
+type singlePagePlatform struct{}
+
+func (p singlePagePlatform) Coin() coin.Coin {
+	return coin.Ethereum()
+}
+
+func (p singlePagePlatform) GetTxsByAddress(address string) (types.Txs, error) {
+	return types.Txs{{ID: "latest"}}, nil
+}
+
+func TestGetTxsByAddressPages(t *testing.T) {
+	all := func(txs types.Txs) types.Txs { return txs }
+	tests := []struct {
+		name     string
+		pages    int
+		failPage int
+		maxPages int
+		filter   func(types.Txs) types.Txs
+		wantTxs  int
+		wantReqs int
+		wantErr  error
+	}{
+		{"single page", 5, 0, 1, FilterTxsZeroValue, 25, 1, nil},
+		{"first page is enough", 5, 0, 5, all, 25, 1, nil},
+		{"filled after filter", 5, 0, 5, FilterTxsZeroValue, 50, 2, nil},
+		{"bounded", 5, 0, 3, func(types.Txs) types.Txs { return nil }, 75, 3, nil},
+		{"no more pages", 2, 0, 5, func(types.Txs) types.Txs { return nil }, 50, 2, nil},
+		{"failing next page", 5, 2, 5, FilterTxsZeroValue, 25, 2, nil},
+		{"failing first page", 5, 1, 5, FilterTxsZeroValue, 0, 1, ErrSourceConn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			api := pagedPlatform{pages: tt.pages, failPage: tt.failPage, requests: &requests}
+			txs, err := GetTxsByAddressPages(api, "0x", TxFilter{}, tt.maxPages, types.TxPerPage, tt.filter)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Len(t, txs, tt.wantTxs)
+			assert.Equal(t, tt.wantReqs, requests)
+		})
+	}
+}
+
+// limitPlatform limits its first page natively on top of the pages of pagedPlatform
+type limitPlatform struct {
+	pagedPlatform
+}
+
+func (p limitPlatform) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	txs, _, err := p.GetTxsByAddressPage(address, 1)
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, err
+}
+
+func TestGetLatestTxs(t *testing.T) {
+	all := func(txs types.Txs) types.Txs { return txs }
+	tests := []struct {
+		name     string
+		native   bool
+		filter   func(types.Txs) types.Txs
+		wantTxs  int
+		wantReqs int
+	}{
+		{"native limit", true, all, 5, 1},
+		{"filtered out of the limit", true, FilterTxsZeroValue, 25, 2},
+		{"no native limit", false, all, 25, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			var api TxAPI = pagedPlatform{pages: 5, requests: &requests}
+			if tt.native {
+				api = limitPlatform{pagedPlatform{pages: 5, requests: &requests}}
+			}
+			txs, err := GetLatestTxs(api, "0x", TxFilter{}, 5, 5, tt.filter)
+			assert.Nil(t, err)
+			assert.Len(t, txs, tt.wantTxs)
+			assert.Equal(t, tt.wantReqs, requests)
+		})
+	}
+}
+
+// filterPlatform serves a single page, limited natively and filtered upstream to the native transfers
+type filterPlatform struct {
+	singlePagePlatform
+}
+
+func (p filterPlatform) SupportsTxFilter(filter TxFilter) bool {
+	return filter.AssetType == AssetTypeNative
+}
+
+func (p filterPlatform) GetTxsByAddressFiltered(address string, filter TxFilter) (types.Txs, error) {
+	return types.Txs{{ID: "filtered"}}, nil
+}
+
+func (p filterPlatform) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	return types.Txs{{ID: "limited"}}, nil
+}
+
+func TestGetTxsByAddressPages_Upstream(t *testing.T) {
+	all := func(txs types.Txs) types.Txs { return txs }
+	native := TxFilter{AssetType: AssetTypeNative}
+
+	txs, err := GetTxsByAddressPages(filterPlatform{}, "0x", native, 5, types.TxPerPage, all)
+	assert.Nil(t, err)
+	assert.Equal(t, "filtered", txs[0].ID)
+
+	txs, err = GetTxsByAddressPages(filterPlatform{}, "0x", TxFilter{AssetType: AssetTypeNFT}, 5, types.TxPerPage, all)
+	assert.Nil(t, err)
+	assert.Equal(t, "latest", txs[0].ID)
+
+	txs, err = GetLatestTxs(filterPlatform{}, "0x", native, 1, 5, all)
+	assert.Nil(t, err)
+	assert.Equal(t, "filtered", txs[0].ID)
+
+	txs, err = GetLatestTxs(filterPlatform{}, "0x", TxFilter{}, 1, 5, all)
+	assert.Nil(t, err)
+	assert.Equal(t, "limited", txs[0].ID)
+
+	// paginated providers serve their pages, filtered locally
+	requests := 0
+	txs, err = GetTxsByAddressPages(pagedPlatform{pages: 5, requests: &requests}, "0x", native, 5, 50, FilterTxsZeroValue)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 100)
+	assert.Equal(t, 4, requests)
+}
+
+func TestGetFullTxHistory(t *testing.T) {
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		pages    int
+		failPage int
+		maxPages int
+		maxTxs   int
+		wantTxs  int
+		wantReqs int
+		want     TxTruncation
+		wantErr  error
+	}{
+		{"whole history", context.Background(), 3, 0, 5, 1000, 75, 3, "", nil},
+		{"page cap", context.Background(), 5, 0, 2, 1000, 50, 2, TxTruncatedCap, nil},
+		{"transactions cap", context.Background(), 3, 0, 5, 60, 60, 3, TxTruncatedCap, nil},
+		{"deadline", done, 3, 0, 5, 1000, 0, 0, TxTruncatedDeadline, nil},
+		{"failing next page", context.Background(), 3, 2, 5, 1000, 25, 2, TxTruncatedProvider, nil},
+		{"failing first page", context.Background(), 3, 1, 5, 1000, 0, 1, "", ErrSourceConn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			api := pagedPlatform{pages: tt.pages, failPage: tt.failPage, requests: &requests}
+			txs, truncation, err := GetFullTxHistory(tt.ctx, api, "0x", tt.maxPages, tt.maxTxs)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Len(t, txs, tt.wantTxs)
+			assert.Equal(t, tt.wantReqs, requests)
+			assert.Equal(t, tt.want, truncation)
+		})
+	}
+
+	txs, truncation, err := GetFullTxHistory(context.Background(), singlePagePlatform{}, "0x", 5, 1000)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, TxTruncatedProvider, truncation)
+}
+
+func TestPaginateTxs(t *testing.T) {
+	txs := types.Txs{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	tests := []struct {
+		name           string
+		page, perPage  int
+		wantIDs        []string
+		wantTotalPages int
+	}{
+		{"first page", 1, 2, []string{"1", "2"}, 3},
+		{"last partial page", 3, 2, []string{"5"}, 3},
+		{"past the last page", 4, 2, []string{}, 3},
+		{"single page", 1, 25, []string{"1", "2", "3", "4", "5"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, totalPages := PaginateTxs(txs, tt.page, tt.perPage)
+			ids := make([]string, 0)
+			for _, tx := range page {
+				ids = append(ids, tx.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTotalPages, totalPages)
+		})
+	}
+	_, totalPages := PaginateTxs(types.Txs{}, 1, 25)
+	assert.Equal(t, 0, totalPages)
+}
+
+// datedPlatform serves pages of types.TxPerPage transactions, one per block counting down from 1000
+type datedPlatform struct {
+	pagedPlatform
+}
+
+func (p datedPlatform) GetTxsByAddressPage(address string, page int) (types.Txs, bool, error) {
+	txs, more, err := p.pagedPlatform.GetTxsByAddressPage(address, page)
+	for i := range txs {
+		txs[i].Block = uint64(1000 - (page-1)*types.TxPerPage - i)
+		txs[i].Date = int64(txs[i].Block) * 10
+	}
+	return txs, more, err
+}
+
+func TestGetTxsByAddressSince(t *testing.T) {
+	tests := []struct {
+		name           string
+		pages          int
+		failPage       int
+		block          uint64
+		timestamp      int64
+		wantReqs       int
+		wantTruncation TxTruncation
+	}{
+		{"first page", 5, 0, 990, 0, 1, ""},
+		{"second page", 5, 0, 960, 0, 2, ""},
+		{"by timestamp", 5, 0, 0, 9600, 2, ""},
+		{"bounded", 5, 0, 800, 0, 3, TxTruncatedCap},
+		{"no more pages", 2, 0, 0, 0, 2, ""},
+		{"failing next page", 5, 2, 960, 0, 2, TxTruncatedProvider},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			api := datedPlatform{pagedPlatform{pages: tt.pages, failPage: tt.failPage, requests: &requests}}
+			_, truncation, err := GetTxsByAddressSince(api, "0x", tt.block, tt.timestamp, 3)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantTruncation, truncation)
+			assert.Equal(t, tt.wantReqs, requests)
+		})
+	}
+
+	_, truncation, err := GetTxsByAddressSince(singlePagePlatform{}, "0x", 10, 0, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, TxTruncatedProvider, truncation)
+}
@@ -1,6 +1,8 @@
 package blockatlas
 
 import (
+	"context"
+
 	"github.com/trustwallet/golibs/coin"
 	"github.com/trustwallet/golibs/types"
 )
@@ -18,12 +20,52 @@ type (
 		GetBlockByNumber(num int64) (*types.Block, error)
 	}
 
+	// BlockHeadAPI provides the latest block of the chain
+	BlockHeadAPI interface {
+		Platform
+		GetCurrentBlockHeight() (BlockHead, error)
+	}
+
 	// TxAPI provides transaction lookups based on address
 	TxAPI interface {
 		Platform
 		GetTxsByAddress(address string) (types.Txs, error)
 	}
 
+	// TxPageAPI provides transaction lookup following the pagination of the provider, pages start at 1
+	TxPageAPI interface {
+		Platform
+		GetTxsByAddressPage(address string, page int) (txs types.Txs, more bool, err error)
+	}
+
+	// TxLimitAPI provides lookups of the latest transactions of an address, the provider limiting its page natively
+	TxLimitAPI interface {
+		Platform
+		GetTxsByAddressLimit(address string, limit int) (types.Txs, error)
+	}
+
+	// TxFilterAPI provides transaction lookups filtered by the provider, so the transactions filtered out
+	// locally are not fetched. Filters are still applied locally, also covering the unsupported ones.
+	TxFilterAPI interface {
+		Platform
+		// SupportsTxFilter reports whether the provider applies the filter upstream
+		SupportsTxFilter(filter TxFilter) bool
+		GetTxsByAddressFiltered(address string, filter TxFilter) (types.Txs, error)
+	}
+
+	// ContractAPI tells contract addresses from externally owned accounts (EVM coins)
+	ContractAPI interface {
+		Platform
+		IsContract(address string) (bool, error)
+	}
+
+	// InternalTxAPI provides lookups of value moved by contract calls (EVM internal transactions),
+	// along with the transactions of the same provider page
+	InternalTxAPI interface {
+		Platform
+		GetTxsWithInternalByAddress(address string) (txs types.Txs, internal types.Txs, err error)
+	}
+
 	// TokenTxAPI provides token transaction lookups
 	TokenTxAPI interface {
 		Platform
@@ -36,6 +78,12 @@ type (
 		GetTxsByXpub(xpub string) (types.Txs, error)
 	}
 
+	// XpubAddressesAPI provides the addresses derived from an XPUB
+	XpubAddressesAPI interface {
+		Platform
+		GetAddressesFromXpub(xpub string) ([]XpubAddress, error)
+	}
+
 	// TokensAPI provides token lookups
 	TokensAPI interface {
 		Platform
@@ -43,6 +91,26 @@ type (
 		GetTokenListIdsByAddress(address string) ([]string, error)
 	}
 
+	// TokenBalanceAPI provides the balance of a single token
+	TokenBalanceAPI interface {
+		Platform
+		GetTokenBalance(address, token string) (types.Amount, error)
+	}
+
+	// BalanceAPI provides the current balances of an address by asset id, the native coin and its tokens,
+	// with the block they were read at
+	BalanceAPI interface {
+		Platform
+		GetBalances(address string) (balances map[string]types.Amount, block int64, err error)
+	}
+
+	// ContextAPI binds the provider requests of a platform to a context, e.g. the context of the request they serve.
+	// Bound platforms return the TxExtras of their transactions in the context, see TrackTxExtras
+	ContextAPI interface {
+		Platform
+		WithContext(ctx context.Context) Platform
+	}
+
 	// StakingAPI provides staking information
 	StakeAPI interface {
 		Platform
@@ -64,6 +132,15 @@ type (
 	CollectionsAPIs map[uint]CollectionsAPI
 )
 
+// BindContext returns the platform with its provider requests bound to the context,
+// platforms without ContextAPI are returned as is
+func BindContext(p Platform, ctx context.Context) Platform {
+	if api, ok := p.(ContextAPI); ok {
+		return api.WithContext(ctx)
+	}
+	return p
+}
+
 func (ps Platforms) GetPlatformList() []Platform {
 	platforms := make([]Platform, 0)
 	for _, p := range ps {
@@ -0,0 +1,39 @@
+package blockatlas
+
+import (
+	"context"
+	"time"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+// PollTxs fetches the transactions every interval until ready accepts them, the timeout elapses
+// or the context is done. The last fetched transactions are returned.
+func PollTxs(ctx context.Context, fetch func() (types.Txs, error), ready func(types.Txs) bool, timeout, interval time.Duration) (types.Txs, error) {
+	txs, err := fetch()
+	if err != nil || ready(txs) {
+		return txs, err
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return txs, nil
+		case <-deadline.C:
+			return txs, nil
+		case <-ticker.C:
+			next, err := fetch()
+			if err != nil {
+				// a failing poll keeps the last transactions, the provider may recover before the timeout
+				continue
+			}
+			txs = next
+			if ready(txs) {
+				return txs, nil
+			}
+		}
+	}
+}
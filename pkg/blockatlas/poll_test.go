@@ -0,0 +1,50 @@
+package blockatlas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestPollTxs(t *testing.T) {
+	newTx := func(polls int) func() (types.Txs, error) {
+		count := 0
+		return func() (types.Txs, error) {
+			count++
+			if count == 2 {
+				return nil, ErrSourceConn
+			}
+			if count >= polls {
+				return types.Txs{{ID: "new"}, {ID: "old"}}, nil
+			}
+			return types.Txs{{ID: "old"}}, nil
+		}
+	}
+	ready := func(txs types.Txs) bool {
+		return len(txs) > 1
+	}
+
+	txs, err := PollTxs(context.Background(), newTx(1), ready, time.Second, time.Millisecond)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 2)
+
+	txs, err = PollTxs(context.Background(), newTx(4), ready, time.Second, time.Millisecond)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 2)
+
+	txs, err = PollTxs(context.Background(), newTx(1000), ready, 20*time.Millisecond, time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, types.Txs{{ID: "old"}}, txs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	txs, err = PollTxs(ctx, newTx(1000), ready, time.Hour, time.Millisecond)
+	assert.Nil(t, err)
+	assert.Len(t, txs, 1)
+
+	_, err = PollTxs(context.Background(), func() (types.Txs, error) { return nil, ErrSourceConn }, ready, time.Second, time.Millisecond)
+	assert.Equal(t, ErrSourceConn, err)
+}
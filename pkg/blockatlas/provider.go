@@ -0,0 +1,46 @@
+package blockatlas
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// servedBy holds the provider which served the lookups of a request
+	servedBy struct {
+		mu       sync.Mutex
+		provider string
+	}
+
+	servedByKey struct{}
+)
+
+// TrackProvider returns a context the platforms bound to it name the provider serving their lookups in, see BindContext
+func TrackProvider(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(servedByKey{}).(*servedBy); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, servedByKey{}, &servedBy{})
+}
+
+// SetServedBy names the provider which served a lookup of the request, it is ignored for untracked contexts
+func SetServedBy(ctx context.Context, provider string) {
+	s, ok := ctx.Value(servedByKey{}).(*servedBy)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = provider
+}
+
+// ServedBy returns the provider which served the last lookup of the request, if named
+func ServedBy(ctx context.Context) (string, bool) {
+	s, ok := ctx.Value(servedByKey{}).(*servedBy)
+	if !ok {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provider, s.provider != ""
+}
@@ -0,0 +1,30 @@
+package blockatlas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServedBy(t *testing.T) {
+	untracked := context.Background()
+	SetServedBy(untracked, "1")
+	_, ok := ServedBy(untracked)
+	assert.False(t, ok)
+
+	ctx := TrackProvider(untracked)
+	_, ok = ServedBy(ctx)
+	assert.False(t, ok)
+
+	SetServedBy(ctx, "0")
+	SetServedBy(ctx, "1")
+	provider, ok := ServedBy(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "1", provider)
+
+	// tracking again keeps the provider named so far
+	provider, ok = ServedBy(TrackProvider(ctx))
+	assert.True(t, ok)
+	assert.Equal(t, "1", provider)
+}
@@ -0,0 +1,54 @@
+package blockatlas
+
+import (
+	mapset "github.com/deckarep/golang-set"
+	"github.com/trustwallet/golibs/types"
+)
+
+// Recipient is an address receiving value in a transaction with several recipients
+type Recipient struct {
+	Address string       `json:"address"`
+	Value   types.Amount `json:"value"`
+	// TokenID of the transferred token, empty for the native coin
+	TokenID string `json:"token_id,omitempty"`
+}
+
+// GetRecipients returns the recipients of batch sends, nil for transactions with a single recipient.
+// Recipients are the outputs of UTXO transactions, change outputs back to an input address excluded,
+// or the token transfers of the transaction.
+func GetRecipients(tx types.Tx) []Recipient {
+	recipients := make([]Recipient, 0)
+	addresses := mapset.NewSet()
+	if len(tx.Inputs) > 0 && len(tx.Outputs) > 0 {
+		inputs := mapset.NewSet()
+		for _, input := range tx.Inputs {
+			inputs.Add(input.Address)
+		}
+		for _, output := range tx.Outputs {
+			if inputs.Contains(output.Address) {
+				continue
+			}
+			recipients = append(recipients, Recipient{Address: output.Address, Value: output.Value})
+			addresses.Add(output.Address)
+		}
+	} else {
+		for _, transfer := range tx.TokenTransfers {
+			recipients = append(recipients, Recipient{Address: transfer.To, Value: transfer.Value, TokenID: transfer.TokenID})
+			addresses.Add(transfer.To)
+		}
+	}
+	if addresses.Cardinality() < 2 {
+		return nil
+	}
+	return recipients
+}
+
+// SetRecipients sets the recipients of batch sends from the transactions with their outputs,
+// in the order of the page as outputs are dropped unless full details are requested
+func (txs Txs) SetRecipients(full types.Txs) {
+	for i := range txs {
+		if i < len(full) && full[i].ID == txs[i].ID {
+			txs[i].Recipients = GetRecipients(full[i])
+		}
+	}
+}
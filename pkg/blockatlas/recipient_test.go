@@ -0,0 +1,72 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+var (
+	utxoBatchSend = types.Tx{
+		ID:     "batch",
+		Coin:   coin.BITCOIN,
+		From:   "bc1qpayroll",
+		To:     "bc1qalice",
+		Inputs: []types.TxOutput{{Address: "bc1qpayroll", Value: "100000"}},
+		Outputs: []types.TxOutput{
+			{Address: "bc1qalice", Value: "30000"},
+			{Address: "bc1qbob", Value: "20000"},
+			{Address: "bc1qcarol", Value: "10000"},
+			{Address: "bc1qpayroll", Value: "39000"},
+		},
+	}
+	tokenBatchSend = types.Tx{
+		ID:   "0xbatch",
+		Coin: coin.ETHEREUM,
+		From: "0xpayroll",
+		To:   "0xdisperse",
+		TokenTransfers: []types.TokenTransfer{
+			{TokenID: "0xusdc", From: "0xpayroll", To: "0xalice", Value: "300"},
+			{TokenID: "0xusdc", From: "0xpayroll", To: "0xbob", Value: "200"},
+		},
+		Meta: types.ContractCall{Input: "0x", Value: "0"},
+	}
+)
+
+func TestGetRecipients(t *testing.T) {
+	assert.Equal(t, []Recipient{
+		{Address: "bc1qalice", Value: "30000"},
+		{Address: "bc1qbob", Value: "20000"},
+		{Address: "bc1qcarol", Value: "10000"},
+	}, GetRecipients(utxoBatchSend))
+	assert.Equal(t, []Recipient{
+		{Address: "0xalice", Value: "300", TokenID: "0xusdc"},
+		{Address: "0xbob", Value: "200", TokenID: "0xusdc"},
+	}, GetRecipients(tokenBatchSend))
+
+	single := utxoBatchSend
+	single.Outputs = []types.TxOutput{{Address: "bc1qalice", Value: "60000"}, {Address: "bc1qpayroll", Value: "39000"}}
+	assert.Nil(t, GetRecipients(single))
+	assert.Nil(t, GetRecipients(types.Tx{From: "0xa", To: "0xb", Meta: types.Transfer{Value: "1"}}))
+}
+
+func TestBatchSendDirection(t *testing.T) {
+	assert.Equal(t, types.DirectionOutgoing, GetTxDirection(utxoBatchSend, "bc1qpayroll"))
+	assert.Equal(t, types.DirectionIncoming, GetTxDirection(utxoBatchSend, "bc1qcarol"))
+
+	assert.Equal(t, types.DirectionOutgoing, GetTxDirection(tokenBatchSend, "0xpayroll"))
+	assert.Equal(t, types.DirectionIncoming, GetTxDirection(tokenBatchSend, "0xbob"))
+	assert.Equal(t, types.DirectionSelf, GetTxDirection(tokenBatchSend, "0xpayroll", "0xalice", "0xbob"))
+	assert.Equal(t, types.DirectionOutgoing, GetTxDirection(tokenBatchSend, "0xstranger"))
+}
+
+func TestTxs_SetRecipients(t *testing.T) {
+	full := types.Txs{utxoBatchSend, {ID: "single", From: "a", To: "b"}}
+	page := NewTxs(ApplyTxDetails(full, TxDetailsLight))
+	page.SetRecipients(full)
+	assert.Len(t, page[0].Recipients, 3)
+	assert.Nil(t, page[0].Outputs)
+	assert.Nil(t, page[1].Recipients)
+}
@@ -0,0 +1,132 @@
+package blockatlas
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+type (
+	// Recorder snapshots the upstream responses of a request along with the response served,
+	// for developers to turn real traffic into regression fixtures
+	Recorder struct {
+		Dir string
+	}
+
+	// recordingSession collects the upstream responses of a recorded request
+	recordingSession struct {
+		mu       sync.Mutex
+		upstream []UpstreamExchange
+	}
+
+	// UpstreamExchange is a raw provider response read while recording
+	UpstreamExchange struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Status int    `json:"status"`
+		Body   string `json:"body"`
+	}
+
+	// Recording is the snapshot of a request written by the Recorder
+	Recording struct {
+		Coin     string             `json:"coin"`
+		Address  string             `json:"address"`
+		Query    string             `json:"query"`
+		Upstream []UpstreamExchange `json:"upstream"`
+		Status   int                `json:"status"`
+		Response json.RawMessage    `json:"response"`
+	}
+
+	// RecordingTransport hands the provider responses to the recording session of their request context
+	RecordingTransport struct {
+		http.RoundTripper
+	}
+
+	recordingKey struct{}
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Record runs serve with the upstream responses of the requests sent with its context recorded,
+// see BindContext. serve returns the status and body served.
+// The recording is written to Dir keyed by coin, address and query, its path is returned
+func (r *Recorder) Record(ctx context.Context, coin, address string, query url.Values, serve func(ctx context.Context) (int, []byte)) (string, error) {
+	session := &recordingSession{}
+	status, response := serve(context.WithValue(ctx, recordingKey{}, session))
+
+	session.mu.Lock()
+	upstream := session.upstream
+	session.mu.Unlock()
+
+	recording := Recording{
+		Coin:     coin,
+		Address:  address,
+		Query:    query.Encode(),
+		Upstream: upstream,
+		Status:   status,
+		Response: response,
+	}
+	if !json.Valid(response) {
+		recording.Response, _ = json.Marshal(string(response))
+	}
+	raw, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.Dir, RecordingName(coin, address, query))
+	return path, ioutil.WriteFile(path, raw, 0644)
+}
+
+// RecordingName is the file name of the recording of a request, the query is hashed
+func RecordingName(coin, address string, query url.Values) string {
+	hash := sha256.Sum256([]byte(query.Encode()))
+	return fmt.Sprintf("%s_%s_%x.json",
+		unsafeFileChars.ReplaceAllString(coin, "_"),
+		unsafeFileChars.ReplaceAllString(address, "_"),
+		hash[:4])
+}
+
+// IsRecording reports whether the context is the one of a recorded request
+func IsRecording(ctx context.Context) bool {
+	_, ok := ctx.Value(recordingKey{}).(*recordingSession)
+	return ok
+}
+
+func (s *recordingSession) add(exchange UpstreamExchange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upstream = append(s.upstream, exchange)
+}
+
+func (t RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	session, ok := req.Context().Value(recordingKey{}).(*recordingSession)
+	if err != nil || !ok {
+		return res, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	session.add(UpstreamExchange{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: res.StatusCode,
+		Body:   string(body),
+	})
+	return res, nil
+}
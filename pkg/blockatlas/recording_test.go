@@ -0,0 +1,74 @@
+package blockatlas
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/client"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	recorder := &Recorder{Dir: t.TempDir()}
+	request := client.InitClient(server.URL, nil)
+	request.HttpClient = &http.Client{Transport: RecordingTransport{RoundTripper: http.DefaultTransport}}
+
+	// responses outside of a recording are not kept
+	var result map[string]string
+	assert.Nil(t, request.Get(&result, "before", nil))
+
+	query := url.Values{"token": {"0xdac17f958d2ee523a2206206994597c13d831ec7"}, "page": {"1"}}
+	path, err := recorder.Record(context.Background(), "ethereum", "0x../abc", query, func(ctx context.Context) (int, []byte) {
+		assert.True(t, IsRecording(ctx))
+		assert.Nil(t, request.GetWithContext(&result, "txs", nil, ctx))
+		assert.Equal(t, "/txs", result["path"])
+
+		// responses of other requests served meanwhile are not kept
+		var other map[string]string
+		assert.Nil(t, request.Get(&other, "concurrent", nil))
+		_, err := recorder.Record(context.Background(), "ethereum", "0xdef", nil, func(ctx context.Context) (int, []byte) {
+			assert.Nil(t, request.GetWithContext(&other, "nested", nil, ctx))
+			return http.StatusOK, []byte(`{"docs":[]}`)
+		})
+		assert.Nil(t, err)
+		return http.StatusOK, []byte(`{"docs":[]}`)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, RecordingName("ethereum", "0x../abc", query), path[len(recorder.Dir)+1:])
+	assert.Regexp(t, `^ethereum_0x___abc_[0-9a-f]{8}\.json$`, path[len(recorder.Dir)+1:])
+
+	raw, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	var recording Recording
+	assert.Nil(t, json.Unmarshal(raw, &recording))
+	assert.Equal(t, "ethereum", recording.Coin)
+	assert.Equal(t, "page=1&token=0xdac17f958d2ee523a2206206994597c13d831ec7", recording.Query)
+	assert.Equal(t, []UpstreamExchange{{Method: http.MethodGet, URL: server.URL + "/txs", Status: http.StatusOK, Body: `{"path":"/txs"}`}}, recording.Upstream)
+	assert.Equal(t, http.StatusOK, recording.Status)
+	assert.JSONEq(t, `{"docs":[]}`, string(recording.Response))
+
+	nested, err := ioutil.ReadFile(filepath.Join(recorder.Dir, RecordingName("ethereum", "0xdef", nil)))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(nested, &recording))
+	assert.Equal(t, []UpstreamExchange{{Method: http.MethodGet, URL: server.URL + "/nested", Status: http.StatusOK, Body: `{"path":"/nested"}`}}, recording.Upstream)
+
+	assert.False(t, IsRecording(context.Background()))
+}
+
+func TestRecordingName(t *testing.T) {
+	a := RecordingName("bitcoin", "bc1q", url.Values{"page": {"1"}})
+	b := RecordingName("bitcoin", "bc1q", url.Values{"page": {"2"}})
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, RecordingName("bitcoin", "bc1q", url.Values{"page": {"1"}}))
+}
@@ -0,0 +1,66 @@
+package blockatlas
+
+import (
+	"strings"
+
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type (
+	// Senders is a set of sender addresses, matched case insensitively
+	Senders map[string]struct{}
+
+	// SenderBlocklist holds by coin handle the senders of known spam campaigns
+	SenderBlocklist map[string]Senders
+)
+
+func NewSenders(addresses ...string) Senders {
+	senders := make(Senders, len(addresses))
+	senders.Add(addresses...)
+	return senders
+}
+
+func (s Senders) Add(addresses ...string) {
+	for _, address := range addresses {
+		if address = strings.TrimSpace(address); address != "" {
+			s[strings.ToLower(address)] = struct{}{}
+		}
+	}
+}
+
+func (s Senders) Contains(address string) bool {
+	_, ok := s[strings.ToLower(address)]
+	return ok
+}
+
+func NewSenderBlocklist(addresses map[string][]string) SenderBlocklist {
+	blocklist := make(SenderBlocklist, len(addresses))
+	for handle, list := range addresses {
+		blocklist[handle] = NewSenders(list...)
+	}
+	return blocklist
+}
+
+// Senders returns the blocked senders of the coin along with the extra ones
+func (b SenderBlocklist) Senders(c coin.Coin, extra ...string) Senders {
+	senders := NewSenders(extra...)
+	for address := range b[c.Handle] {
+		senders[address] = struct{}{}
+	}
+	return senders
+}
+
+// FilterTxsBySenders drops the transactions sent by the senders
+func FilterTxsBySenders(txs types.Txs, senders Senders) types.Txs {
+	if len(senders) == 0 {
+		return txs
+	}
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		if !senders.Contains(tx.From) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestSenderBlocklist(t *testing.T) {
+	blocklist := NewSenderBlocklist(map[string][]string{
+		coin.Ethereum().Handle: {"0xDustSpammer"},
+	})
+
+	senders := blocklist.Senders(coin.Ethereum(), "0xScammer", " ")
+	assert.Len(t, senders, 2)
+	assert.True(t, senders.Contains("0xdustspammer"))
+	assert.True(t, senders.Contains("0xSCAMMER"))
+
+	assert.Empty(t, blocklist.Senders(coin.Bitcoin()))
+}
+
+func TestFilterTxsBySenders(t *testing.T) {
+	txs := types.Txs{
+		{ID: "dust", From: "0xDustSpammer"},
+		{ID: "payment", From: "0xFriend"},
+	}
+	assert.Equal(t, txs, FilterTxsBySenders(txs, nil))
+
+	filtered := FilterTxsBySenders(txs, NewSenders("0xdustspammer"))
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "payment", filtered[0].ID)
+}
@@ -0,0 +1,51 @@
+package blockatlas
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidSignature signals a signed message whose signature doesn't match its payload
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// SignedMessage wraps a message payload with its HMAC-SHA256 signature, hex encoded
+type SignedMessage struct {
+	Payload   json.RawMessage `json:"payload"`
+	Signature string          `json:"signature"`
+}
+
+// SignMessage wraps the JSON payload in a SignedMessage signed with the secret.
+// The signature covers the payload bytes as written in the message, compacted.
+func SignMessage(payload []byte, secret string) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, payload); err != nil {
+		return nil, err
+	}
+	return json.Marshal(SignedMessage{
+		Payload:   compact.Bytes(),
+		Signature: hex.EncodeToString(mac(compact.Bytes(), secret)),
+	})
+}
+
+// VerifyMessage returns the payload of a SignedMessage signed with the secret, otherwise ErrInvalidSignature
+func VerifyMessage(body []byte, secret string) ([]byte, error) {
+	var message SignedMessage
+	if err := json.Unmarshal(body, &message); err != nil || len(message.Payload) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	expected, err := hex.DecodeString(message.Signature)
+	if err != nil || !hmac.Equal(expected, mac(message.Payload, secret)) {
+		return nil, ErrInvalidSignature
+	}
+	return message.Payload, nil
+}
+
+func mac(payload []byte, secret string) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return h.Sum(nil)
+}
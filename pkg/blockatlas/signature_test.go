@@ -0,0 +1,43 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignMessage(t *testing.T) {
+	signed, err := SignMessage([]byte(`{ "operation": "AddSubscription" }`), "secret")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^\{"payload":\{"operation":"AddSubscription"\},"signature":"[0-9a-f]{64}"\}$`, string(signed))
+
+	payload, err := VerifyMessage(signed, "secret")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"operation":"AddSubscription"}`, string(payload))
+
+	_, err = SignMessage([]byte(`not json`), "secret")
+	assert.NotNil(t, err)
+}
+
+func TestVerifyMessage(t *testing.T) {
+	signed, err := SignMessage([]byte(`{"operation":"AddSubscription"}`), "secret")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		body   string
+		secret string
+	}{
+		{"other secret", string(signed), "other"},
+		{"unsigned", `{"operation":"AddSubscription"}`, "secret"},
+		{"tampered payload", `{"payload":{"operation":"DeleteSubscription"},"signature":"` + string(signed[len(signed)-66:len(signed)-2]) + `"}`, "secret"},
+		{"not hex", `{"payload":{},"signature":"zz"}`, "secret"},
+		{"not json", `[`, "secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyMessage([]byte(tt.body), tt.secret)
+			assert.Equal(t, ErrInvalidSignature, err)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package blockatlas
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+// SpamTokens holds the ids of known spam tokens. The list is safe for concurrent use and can be reloaded at runtime.
+type SpamTokens struct {
+	mu     sync.RWMutex
+	tokens map[string]struct{}
+}
+
+func NewSpamTokens(ids []string) *SpamTokens {
+	s := &SpamTokens{}
+	s.Load(ids)
+	return s
+}
+
+// Load replaces the spam token ids
+func (s *SpamTokens) Load(ids []string) {
+	tokens := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		tokens[strings.ToLower(id)] = struct{}{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = tokens
+}
+
+func (s *SpamTokens) Contains(token string) bool {
+	if s == nil || token == "" {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.tokens[strings.ToLower(token)]
+	return ok
+}
+
+func (s *SpamTokens) isSpam(tx types.Tx) bool {
+	tokenID, ok := GetTokenID(tx)
+	return ok && s.Contains(tokenID)
+}
+
+// SetSpamFlags flags the transfers of spam tokens
+func (txs Txs) SetSpamFlags(spam *SpamTokens) {
+	for i := range txs {
+		txs[i].IsSpam = spam.isSpam(txs[i].Tx)
+	}
+}
+
+// FilterTxsSpam drops the transfers of spam tokens
+func FilterTxsSpam(txs types.Txs, spam *SpamTokens) types.Txs {
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		if !spam.isSpam(tx) {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
@@ -0,0 +1,28 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestSpamTokens(t *testing.T) {
+	transfer := types.Tx{ID: "transfer", Meta: types.Transfer{Value: "1"}}
+	spam := types.Tx{ID: "spam", Meta: types.TokenTransfer{TokenID: "0xSpam", Value: "1"}}
+	token := types.Tx{ID: "token", Meta: types.TokenTransfer{TokenID: "0xToken", Value: "1"}}
+	txs := types.Txs{transfer, spam, token}
+
+	list := NewSpamTokens([]string{"0xspam"})
+	assert.Equal(t, types.Txs{transfer, token}, FilterTxsSpam(txs, list))
+
+	page := NewTxs(txs)
+	page.SetSpamFlags(list)
+	assert.Equal(t, []bool{false, true, false}, []bool{page[0].IsSpam, page[1].IsSpam, page[2].IsSpam})
+
+	list.Load([]string{"0xTOKEN"})
+	assert.Equal(t, types.Txs{transfer, spam}, FilterTxsSpam(txs, list))
+
+	var disabled *SpamTokens
+	assert.Equal(t, txs, FilterTxsSpam(txs, disabled))
+}
@@ -0,0 +1,203 @@
+package blockatlas
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+const (
+	TxGroupNone TxGroup = ""
+	TxGroupDay  TxGroup = "day"
+)
+
+// TxGroup defines how transactions of a page are bucketed
+type TxGroup string
+
+func (g TxGroup) IsValid() bool {
+	switch g {
+	case TxGroupNone, TxGroupDay:
+		return true
+	default:
+		return false
+	}
+}
+
+type (
+	// TxDaySummary holds the transactions of one UTC day with the daily flow totals.
+	// Totals are in the smallest unit of the coin, token and self transfers are not counted.
+	TxDaySummary struct {
+		Date         string       `json:"date"`
+		Inflow       types.Amount `json:"inflow"`
+		Outflow      types.Amount `json:"outflow"`
+		Net          types.Amount `json:"net"`
+		Transactions Txs          `json:"transactions"`
+	}
+
+	// TxFlowSummary holds the flow totals of an address in the smallest unit of the coin, token transfers are not
+	// counted. Fees are the fees paid by the address, including the fees of failed transactions.
+	TxFlowSummary struct {
+		Inflow   types.Amount `json:"inflow"`
+		Outflow  types.Amount `json:"outflow"`
+		Fees     types.Amount `json:"fees"`
+		Count    int          `json:"count"`
+		Decimals uint         `json:"decimals"`
+	}
+
+	// TokenTransferCount is the number of transfers of a token by an address, LastSeen is the date of the latest one
+	TokenTransferCount struct {
+		Count    int   `json:"count"`
+		LastSeen int64 `json:"last_seen"`
+	}
+
+	TxDaySummaryPage struct {
+		TxSource
+		Total    int            `json:"total"`
+		Docs     []TxDaySummary `json:"docs"`
+		Status   bool           `json:"status"`
+		Decimals uint           `json:"decimals"`
+		// Truncated tells why a full_history page misses the oldest transactions
+		Truncated TxTruncation `json:"truncated,omitempty"`
+	}
+)
+
+// GetTxValue returns the transferred amount of transactions moving a single asset
+func GetTxValue(tx types.Tx) (types.Amount, bool) {
+	switch meta := tx.Meta.(type) {
+	case types.Transfer:
+		return meta.Value, true
+	case *types.Transfer:
+		return meta.Value, true
+	case types.TokenTransfer:
+		return meta.Value, true
+	case *types.TokenTransfer:
+		return meta.Value, true
+	case types.NativeTokenTransfer:
+		return meta.Value, true
+	case *types.NativeTokenTransfer:
+		return meta.Value, true
+	default:
+		return "", false
+	}
+}
+
+// getCoinTxValue returns the amount of the coin moved by native transfers, the amounts of tokens are in other
+// units and can't be added to it
+func getCoinTxValue(tx types.Tx) (types.Amount, bool) {
+	switch meta := tx.Meta.(type) {
+	case types.Transfer:
+		return meta.Value, true
+	case *types.Transfer:
+		return meta.Value, true
+	default:
+		return "", false
+	}
+}
+
+// GetTokenID returns the id of the token moved by the transaction, types.Tx.TokenID misses *types.TokenTransfer
+func GetTokenID(tx types.Tx) (string, bool) {
+	if meta, ok := tx.Meta.(*types.TokenTransfer); ok {
+		return meta.TokenID, true
+	}
+	return tx.TokenID()
+}
+
+// GroupTxsByDay buckets transactions by UTC day keeping their order.
+// The direction of the transactions must already be set.
+func GroupTxsByDay(txs Txs, decimals uint) TxDaySummaryPage {
+	days := make([]TxDaySummary, 0)
+	inflows := make([]*big.Int, 0)
+	outflows := make([]*big.Int, 0)
+	index := make(map[string]int)
+	for _, tx := range txs {
+		date := time.Unix(tx.Date, 0).UTC().Format("2006-01-02")
+		i, ok := index[date]
+		if !ok {
+			i = len(days)
+			index[date] = i
+			days = append(days, TxDaySummary{Date: date, Transactions: Txs{}})
+			inflows = append(inflows, new(big.Int))
+			outflows = append(outflows, new(big.Int))
+		}
+		days[i].Transactions = append(days[i].Transactions, tx)
+
+		value, ok := getCoinTxValue(tx.Tx)
+		if !ok || tx.Status == types.StatusError {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(string(value), 10)
+		if !ok {
+			continue
+		}
+		switch tx.Direction {
+		case types.DirectionIncoming:
+			inflows[i].Add(inflows[i], amount)
+		case types.DirectionOutgoing:
+			outflows[i].Add(outflows[i], amount)
+		}
+	}
+	for i := range days {
+		days[i].Inflow = types.Amount(inflows[i].String())
+		days[i].Outflow = types.Amount(outflows[i].String())
+		days[i].Net = types.Amount(new(big.Int).Sub(inflows[i], outflows[i]).String())
+	}
+	return TxDaySummaryPage{
+		Total:    len(days),
+		Docs:     days,
+		Status:   true,
+		Decimals: decimals,
+	}
+}
+
+// SummarizeTxs computes the flow totals of the coin over the transactions.
+// The direction of the transactions must already be set.
+func SummarizeTxs(txs types.Txs, decimals uint) TxFlowSummary {
+	inflow, outflow, fees := new(big.Int), new(big.Int), new(big.Int)
+	for _, tx := range txs {
+		if tx.Direction == types.DirectionOutgoing || tx.Direction == types.DirectionSelf {
+			if fee, ok := new(big.Int).SetString(string(tx.Fee), 10); ok {
+				fees.Add(fees, fee)
+			}
+		}
+		value, ok := getCoinTxValue(tx)
+		if !ok || tx.Status == types.StatusError {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(string(value), 10)
+		if !ok {
+			continue
+		}
+		switch tx.Direction {
+		case types.DirectionIncoming:
+			inflow.Add(inflow, amount)
+		case types.DirectionOutgoing:
+			outflow.Add(outflow, amount)
+		}
+	}
+	return TxFlowSummary{
+		Inflow:   types.Amount(inflow.String()),
+		Outflow:  types.Amount(outflow.String()),
+		Fees:     types.Amount(fees.String()),
+		Count:    len(txs),
+		Decimals: decimals,
+	}
+}
+
+// CountTokenTransfers counts the token transfers of the transactions by token id, failed transfers are not counted
+func CountTokenTransfers(txs types.Txs) map[string]TokenTransferCount {
+	counts := make(map[string]TokenTransferCount)
+	for _, tx := range txs {
+		tokenID, ok := GetTokenID(tx)
+		if !ok || tx.Status == types.StatusError {
+			continue
+		}
+		count := counts[tokenID]
+		count.Count++
+		if tx.Date > count.LastSeen {
+			count.LastSeen = tx.Date
+		}
+		counts[tokenID] = count
+	}
+	return counts
+}
@@ -0,0 +1,89 @@
+package blockatlas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGroupTxsByDay(t *testing.T) {
+	newTx := func(id string, date int64, direction types.Direction, value types.Amount) types.Tx {
+		return types.Tx{ID: id, Date: date, Direction: direction, Status: types.StatusCompleted, Meta: types.Transfer{Value: value}}
+	}
+	txs := NewTxs(types.Txs{
+		newTx("1", 1600041600, types.DirectionIncoming, "100"),
+		newTx("2", 1600000000, types.DirectionOutgoing, "30"),
+		newTx("3", 1599999999, types.DirectionOutgoing, "250"),
+		newTx("4", 1599990000, types.DirectionSelf, "10"),
+		{ID: "5", Date: 1599990000, Direction: types.DirectionIncoming, Status: types.StatusCompleted,
+			Meta: types.TokenTransfer{TokenID: "0xusdt", Value: "70000"}},
+	})
+
+	page := GroupTxsByDay(txs, 8)
+	assert.Equal(t, 2, page.Total)
+	assert.True(t, page.Status)
+	assert.Equal(t, uint(8), page.Decimals)
+
+	assert.Equal(t, "2020-09-14", page.Docs[0].Date)
+	assert.Equal(t, types.Amount("100"), page.Docs[0].Inflow)
+	assert.Equal(t, types.Amount("0"), page.Docs[0].Outflow)
+	assert.Equal(t, types.Amount("100"), page.Docs[0].Net)
+	assert.Len(t, page.Docs[0].Transactions, 1)
+
+	assert.Equal(t, "2020-09-13", page.Docs[1].Date)
+	assert.Equal(t, types.Amount("0"), page.Docs[1].Inflow)
+	assert.Equal(t, types.Amount("280"), page.Docs[1].Outflow)
+	assert.Equal(t, types.Amount("-280"), page.Docs[1].Net)
+	assert.Len(t, page.Docs[1].Transactions, 4)
+}
+
+func TestGetTxValue(t *testing.T) {
+	value, ok := GetTxValue(types.Tx{Meta: &types.TokenTransfer{Value: "5"}})
+	assert.True(t, ok)
+	assert.Equal(t, types.Amount("5"), value)
+
+	_, ok = GetTxValue(types.Tx{Meta: types.AnyAction{}})
+	assert.False(t, ok)
+}
+
+func TestSummarizeTxs(t *testing.T) {
+	newTx := func(direction types.Direction, status types.Status, fee, value types.Amount) types.Tx {
+		return types.Tx{Direction: direction, Status: status, Fee: fee, Meta: types.Transfer{Value: value}}
+	}
+	txs := types.Txs{
+		newTx(types.DirectionIncoming, types.StatusCompleted, "10", "1000"),
+		newTx(types.DirectionOutgoing, types.StatusCompleted, "10", "300"),
+		newTx(types.DirectionOutgoing, types.StatusError, "10", "500"),
+		newTx(types.DirectionSelf, types.StatusCompleted, "10", "200"),
+		{Direction: types.DirectionOutgoing, Status: types.StatusCompleted, Fee: "10", Meta: types.ContractCall{}},
+		{Direction: types.DirectionIncoming, Status: types.StatusCompleted, Fee: "10", Meta: types.TokenTransfer{TokenID: "0xusdt", Value: "70000"}},
+		{Direction: types.DirectionOutgoing, Status: types.StatusCompleted, Fee: "10", Meta: &types.TokenTransfer{TokenID: "0xusdt", Value: "5000"}},
+	}
+
+	summary := SummarizeTxs(txs, 8)
+	assert.Equal(t, types.Amount("1000"), summary.Inflow)
+	assert.Equal(t, types.Amount("300"), summary.Outflow)
+	assert.Equal(t, types.Amount("50"), summary.Fees)
+	assert.Equal(t, 7, summary.Count)
+	assert.Equal(t, uint(8), summary.Decimals)
+}
+
+func TestCountTokenTransfers(t *testing.T) {
+	newTx := func(token string, status types.Status, date int64) types.Tx {
+		return types.Tx{Status: status, Date: date, Meta: types.TokenTransfer{TokenID: token, Value: "1"}}
+	}
+	txs := types.Txs{
+		newTx("0xusdt", types.StatusCompleted, 100),
+		newTx("0xusdt", types.StatusCompleted, 300),
+		newTx("0xusdt", types.StatusError, 500),
+		newTx("0xdai", types.StatusPending, 200),
+		{Date: 600, Meta: types.Transfer{Value: "1"}},
+	}
+
+	assert.Equal(t, map[string]TokenTransferCount{
+		"0xusdt": {Count: 2, LastSeen: 300},
+		"0xdai":  {Count: 1, LastSeen: 200},
+	}, CountTokenTransfers(txs))
+	assert.Empty(t, CountTokenTransfers(nil))
+}
@@ -0,0 +1,47 @@
+package blockatlas
+
+import (
+	"io"
+	"net/http"
+)
+
+// LimitedTransport fails upstream responses whose body exceeds MaxSize bytes with ErrResponseTooLarge,
+// so a misbehaving provider can't make the service buffer an unbounded payload
+type LimitedTransport struct {
+	http.RoundTripper
+	MaxSize int64
+}
+
+func (t LimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.ContentLength > t.MaxSize {
+		res.Body.Close()
+		return nil, ErrResponseTooLarge
+	}
+	res.Body = &limitedBody{ReadCloser: res.Body, remaining: t.MaxSize}
+	return res, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// read one byte past the limit to tell a body of exactly MaxSize bytes from a bigger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,51 @@
+package blockatlas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/client"
+)
+
+func TestLimitedTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `"` + strings.Repeat("a", 8) + `"`
+		if r.URL.Path == "/chunked" {
+			// flushing before writing the body drops the Content-Length header
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		maxSize int64
+		wantErr bool
+	}{
+		{"within limit", "/", 64, false},
+		{"exact limit", "/chunked", 10, false},
+		{"content length over limit", "/", 9, true},
+		{"chunked over limit", "/chunked", 9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := client.InitClient(server.URL, nil)
+			request.HttpClient = &http.Client{Transport: LimitedTransport{RoundTripper: http.DefaultTransport, MaxSize: tt.maxSize}}
+
+			var result string
+			err := request.Get(&result, tt.path, nil)
+			if !tt.wantErr {
+				assert.Nil(t, err)
+				assert.Equal(t, "aaaaaaaa", result)
+				return
+			}
+			assert.True(t, IsSourceConnError(err))
+			assert.Equal(t, SourceErrorTooLarge, GetSourceErrorReason(err))
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package blockatlas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+type (
+	// Tx is the transaction served by the API. It extends the shared
+	// types.Tx with fields which are computed or resolved by blockatlas.
+	Tx struct {
+		types.Tx
+		TxExtension
+	}
+
+	// TxExtension holds the fields blockatlas adds on top of types.Tx
+	TxExtension struct {
+		// Height of the block the transaction was included in, 0 if pending
+		BlockHeight uint64 `json:"block_height"`
+		// BlockTime is the date of confirmed transactions, the time of their block
+		BlockTime int64 `json:"block_time,omitempty"`
+		// FirstSeen is the date of pending transactions, the time the provider first saw them
+		FirstSeen int64 `json:"first_seen,omitempty"`
+		// Hash of the block the transaction was included in.
+		// Only reported by Blockbook based platforms (Bitcoin and Ethereum families),
+		// omitted for all other coins.
+		BlockHash string `json:"block_hash,omitempty"`
+		// Internal marks value moved by a contract call of the transaction
+		Internal bool `json:"internal,omitempty"`
+		// Labels attached by users to the transaction
+		Labels []string `json:"labels,omitempty"`
+		// Logo of the transferred token in the asset registry
+		TokenLogo string `json:"token_logo,omitempty"`
+		// UnknownToken marks transferred tokens missing from the asset registry
+		UnknownToken bool `json:"unknown_token,omitempty"`
+		// IsSpam marks transfers of known spam tokens
+		IsSpam bool `json:"is_spam,omitempty"`
+		// Category is the kind of activity, see TxCategory for the taxonomy
+		Category TxCategory `json:"category,omitempty"`
+		// AssetType is the kind of asset moved: native, fungible or nft
+		AssetType AssetType `json:"asset_type,omitempty"`
+		// Recipients of batch sends, to is the first of them
+		Recipients []Recipient `json:"recipients,omitempty"`
+		// SignedValue is the net amount moved for the address in the unit of the value, see Txs.SetSignedValues
+		SignedValue types.Amount `json:"signed_value,omitempty"`
+		// Size and VSize of UTXO transactions in bytes and virtual bytes, with full details
+		Size  uint64 `json:"size,omitempty"`
+		VSize uint64 `json:"vsize,omitempty"`
+		// RBF marks pending UTXO transactions signalling replace-by-fee, they may be replaced before confirming
+		RBF bool `json:"rbf,omitempty"`
+	}
+
+	// TxSize is the size of a UTXO transaction, the fee rate is its fee by virtual byte
+	TxSize struct {
+		Size  uint64
+		VSize uint64
+	}
+
+	Txs []Tx
+
+	// TxPage must serialize to the same bytes for the same content, responses are hashed for caching.
+	// Map-typed fields are fine as encoding/json sorts map keys, custom marshalers must do the same.
+	TxPage struct {
+		TxSource
+		Total  int  `json:"total"`
+		Docs   Txs  `json:"docs"`
+		Status bool `json:"status"`
+		// Decimals of the native coin, token transfers carry their own decimals
+		Decimals uint `json:"decimals"`
+		// Page, PerPage and TotalPages are set for offset pagination, pages only cover the
+		// transactions returned by the provider and shift when new transactions come in
+		Page       int `json:"page,omitempty"`
+		PerPage    int `json:"per_page,omitempty"`
+		TotalPages int `json:"total_pages,omitempty"`
+		// HashNotFound is set when the requested after_hash is not in the transactions window
+		HashNotFound bool `json:"hash_not_found,omitempty"`
+		// Restart is set when the after_hash cursor was reorged, the page is served from the top
+		// and clients should drop the transactions they paged so far
+		Restart bool `json:"restart,omitempty"`
+		// Cursor of the newest transaction of the page, to be sent as after_hash for the next transactions
+		Cursor string `json:"cursor,omitempty"`
+		// Addresses derived from the requested XPUB which had transfers
+		Addresses []XpubAddress `json:"addresses,omitempty"`
+		// Logo of the native coin in the asset registry
+		Logo string `json:"logo,omitempty"`
+		// Truncated tells why a full_history or deposits page misses the oldest transactions
+		Truncated TxTruncation `json:"truncated,omitempty"`
+	}
+
+	// TxSource tells clients where the transactions of a page come from, it is set on empty pages too
+	TxSource struct {
+		// Provider serving the transactions: the coin handle, followed by the provider answering for coins with failover
+		// as in the providers health, e.g. bitcoin/1, or upstream for overridden providers
+		Provider string `json:"provider"`
+		// FetchedAt is the unix time the provider responded at
+		FetchedAt int64 `json:"fetched_at"`
+		// FromCache is set when the transactions were not fetched from the provider for this request
+		FromCache bool `json:"from_cache"`
+	}
+
+	// TxCount is the number of transactions matching a query, returned instead of the page when only the activity matters
+	TxCount struct {
+		Total int `json:"total"`
+	}
+
+	// TokenDetails combines the token balance of an address with its latest transfers
+	TokenDetails struct {
+		Balance      types.Amount `json:"balance"`
+		Transactions TxPage       `json:"transactions"`
+	}
+)
+
+func NewTxs(txs types.Txs) Txs {
+	result := make(Txs, 0, len(txs))
+	for _, tx := range txs {
+		extension := TxExtension{BlockHeight: tx.Block, Category: GetTxCategory(tx)}
+		if IsPendingTx(tx) {
+			extension.FirstSeen = tx.Date
+		} else {
+			extension.BlockTime = tx.Date
+		}
+		result = append(result, Tx{Tx: tx, TxExtension: extension})
+	}
+	return result
+}
+
+// NewTxSource returns the source of transactions the provider just responded with
+func NewTxSource(provider string) TxSource {
+	return TxSource{Provider: provider, FetchedAt: time.Now().Unix()}
+}
+
+func NewTxPage(txs Txs, decimals uint) TxPage {
+	if txs == nil {
+		txs = Txs{}
+	}
+	return TxPage{
+		Total:    len(txs),
+		Docs:     txs,
+		Status:   true,
+		Decimals: decimals,
+	}
+}
+
+// V1 returns the page in the shape served to the v1 API routes, the golibs types.TxPage.
+// Compared to v2, v1 pages only carry total, docs and status, and v1 transactions keep their inputs and outputs
+// but miss all of their TxExtension.
+func (p TxPage) V1() types.TxPage {
+	txs := make([]types.Tx, 0, len(p.Docs))
+	for _, tx := range p.Docs {
+		txs = append(txs, tx.Tx)
+	}
+	return types.TxPage{
+		Total:  p.Total,
+		Docs:   txs,
+		Status: p.Status,
+	}
+}
+
+// SetInternal flags the internal transactions of the page. They share the ID of their parent transaction,
+// which is sent by an account while internal transfers are sent by a contract.
+func (txs Txs) SetInternal(internal types.Txs) {
+	if len(internal) == 0 {
+		return
+	}
+	keys := make(map[string]bool, len(internal))
+	for _, tx := range internal {
+		keys[internalTxKey(tx)] = true
+	}
+	for i := range txs {
+		txs[i].Internal = keys[internalTxKey(txs[i].Tx)]
+	}
+}
+
+func internalTxKey(tx types.Tx) string {
+	return tx.ID + "/" + tx.From + "/" + tx.To
+}
+
+// SetBlockHashes fills the block hash of the included transactions the provider returned it with
+func (txs Txs) SetBlockHashes(extras TxExtrasIndex) {
+	for i := range txs {
+		if hash := extras[txs[i].ID].BlockHash; hash != "" && txs[i].Block > 0 {
+			txs[i].BlockHash = hash
+		}
+	}
+}
+
+// SetTxSizes fills the size of the transactions the provider returned it with
+func (txs Txs) SetTxSizes(extras TxExtrasIndex) {
+	for i := range txs {
+		if size := extras[txs[i].ID].Size; size.Size > 0 {
+			txs[i].Size, txs[i].VSize = size.Size, size.VSize
+		}
+	}
+}
+
+// SetReplaceable flags the pending transactions the provider returned signalling replace-by-fee
+func (txs Txs) SetReplaceable(extras TxExtrasIndex) {
+	for i := range txs {
+		if txs[i].Status == types.StatusPending {
+			txs[i].RBF = extras[txs[i].ID].RBF
+		}
+	}
+}
+
+// MarshalJSON merges the extension fields into the types.Tx JSON object
+func (t Tx) MarshalJSON() ([]byte, error) {
+	base, err := t.Tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	extension, err := json.Marshal(t.TxExtension)
+	if err != nil {
+		return nil, err
+	}
+	if len(extension) <= 2 {
+		return base, nil
+	}
+	result := make([]byte, 0, len(base)+len(extension))
+	result = append(result, base[:len(base)-1]...)
+	result = append(result, ',')
+	return append(result, extension[1:]...), nil
+}
@@ -0,0 +1,134 @@
+package blockatlas
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+var transferTx = types.Tx{
+	ID:     "95CF63FAA27579A9B6AF84EF8B2DFEAC29627479E9C98E7F5AE4535E213FA4C9",
+	Coin:   coin.BITCOIN,
+	From:   "bc1qhn03cww757mnnlpkdvvfkaydxqygm86nvkm92h",
+	To:     "bc1qjcslq88cht8llqmh3aqshjx9we9msv386jvxl6",
+	Fee:    "1000",
+	Date:   1555117625,
+	Block:  592400,
+	Status: types.StatusCompleted,
+	Meta: types.Transfer{
+		Value:    "100000",
+		Symbol:   "BTC",
+		Decimals: 8,
+	},
+}
+
+func TestNewTxs(t *testing.T) {
+	txs := NewTxs(types.Txs{transferTx})
+	assert.Len(t, txs, 1)
+	assert.Equal(t, transferTx, txs[0].Tx)
+	assert.Equal(t, uint64(592400), txs[0].BlockHeight)
+	assert.Empty(t, txs[0].BlockHash)
+}
+
+func TestTxs_SetBlockHashes(t *testing.T) {
+	pending := transferTx
+	pending.Block = 0
+	txs := NewTxs(types.Txs{transferTx, pending})
+	txs.SetBlockHashes(TxExtrasIndex{transferTx.ID: {BlockHash: "0000000000000000000a7b"}})
+
+	assert.Equal(t, "0000000000000000000a7b", txs[0].BlockHash)
+	assert.Empty(t, txs[1].BlockHash)
+}
+
+func TestTxs_SetTxSizes(t *testing.T) {
+	other := transferTx
+	other.ID = "other"
+	txs := NewTxs(types.Txs{transferTx, other})
+	txs.SetTxSizes(TxExtrasIndex{transferTx.ID: {Size: TxSize{Size: 225, VSize: 144}}})
+
+	assert.Equal(t, uint64(225), txs[0].Size)
+	assert.Equal(t, uint64(144), txs[0].VSize)
+	assert.Zero(t, txs[1].Size)
+
+	raw, err := json.Marshal(txs)
+	assert.Nil(t, err)
+	assert.Contains(t, string(raw), `"size":225,`)
+	assert.Contains(t, string(raw), `"vsize":144`)
+	assert.Equal(t, 1, strings.Count(string(raw), `"size"`))
+}
+
+func TestTx_MarshalJSON(t *testing.T) {
+	txs := NewTxs(types.Txs{transferTx})
+	txs[0].BlockHash = "0000000000000000000a7b"
+
+	raw, err := json.Marshal(NewTxPage(txs, 8))
+	assert.Nil(t, err)
+
+	var page struct {
+		Docs     []map[string]interface{} `json:"docs"`
+		Decimals uint                     `json:"decimals"`
+	}
+	assert.Nil(t, json.Unmarshal(raw, &page))
+	assert.Len(t, page.Docs, 1)
+	assert.Equal(t, transferTx.ID, page.Docs[0]["id"])
+	assert.Equal(t, "transfer", page.Docs[0]["type"])
+	assert.Equal(t, float64(592400), page.Docs[0]["block_height"])
+	assert.Equal(t, "0000000000000000000a7b", page.Docs[0]["block_hash"])
+	assert.Equal(t, uint(8), page.Decimals)
+}
+
+func TestTxPage_MarshalJSON_Deterministic(t *testing.T) {
+	token := transferTx
+	token.ID = "0x7e0c"
+	token.Meta = types.TokenTransfer{Name: "Tether", Symbol: "USDT", TokenID: "0xdac17f958d2ee523a2206206994597c13d831ec7", Value: "1"}
+	txs := NewTxs(types.Txs{transferTx, token})
+	txs.SetLabels(TxLabels{transferTx.ID: {"rent", "salary"}, token.ID: {"swap"}})
+	txs.SetTokenLogos(TokenLogos{"0xdac17f958d2ee523a2206206994597c13d831ec7": "https://assets/usdt.png", "0x1": "https://assets/1.png"})
+	page := NewTxPage(txs, 8)
+	page.Addresses = []XpubAddress{{Address: transferTx.From, Transfers: 1}, {Address: transferTx.To, Transfers: 2}}
+
+	first, err := json.Marshal(page)
+	assert.Nil(t, err)
+	for i := 0; i < 10; i++ {
+		raw, err := json.Marshal(page)
+		assert.Nil(t, err)
+		assert.Equal(t, first, raw)
+	}
+
+	labels, err := json.Marshal(TxLabels{"b": {"swap"}, "c": {"rent"}, "a": {"salary"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":["salary"],"b":["swap"],"c":["rent"]}`, string(labels))
+}
+
+func TestNewTxPage_Empty(t *testing.T) {
+	raw, err := json.Marshal(NewTxPage(nil, 18))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"total":0,"docs":[],"status":true,"decimals":18,"provider":"","fetched_at":0,"from_cache":false}`, string(raw))
+}
+
+func TestNewTxSource(t *testing.T) {
+	page := NewTxPage(nil, 18)
+	page.TxSource = NewTxSource("bitcoin")
+	raw, err := json.Marshal(page)
+	assert.Nil(t, err)
+	var result map[string]interface{}
+	assert.Nil(t, json.Unmarshal(raw, &result))
+	assert.Equal(t, "bitcoin", result["provider"])
+	assert.Equal(t, false, result["from_cache"])
+	assert.InDelta(t, time.Now().Unix(), result["fetched_at"], 5)
+}
+
+func TestTxs_SetInternal(t *testing.T) {
+	internal := types.Txs{{ID: "call", From: "0xcontract", To: "0xuser"}}
+	txs := NewTxs(types.Txs{{ID: "new"}, internal[0], {ID: "call", From: "0xuser", To: "0xcontract"}})
+
+	txs.SetInternal(internal)
+	assert.False(t, txs[0].Internal)
+	assert.True(t, txs[1].Internal)
+	assert.False(t, txs[2].Internal)
+}
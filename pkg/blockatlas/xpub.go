@@ -0,0 +1,183 @@
+package blockatlas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/trustwallet/golibs/types"
+)
+
+const (
+	// maxAddressLookups is the number of addresses derived from an XPUB queried concurrently
+	maxAddressLookups = 10
+	// xpubLength is the length of a base58 encoded extended key
+	xpubLength = 111
+)
+
+// Derivation is the standard the addresses of an XPUB are derived with
+type Derivation string
+
+const (
+	DerivationNone  Derivation = ""
+	DerivationBIP44 Derivation = "bip44"
+	DerivationBIP49 Derivation = "bip49"
+	DerivationBIP84 Derivation = "bip84"
+)
+
+// xpubDerivations are the standards implied by the XPUB prefixes. Keys of the BIP44 prefixes are also used
+// by wallets deriving BIP49 or BIP84 addresses, the others only fit their own standard.
+var xpubDerivations = map[string]Derivation{
+	"xpub": DerivationBIP44,
+	"tpub": DerivationBIP44,
+	"Ltub": DerivationBIP44,
+	"dgub": DerivationBIP44,
+	"ypub": DerivationBIP49,
+	"upub": DerivationBIP49,
+	"Mtub": DerivationBIP49,
+	"zpub": DerivationBIP84,
+	"vpub": DerivationBIP84,
+}
+
+func (d Derivation) IsValid() bool {
+	switch d {
+	case DerivationNone, DerivationBIP44, DerivationBIP49, DerivationBIP84:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValidXpub reports whether the key looks like an extended public key of a known prefix, the checksum
+// is left to the provider
+func IsValidXpub(xpub string) bool {
+	if len(xpub) != xpubLength {
+		return false
+	}
+	_, ok := xpubDerivations[xpub[:4]]
+	return ok
+}
+
+// XpubDescriptor returns the key to look the XPUB up with for the derivation: the XPUB itself when its prefix
+// implies the derivation, else its output descriptor (BIP 380). Incompatible prefixes are an ErrInvalidKey.
+func XpubDescriptor(xpub string, d Derivation) (string, error) {
+	if d == DerivationNone {
+		return xpub, nil
+	}
+	if len(xpub) < 4 {
+		return "", ErrInvalidKey
+	}
+	implied, ok := xpubDerivations[xpub[:4]]
+	switch {
+	case !ok:
+		return "", ErrInvalidKey
+	case implied == d:
+		return xpub, nil
+	case implied != DerivationBIP44:
+		return "", ErrInvalidKey
+	case d == DerivationBIP49:
+		return fmt.Sprintf("sh(wpkh(%s))", xpub), nil
+	default:
+		return fmt.Sprintf("wpkh(%s)", xpub), nil
+	}
+}
+
+// XpubAddress is an address derived from an XPUB with the number of transfers it had
+type XpubAddress struct {
+	Address   string `json:"address"`
+	Path      string `json:"path"`
+	Index     uint32 `json:"index"`
+	Transfers int64  `json:"transfers"`
+}
+
+// GetDerivationIndex returns the address index of a BIP32 path, the last non hardened level
+func GetDerivationIndex(path string) (uint32, bool) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(path[i+1:], 10, 31)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(index), true
+}
+
+// FirstXpubAddresses returns the receiving addresses of the lowest indexes, at most count of them.
+// Change addresses are left out
+func FirstXpubAddresses(addresses []XpubAddress, count int) []XpubAddress {
+	result := make([]XpubAddress, 0, count)
+	for _, address := range addresses {
+		if isReceivingPath(address.Path) {
+			result = append(result, address)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Index < result[j].Index
+	})
+	if len(result) > count {
+		result = result[:count]
+	}
+	return result
+}
+
+// isReceivingPath reports whether the BIP32 path is on the external chain, the level before the address index
+func isReceivingPath(path string) bool {
+	levels := strings.Split(path, "/")
+	return len(levels) >= 2 && levels[len(levels)-2] == "0"
+}
+
+// UsedXpubAddresses keeps the derived addresses which had transfers
+func UsedXpubAddresses(addresses []XpubAddress) []XpubAddress {
+	result := make([]XpubAddress, 0)
+	for _, address := range addresses {
+		if address.Transfers > 0 {
+			result = append(result, address)
+		}
+	}
+	return result
+}
+
+func GetXpubAddressList(addresses []XpubAddress) []string {
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, address.Address)
+	}
+	return result
+}
+
+// GetTokenTxsByAddresses returns the token transactions of all the addresses, the first error fails the lookup
+func GetTokenTxsByAddresses(api TokenTxAPI, addresses []string, token string) (types.Txs, error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		result  = make(types.Txs, 0)
+		lastErr error
+	)
+	sem := make(chan struct{}, maxAddressLookups)
+	for _, address := range addresses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(address string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			txs, err := api.GetTokenTxsByAddress(address, token)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			result = append(result, txs...)
+		}(address)
+	}
+	wg.Wait()
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package blockatlas
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+func TestGetDerivationIndex(t *testing.T) {
+	tests := []struct {
+		path  string
+		index uint32
+		ok    bool
+	}{
+		{"m/84'/0'/0'/0/5", 5, true},
+		{"m/44'/2'/0'/1/120", 120, true},
+		{"m/84'/0'/0'", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			index, ok := GetDerivationIndex(tt.path)
+			assert.Equal(t, tt.index, index)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestUsedXpubAddresses(t *testing.T) {
+	addresses := []XpubAddress{
+		{Address: "bc1qused", Path: "m/84'/0'/0'/0/0", Index: 0, Transfers: 2},
+		{Address: "bc1qunused", Path: "m/84'/0'/0'/0/1", Index: 1},
+	}
+	assert.Equal(t, addresses[:1], UsedXpubAddresses(addresses))
+	assert.Equal(t, []string{"bc1qused", "bc1qunused"}, GetXpubAddressList(addresses))
+}
+
+func TestFirstXpubAddresses(t *testing.T) {
+	addresses := []XpubAddress{
+		{Address: "bc1qreceive2", Path: "m/84'/0'/0'/0/2", Index: 2},
+		{Address: "bc1qchange0", Path: "m/84'/0'/0'/1/0", Index: 0},
+		{Address: "bc1qreceive0", Path: "m/84'/0'/0'/0/0", Index: 0, Transfers: 1},
+		{Address: "bc1qreceive1", Path: "m/84'/0'/0'/0/1", Index: 1},
+	}
+	assert.Equal(t, []string{"bc1qreceive0", "bc1qreceive1"}, GetXpubAddressList(FirstXpubAddresses(addresses, 2)))
+	assert.Equal(t, []string{"bc1qreceive0", "bc1qreceive1", "bc1qreceive2"}, GetXpubAddressList(FirstXpubAddresses(addresses, 20)))
+}
+
+func TestIsValidXpub(t *testing.T) {
+	assert.True(t, IsValidXpub("zpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtihrC"))
+	assert.False(t, IsValidXpub("zpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtih"))
+	assert.False(t, IsValidXpub("qpub6ruK9k6YGm8BRHWvTiQcrEPnFkuRDJhR7mPYzV2LDvjpLa5CuGgrhCYVZjMGcLcFqv9b2WvsFtY2Gb3xq8NVq8qhk9veozrA2W9QaWtihrC"))
+	assert.False(t, IsValidXpub(""))
+}
+
+type tokenTxsPlatform map[string]types.Txs
+
+func (p tokenTxsPlatform) Coin() coin.Coin {
+	return coin.Bitcoin()
+}
+
+func (p tokenTxsPlatform) GetTokenTxsByAddress(address, token string) (types.Txs, error) {
+	txs, ok := p[address]
+	if !ok {
+		return nil, ErrSourceConn
+	}
+	return txs, nil
+}
+
+func TestGetTokenTxsByAddresses(t *testing.T) {
+	api := tokenTxsPlatform{
+		"a": {{ID: "1"}, {ID: "2"}},
+		"b": {{ID: "3"}},
+		"c": {},
+	}
+
+	txs, err := GetTokenTxsByAddresses(api, []string{"a", "b", "c"}, "token")
+	assert.Nil(t, err)
+	ids := make([]string, 0)
+	for _, tx := range txs {
+		ids = append(ids, tx.ID)
+	}
+	sort.Strings(ids)
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+
+	txs, err = GetTokenTxsByAddresses(api, nil, "token")
+	assert.Nil(t, err)
+	assert.Empty(t, txs)
+
+	_, err = GetTokenTxsByAddresses(api, []string{"a", "unknown"}, "token")
+	assert.Equal(t, ErrSourceConn, err)
+}
+
+func TestXpubDescriptor(t *testing.T) {
+	tests := []struct {
+		xpub       string
+		derivation Derivation
+		want       string
+		wantErr    error
+	}{
+		{"xpub6C", DerivationNone, "xpub6C", nil},
+		{"xpub6C", DerivationBIP44, "xpub6C", nil},
+		{"xpub6C", DerivationBIP49, "sh(wpkh(xpub6C))", nil},
+		{"xpub6C", DerivationBIP84, "wpkh(xpub6C)", nil},
+		{"ypub6W", DerivationBIP49, "ypub6W", nil},
+		{"zpub6r", DerivationBIP84, "zpub6r", nil},
+		{"zpub6r", DerivationBIP44, "", ErrInvalidKey},
+		{"ypub6W", DerivationBIP84, "", ErrInvalidKey},
+		{"abc", DerivationBIP84, "", ErrInvalidKey},
+		{"unknown", DerivationBIP84, "", ErrInvalidKey},
+		{"unknown", DerivationNone, "unknown", nil},
+	}
+	for _, tt := range tests {
+		got, err := XpubDescriptor(tt.xpub, tt.derivation)
+		assert.Equal(t, tt.wantErr, err, tt.xpub, tt.derivation)
+		assert.Equal(t, tt.want, got, tt.xpub, tt.derivation)
+	}
+}
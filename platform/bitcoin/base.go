@@ -1,21 +1,22 @@
 package bitcoin
 
 import (
+	"context"
+
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/blockatlas/platform/bitcoin/blockbook"
-	"github.com/trustwallet/golibs/client"
 	"github.com/trustwallet/golibs/coin"
-	"github.com/trustwallet/golibs/network/middleware"
 )
 
 type Platform struct {
-	client    blockbook.Client
+	client    *blockbook.Client
 	CoinIndex uint
 }
 
 func Init(coin uint, api string) *Platform {
 	return &Platform{
 		CoinIndex: coin,
-		client:    blockbook.Client{Request: client.InitClient(api, middleware.SentryErrorHandler)},
+		client:    blockbook.InitClient(api),
 	}
 }
 
@@ -23,11 +24,24 @@ func (p *Platform) Coin() coin.Coin {
 	return coin.Coins[p.CoinIndex]
 }
 
-func (p *Platform) GetAddressesFromXpub(xpub string) ([]string, error) {
+func (p *Platform) WithContext(ctx context.Context) blockatlas.Platform {
+	return &Platform{client: p.client.WithContext(ctx), CoinIndex: p.CoinIndex}
+}
+
+func (p *Platform) GetAddressesFromXpub(xpub string) ([]blockatlas.XpubAddress, error) {
 	tokens, err := p.client.GetAddressesFromXpub(xpub)
-	addresses := make([]string, 0)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]blockatlas.XpubAddress, 0, len(tokens))
 	for _, token := range tokens {
-		addresses = append(addresses, token.Name)
+		index, _ := blockatlas.GetDerivationIndex(token.Path)
+		addresses = append(addresses, blockatlas.XpubAddress{
+			Address:   token.Name,
+			Path:      token.Path,
+			Index:     index,
+			Transfers: token.Transfers,
+		})
 	}
-	return addresses, err
+	return addresses, nil
 }
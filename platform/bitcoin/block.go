@@ -1,11 +1,18 @@
 package bitcoin
 
-import "github.com/trustwallet/golibs/types"
+import (
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
 
 func (p *Platform) CurrentBlockNumber() (int64, error) {
 	return p.client.GetCurrentBlockNumber()
 }
 
+func (p *Platform) GetCurrentBlockHeight() (blockatlas.BlockHead, error) {
+	return p.client.GetCurrentBlockHeight()
+}
+
 func (p *Platform) GetBlockByNumber(num int64) (*types.Block, error) {
 	block, err := p.client.GetAllTransactionsByBlockNumber(num)
 	if err != nil {
@@ -21,3 +28,13 @@ func (p *Platform) GetBlockByNumber(num int64) (*types.Block, error) {
 	}, nil
 
 }
+
+// GetBalances reads the block first, the balances cover at least its transactions
+func (p *Platform) GetBalances(address string) (map[string]types.Amount, int64, error) {
+	block, err := p.client.GetCurrentBlockNumber()
+	if err != nil {
+		return nil, 0, err
+	}
+	balances, err := p.client.GetBalances(address, p.CoinIndex)
+	return balances, block, err
+}
@@ -3,6 +3,7 @@ package blockbook
 import (
 	"strings"
 
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -28,3 +29,30 @@ func (c *Client) GetBlockByNumber(num int64, coinIndex uint) (*types.Block, erro
 		Txs:    txs,
 	}, nil
 }
+
+// addTxExtras returns the block hash, size and replace-by-fee signal of the transactions of the page
+// to the request the client is bound to, see blockatlas.TrackTxExtras
+func (c *Client) addTxExtras(page TransactionsList) {
+	if c.ctx == nil {
+		return
+	}
+	for _, tx := range page.TransactionList() {
+		blockatlas.AddTxExtras(c.ctx, tx.ID, getTxExtras(tx))
+	}
+}
+
+// getTxExtras returns the extras of the transaction.
+// The virtual size defaults to the size for coins without segregated witness.
+func getTxExtras(tx Transaction) blockatlas.TxExtras {
+	extras := blockatlas.TxExtras{RBF: tx.IsReplaceable()}
+	if tx.BlockHeight > 0 {
+		extras.BlockHash = tx.BlockHash
+	}
+	if tx.Size > 0 {
+		extras.Size = blockatlas.TxSize{Size: tx.Size, VSize: tx.VSize}
+		if extras.Size.VSize == 0 {
+			extras.Size.VSize = tx.Size
+		}
+	}
+	return extras
+}
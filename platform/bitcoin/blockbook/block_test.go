@@ -0,0 +1,33 @@
+package blockbook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+func TestClient_addTxExtras(t *testing.T) {
+	page := TransactionsList{Transactions: []Transaction{
+		{ID: "segwit", Size: 225, VSize: 144, BlockHeight: 592400, BlockHash: "0000000000000000000a7b", Confirmations: 1},
+		{ID: "legacy", Size: 226, BlockHeight: 592400, BlockHash: "0000000000000000000a7b", Confirmations: 1},
+		{ID: "flagged", Rbf: true, BlockHeight: -1},
+		{ID: "sequence", Vin: []Output{{Sequence: 0xffffffff}, {Sequence: 0xfffffffd}}},
+		{ID: "final", Vin: []Output{{Sequence: 0xfffffffe}, {Sequence: 0xffffffff}}},
+		{ID: "confirmed", Confirmations: 1, Rbf: true},
+	}}
+	ctx := blockatlas.TrackTxExtras(context.Background())
+	(&Client{}).WithContext(ctx).addTxExtras(page)
+
+	extras := blockatlas.GetTxExtras(ctx)
+	assert.Equal(t, blockatlas.TxExtras{BlockHash: "0000000000000000000a7b", Size: blockatlas.TxSize{Size: 225, VSize: 144}}, extras["segwit"])
+	assert.Equal(t, blockatlas.TxExtras{BlockHash: "0000000000000000000a7b", Size: blockatlas.TxSize{Size: 226, VSize: 226}}, extras["legacy"])
+	assert.Equal(t, blockatlas.TxExtras{RBF: true}, extras["flagged"])
+	assert.True(t, extras["sequence"].RBF)
+	assert.False(t, extras["final"].RBF)
+	assert.False(t, extras["confirmed"].RBF)
+
+	// unbound clients, e.g. of the parser, don't return extras
+	(&Client{}).addTxExtras(page)
+}
@@ -1,6 +1,7 @@
 package blockbook
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,12 +9,38 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/client"
+	"github.com/trustwallet/golibs/network/middleware"
 	"github.com/trustwallet/golibs/types"
 )
 
 type Client struct {
 	client.Request
+	// ctx is the context of the requests, see WithContext
+	ctx context.Context
+}
+
+func InitClient(api string) *Client {
+	return &Client{
+		Request: client.InitClient(api, middleware.SentryErrorHandler),
+	}
+}
+
+// WithContext returns a copy of the client sending its requests with the context,
+// the address and xpub lookups return the extras of their transactions in it
+func (c *Client) WithContext(ctx context.Context) *Client {
+	bound := *c
+	bound.ctx = ctx
+	return &bound
+}
+
+func (c *Client) get(result interface{}, path string, query url.Values) error {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.GetWithContext(result, path, query, ctx)
 }
 
 type ClientError struct {
@@ -27,17 +54,31 @@ func (c *ClientError) Error() string {
 // Block
 
 func (c *Client) GetCurrentBlockNumber() (int64, error) {
-	var nodeInfo NodeInfo
-	err := c.Get(&nodeInfo, "api/v2", nil)
+	head, err := c.GetCurrentBlockHeight()
 	if err != nil {
 		return 0, err
 	}
+	return head.Height, nil
+}
+
+// GetCurrentBlockHeight returns the latest block indexed by Blockbook, its hash is only
+// known when the backend node is at the same height
+func (c *Client) GetCurrentBlockHeight() (blockatlas.BlockHead, error) {
+	var nodeInfo NodeInfo
+	err := c.get(&nodeInfo, "api/v2", nil)
+	if err != nil {
+		return blockatlas.BlockHead{}, err
+	}
 	// If not in sync, latest block might not be available yet.
 	if !nodeInfo.Blockbook.InSync {
-		return 0, errors.New("not in sync to get current block number")
+		return blockatlas.BlockHead{}, errors.New("not in sync to get current block number")
 	}
 
-	return nodeInfo.Blockbook.BestHeight, nil
+	head := blockatlas.BlockHead{Height: nodeInfo.Blockbook.BestHeight}
+	if nodeInfo.Backend != nil && nodeInfo.Backend.Blocks == head.Height {
+		head.Hash = nodeInfo.Backend.BestBlockHash
+	}
+	return head, nil
 }
 
 // Tokens
@@ -46,11 +87,22 @@ func (c *Client) GetTokens(address string) ([]Token, error) {
 	var res TransactionsList
 	path := fmt.Sprintf("api/v2/address/%s", address)
 	query := url.Values{"details": {"tokenBalances"}}
-	err := c.Get(&res, path, query)
+	err := c.get(&res, path, query)
 	return res.Tokens, err
 }
 
 // Transactions
+// IsContract tells contract addresses by the contract details Blockbook reports for them.
+// Blockbook versions without contractInfo only report token contracts.
+func (c *Client) IsContract(address string) (bool, error) {
+	var res TransactionsList
+	path := fmt.Sprintf("api/v2/address/%s", address)
+	if err := c.get(&res, path, url.Values{"details": {"basic"}}); err != nil {
+		return false, err
+	}
+	return res.ContractInfo != nil || res.Erc20Contract != nil, nil
+}
+
 func (c *Client) GetAllTransactionsByBlockNumber(num int64) ([]Transaction, error) {
 	page := int64(1)
 	block, err := c.GetTransactionsByBlockNumber(num, page)
@@ -71,11 +123,21 @@ func (c *Client) GetAllTransactionsByBlockNumber(num int64) ([]Transaction, erro
 }
 
 func (c *Client) GetTxs(address string) (TransactionsList, error) {
-	return c.getTransactionsForContract(address, "", types.TxPerPage)
+	return c.getTransactionsForContract(address, "", 1, types.TxPerPage)
+}
+
+// GetTxsPage returns a page of the address transactions, pages start at 1
+func (c *Client) GetTxsPage(address string, page int) (TransactionsList, error) {
+	return c.getTransactionsForContract(address, "", page, types.TxPerPage)
+}
+
+// GetTxsLimit returns the latest limit transactions of the address
+func (c *Client) GetTxsLimit(address string, limit int) (TransactionsList, error) {
+	return c.getTransactionsForContract(address, "", 1, limit)
 }
 
 func (c *Client) GetTxsWithContract(address, contract string) (TransactionsList, error) {
-	return c.getTransactionsForContract(address, contract, types.TxPerPage)
+	return c.getTransactionsForContract(address, contract, 1, types.TxPerPage)
 }
 
 func (c *Client) GetTransactionsByBlockNumber(number int64, page int64) (block TransactionsList, err error) {
@@ -83,18 +145,35 @@ func (c *Client) GetTransactionsByBlockNumber(number int64, page int64) (block T
 	args := url.Values{
 		"page": {strconv.FormatInt(page, 10)},
 	}
-	err = c.Get(&block, path, args)
+	err = c.get(&block, path, args)
 	return block, err
 }
 
-func (c *Client) getTransactionsForContract(address, contract string, limit int) (transactions TransactionsList, err error) {
-	path := fmt.Sprintf("api/v2/address/%s", address)
-	err = c.Get(&transactions, path, url.Values{
+// GetTxsWithoutContracts returns the address transactions without token transfers, filtered by Blockbook
+func (c *Client) GetTxsWithoutContracts(address string) (TransactionsList, error) {
+	return c.getTransactions(address, url.Values{
 		"page":     {"1"},
 		"details":  {"txs"},
+		"pageSize": {strconv.Itoa(types.TxPerPage)},
+		"filter":   {"0"},
+	})
+}
+
+func (c *Client) getTransactionsForContract(address, contract string, page, limit int) (TransactionsList, error) {
+	return c.getTransactions(address, url.Values{
+		"page":     {strconv.Itoa(page)},
+		"details":  {"txs"},
 		"pageSize": {strconv.Itoa(limit)},
 		"contract": {contract},
 	})
+}
+
+func (c *Client) getTransactions(address string, query url.Values) (transactions TransactionsList, err error) {
+	path := fmt.Sprintf("api/v2/address/%s", address)
+	err = c.get(&transactions, path, query)
+	if err == nil {
+		c.addTxExtras(transactions)
+	}
 	return transactions, err
 }
 
@@ -105,7 +184,10 @@ func (c *Client) GetTransactionsByXpub(xpub string) (transactions TransactionsLi
 		"details":  {"txs"},
 		"tokens":   {"derived"},
 	}
-	err = c.Get(&transactions, path, args)
+	err = c.get(&transactions, path, args)
+	if err == nil {
+		c.addTxExtras(transactions)
+	}
 	return transactions, err
 }
 
@@ -117,7 +199,7 @@ func (c *Client) GetAddressesFromXpub(xpub string) (tokens []Token, err error) {
 		"tokens":   {"derived"},
 	}
 	var transactions TransactionsList
-	err = c.Get(&transactions, path, args)
+	err = c.get(&transactions, path, args)
 	return transactions.Tokens, err
 }
 
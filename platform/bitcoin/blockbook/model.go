@@ -9,6 +9,7 @@ import (
 
 type NodeInfo struct {
 	Blockbook *Blockbook `json:"blockbook"`
+	Backend   *Backend   `json:"backend"`
 }
 
 type Blockbook struct {
@@ -16,6 +17,11 @@ type Blockbook struct {
 	InSync     bool  `json:"inSync"`
 }
 
+type Backend struct {
+	Blocks        int64  `json:"blocks"`
+	BestBlockHash string `json:"bestBlockHash"`
+}
+
 type TokenTransfer struct {
 	Decimals uint   `json:"decimals"`
 	From     string `json:"from"`
@@ -35,6 +41,9 @@ type Token struct {
 	Name     string          `json:"name"`
 	Symbol   string          `json:"symbol"`
 	Type     types.TokenType `json:"type"`
+	// Path and Transfers are set on the addresses derived from an XPUB
+	Path      string `json:"path,omitempty"`
+	Transfers int64  `json:"transfers,omitempty"`
 }
 
 // EthereumSpecific contains ethereum specific transaction data
@@ -45,6 +54,16 @@ type EthereumSpecific struct {
 	GasUsed  *big.Int `json:"gasUsed"`
 	GasPrice string   `json:"gasPrice"`
 	Data     string   `json:"data,omitempty"`
+	// InternalTransfers is the value moved by contract calls, only reported by Blockbook nodes indexing internal data
+	InternalTransfers []InternalTransfer `json:"internalTransfers,omitempty"`
+}
+
+// InternalTransfer describes value moved by a contract call inside a transaction
+type InternalTransfer struct {
+	Type  int    `json:"type"` // 0 call, 1 create, 2 selfdestruct
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
 }
 
 type TransactionsList struct {
@@ -55,7 +74,11 @@ type TransactionsList struct {
 	Txs          interface{}   `json:"txs,omitempty"`
 	Tokens       []Token       `json:"tokens,omitempty"`
 	TxCount      int64         `json:"txCount,omitempty"`
-	Hash         string        `json:"hash,omitempty"`
+	Balance      string        `json:"balance,omitempty"`
+	// ContractInfo and Erc20Contract are reported for contract addresses, see IsContract
+	ContractInfo  interface{} `json:"contractInfo,omitempty"`
+	Erc20Contract interface{} `json:"erc20Contract,omitempty"`
+	Hash          string      `json:"hash,omitempty"`
 }
 
 func (tl *TransactionsList) TransactionList() []Transaction {
@@ -86,6 +109,9 @@ type Transaction struct {
 	Value            string            `json:"value"`
 	ValueOut         string            `json:"valueOut"`
 	Fees             string            `json:"fees"`
+	Size             uint64            `json:"size,omitempty"`
+	VSize            uint64            `json:"vsize,omitempty"`
+	Rbf              bool              `json:"rbf,omitempty"`
 	TokenTransfers   []TokenTransfer   `json:"tokenTransfers,omitempty"`
 	EthereumSpecific *EthereumSpecific `json:"ethereumSpecific,omitempty"`
 }
@@ -116,6 +142,7 @@ type Output struct {
 	Value        string       `json:"value,omitempty"`
 	Addresses    []string     `json:"addresses,omitempty"`
 	ScriptPubKey ScriptPubKey `json:"scriptPubKey,omitempty"`
+	Sequence     uint32       `json:"sequence,omitempty"`
 }
 
 type ScriptPubKey struct {
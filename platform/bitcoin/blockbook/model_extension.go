@@ -26,6 +26,26 @@ func (transaction *Transaction) FromAddress() string {
 	return ""
 }
 
+// rbfSequence is the highest input sequence signalling replace-by-fee
+const rbfSequence = 0xfffffffd
+
+// IsReplaceable tells whether a pending transaction signals replace-by-fee (BIP 125), either reported by
+// Blockbook or by an input sequence. Blockbook omits zero sequences, which also signal it.
+func (transaction *Transaction) IsReplaceable() bool {
+	if transaction.GetStatus() != types.StatusPending {
+		return false
+	}
+	if transaction.Rbf {
+		return true
+	}
+	for _, input := range transaction.Vin {
+		if input.Sequence > 0 && input.Sequence <= rbfSequence {
+			return true
+		}
+	}
+	return false
+}
+
 func (transaction *Transaction) GetFee() string {
 	status, _ := transaction.EthereumSpecific.GetStatus()
 	if status != types.StatusPending {
@@ -57,3 +77,13 @@ func GetDirection(address, from, to string) types.Direction {
 	}
 	return types.DirectionIncoming
 }
+
+// IsCollectible reports whether the transfer moves an ERC-721 or ERC-1155 token
+func (t TokenTransfer) IsCollectible() bool {
+	switch types.TokenType(t.Type) {
+	case types.ERC721, types.ERC1155:
+		return true
+	default:
+		return false
+	}
+}
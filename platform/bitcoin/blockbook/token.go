@@ -1,6 +1,11 @@
 package blockbook
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/trustwallet/golibs/asset"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -12,6 +17,39 @@ func (c *Client) GetTokenList(address string, coinIndex uint) ([]types.Token, er
 	return NormalizeTokens(tokens, coinIndex), nil
 }
 
+// GetTokenBalance returns the balance of the token contract held by the address, zero if it holds none
+func (c *Client) GetTokenBalance(address, token string) (types.Amount, error) {
+	tokens, err := c.GetTokens(address)
+	if err != nil {
+		return "", err
+	}
+	for _, srcToken := range tokens {
+		if strings.EqualFold(srcToken.Contract, token) && srcToken.Balance != "" {
+			return types.Amount(srcToken.Balance), nil
+		}
+	}
+	return "0", nil
+}
+
+// GetBalances returns the balances of the address by asset id, the native coin and the tokens it holds
+func (c *Client) GetBalances(address string, coinIndex uint) (map[string]types.Amount, error) {
+	var res TransactionsList
+	path := fmt.Sprintf("api/v2/address/%s", address)
+	if err := c.get(&res, path, url.Values{"details": {"tokenBalances"}}); err != nil {
+		return nil, err
+	}
+	balances := map[string]types.Amount{asset.BuildID(coinIndex, ""): "0"}
+	if res.Balance != "" {
+		balances[asset.BuildID(coinIndex, "")] = types.Amount(res.Balance)
+	}
+	for _, token := range res.Tokens {
+		if token.Balance != "" {
+			balances[asset.BuildID(coinIndex, token.Contract)] = types.Amount(token.Balance)
+		}
+	}
+	return balances, nil
+}
+
 func NormalizeTokens(tokens []Token, coinIndex uint) []types.Token {
 	assets := make([]types.Token, 0)
 	for _, srcToken := range tokens {
@@ -1,9 +1,12 @@
 package blockbook
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -72,3 +75,51 @@ func TestNormalizeToken(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetBalances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/address/0xa", r.URL.Path)
+		assert.Equal(t, "tokenBalances", r.URL.Query().Get("details"))
+		_, _ = w.Write([]byte(`{"balance":"1000","tokens":[{"contract":"0xt","balance":"5"},{"contract":"0xu"}]}`))
+	}))
+	defer server.Close()
+
+	balances, err := InitClient(server.URL).GetBalances("0xa", 60)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]types.Amount{"c60": "1000", "c60_t0xt": "5"}, balances)
+}
+
+func TestClient_GetTokenBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tokenBalances", r.URL.Query().Get("details"))
+		switch r.URL.Path {
+		case "/api/v2/address/0xa":
+			_, _ = w.Write([]byte(`{"balance":"1000","tokens":[{"contract":"0xdAC17F958D2ee523a2206206994597C13D831ec7","balance":"5"},{"contract":"0xdai"}]}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid address"}`))
+		}
+	}))
+	defer server.Close()
+	client := InitClient(server.URL)
+
+	tests := []struct {
+		name    string
+		address string
+		token   string
+		want    types.Amount
+		wantErr bool
+	}{
+		{"held token", "0xa", "0xdac17f958d2ee523a2206206994597c13d831ec7", "5", false},
+		{"token without balance", "0xa", "0xdai", "0", false},
+		{"token not held", "0xa", "0xusdc", "0", false},
+		{"provider error", "0xinvalid", "0xdai", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance, err := client.GetTokenBalance(tt.address, tt.token)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, balance)
+		})
+	}
+}
@@ -16,6 +16,15 @@ func (c *Client) GetTransactions(address string, coinIndex uint) (types.Txs, err
 	return NormalizePage(page, address, "", coinIndex), nil
 }
 
+// GetNativeTxs returns the address transactions without token transfers
+func (c *Client) GetNativeTxs(address string, coinIndex uint) (types.Txs, error) {
+	page, err := c.GetTxsWithoutContracts(address)
+	if err != nil {
+		return nil, err
+	}
+	return NormalizePage(page, address, "", coinIndex), nil
+}
+
 func (c *Client) GetTokenTxs(address, token string, coinIndex uint) (types.Txs, error) {
 	page, err := c.GetTxsWithContract(address, token)
 	if err != nil {
@@ -24,6 +33,55 @@ func (c *Client) GetTokenTxs(address, token string, coinIndex uint) (types.Txs,
 	return NormalizePage(page, address, token, coinIndex), nil
 }
 
+// GetTransactionsWithInternal returns the address transactions with their internal transfers, one transaction
+// per transfer, read from the same page
+func (c *Client) GetTransactionsWithInternal(address string, coinIndex uint) (types.Txs, types.Txs, error) {
+	page, err := c.GetTxs(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NormalizePage(page, address, "", coinIndex), NormalizeInternalTransfers(page, address, coinIndex), nil
+}
+
+func NormalizeInternalTransfers(srcPage TransactionsList, address string, coinIndex uint) types.Txs {
+	txs := make(types.Txs, 0)
+	normalizedAddr, err := Address.EIP55Checksum(address)
+	if err != nil {
+		return txs
+	}
+	for _, srcTx := range srcPage.Transactions {
+		if srcTx.EthereumSpecific == nil {
+			continue
+		}
+		status, errReason := srcTx.EthereumSpecific.GetStatus()
+		for _, transfer := range srcTx.EthereumSpecific.InternalTransfers {
+			if transfer.From != normalizedAddr && transfer.To != normalizedAddr {
+				continue
+			}
+			txs = append(txs, types.Tx{
+				ID:        srcTx.ID,
+				Coin:      coinIndex,
+				From:      transfer.From,
+				To:        transfer.To,
+				Fee:       "0",
+				Date:      srcTx.BlockTime,
+				Block:     normalizeBlockHeight(srcTx.BlockHeight),
+				Status:    status,
+				Error:     errReason,
+				Sequence:  srcTx.EthereumSpecific.Nonce,
+				Type:      types.TxTransfer,
+				Direction: GetDirection(normalizedAddr, transfer.From, transfer.To),
+				Meta: types.Transfer{
+					Value:    types.Amount(transfer.Value),
+					Symbol:   coin.Coins[coinIndex].Symbol,
+					Decimals: coin.Coins[coinIndex].Decimals,
+				},
+			})
+		}
+	}
+	return txs
+}
+
 func NormalizePage(srcPage TransactionsList, address, token string, coinIndex uint) (txs types.Txs) {
 	normalizedAddr, err := Address.EIP55Checksum(address)
 	if err != nil {
@@ -107,6 +165,11 @@ func fillMetaWithAddress(final *types.Tx, tx *Transaction, address, token string
 func fillTokenTransfer(final *types.Tx, tx *Transaction, coinIndex uint) bool {
 	if len(tx.TokenTransfers) == 1 {
 		transfer := tx.TokenTransfers[0]
+		if transfer.IsCollectible() {
+			final.Meta = normalizeCollectibleTransfer(transfer)
+			final.TokenTransfers = []types.TokenTransfer{}
+			return true
+		}
 		final.Meta = types.TokenTransfer{
 			Name:     transfer.Name,
 			Symbol:   transfer.Symbol,
@@ -133,6 +196,12 @@ func fillTokenTransferWithAddress(final *types.Tx, tx *Transaction, address, tok
 				}
 			}
 			direction := GetDirection(address, transfer.From, transfer.To)
+			if transfer.IsCollectible() {
+				final.Direction = direction
+				final.Meta = normalizeCollectibleTransfer(transfer)
+				final.TokenTransfers = []types.TokenTransfer{}
+				return true
+			}
 			metadata := types.TokenTransfer{
 				Name:     transfer.Name,
 				Symbol:   transfer.Symbol,
@@ -159,6 +228,14 @@ func fillTokenTransferWithAddress(final *types.Tx, tx *Transaction, address, tok
 	return false
 }
 
+// normalizeCollectibleTransfer maps ERC-721 and ERC-1155 transfers, their value is the id of the token or a count
+func normalizeCollectibleTransfer(transfer TokenTransfer) types.CollectibleTransfer {
+	return types.CollectibleTransfer{
+		Name:     transfer.Name,
+		Contract: transfer.Token,
+	}
+}
+
 func fillTransferOrContract(final *types.Tx, tx *Transaction, coinIndex uint) {
 	gasUsed := tx.EthereumSpecific.GasUsed
 	if gasUsed != nil && gasUsed.Int64() == 21000 {
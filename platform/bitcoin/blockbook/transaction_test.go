@@ -54,3 +54,63 @@ func TestNormalizeTxs(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeInternalTransfers(t *testing.T) {
+	address := "0x7d8bf18C7cE84b3E175b339c4Ca93aEd1dD166F1"
+	page := TransactionsList{Transactions: []Transaction{
+		{
+			ID:            "0x1",
+			BlockHeight:   100,
+			BlockTime:     1600000000,
+			Confirmations: 10,
+			EthereumSpecific: &EthereumSpecific{
+				Status: 1,
+				Nonce:  3,
+				InternalTransfers: []InternalTransfer{
+					{From: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", To: address, Value: "1000"},
+					{From: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", To: "0x0000000000000000000000000000000000000001", Value: "5"},
+				},
+			},
+		},
+		{ID: "0x2", EthereumSpecific: &EthereumSpecific{Status: 1}},
+	}}
+
+	txs := NormalizeInternalTransfers(page, "0x7d8bf18c7ce84b3e175b339c4ca93aed1dd166f1", 60)
+	assert.Equal(t, types.Txs{{
+		ID:        "0x1",
+		Coin:      60,
+		From:      "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		To:        address,
+		Fee:       "0",
+		Date:      1600000000,
+		Block:     100,
+		Status:    types.StatusCompleted,
+		Sequence:  3,
+		Type:      types.TxTransfer,
+		Direction: types.DirectionIncoming,
+		Meta:      types.Transfer{Value: "1000", Symbol: "ETH", Decimals: 18},
+	}}, txs)
+}
+
+func TestNormalizePage_Collectible(t *testing.T) {
+	address := "0x7d8bf18C7cE84b3E175b339c4Ca93aEd1dD166F1"
+	page := TransactionsList{Transactions: []Transaction{{
+		ID:               "0x1",
+		Vin:              []Output{{Addresses: []string{"0x0000000000000000000000000000000000000001"}}},
+		Vout:             []Output{{Addresses: []string{"0x06012c8cf97BEaD5deAe237070F9587f8E7A266d"}}},
+		EthereumSpecific: &EthereumSpecific{Status: 1},
+		TokenTransfers: []TokenTransfer{{
+			Type:  "ERC721",
+			Name:  "CryptoKitties",
+			Token: "0x06012c8cf97BEaD5deAe237070F9587f8E7A266d",
+			From:  "0x0000000000000000000000000000000000000001",
+			To:    address,
+			Value: "1523",
+		}},
+	}}}
+
+	txs := NormalizePage(page, address, "", 60)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, types.CollectibleTransfer{Name: "CryptoKitties", Contract: "0x06012c8cf97BEaD5deAe237070F9587f8E7A266d"}, txs[0].Meta)
+	assert.Equal(t, types.DirectionIncoming, txs[0].Direction)
+}
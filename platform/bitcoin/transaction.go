@@ -3,6 +3,7 @@ package bitcoin
 import (
 	"sort"
 
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/blockatlas/platform/bitcoin/blockbook"
 
 	mapset "github.com/deckarep/golang-set"
@@ -20,6 +21,30 @@ func (p *Platform) GetTxsByAddress(address string) (types.Txs, error) {
 	return txs, nil
 }
 
+// GetTxsByAddressPage returns a page of the address transactions and whether more pages follow
+func (p *Platform) GetTxsByAddressPage(address string, page int) (types.Txs, bool, error) {
+	sourceTxs, err := p.client.GetTxsPage(address, page)
+	if err != nil {
+		return nil, false, err
+	}
+	addressSet := blockatlas.NewAddressSet(p.CoinIndex, address)
+	txs := normalizeTxs(sourceTxs, p.CoinIndex, addressSet)
+	sort.Sort(txs)
+	return txs, sourceTxs.Page < sourceTxs.TotalPages, nil
+}
+
+// GetTxsByAddressLimit returns the latest limit transactions of the address, Blockbook limits the page size
+func (p *Platform) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	sourceTxs, err := p.client.GetTxsLimit(address, limit)
+	if err != nil {
+		return nil, err
+	}
+	addressSet := blockatlas.NewAddressSet(p.CoinIndex, address)
+	txs := normalizeTxs(sourceTxs, p.CoinIndex, addressSet)
+	sort.Sort(txs)
+	return txs, nil
+}
+
 func (p *Platform) GetTxsByXpub(xpub string) (types.Txs, error) {
 	txs, err := p.getTxsByXpub(xpub)
 	if err != nil {
@@ -36,10 +61,11 @@ func (p *Platform) getTxsByXpub(xpub string) (types.Txs, error) {
 		return types.Txs{}, err
 	}
 
-	addressSet := mapset.NewSet()
+	addresses := make([]string, 0, len(sourceTxs.Tokens))
 	for _, token := range sourceTxs.Tokens {
-		addressSet.Add(token.Name)
+		addresses = append(addresses, token.Name)
 	}
+	addressSet := blockatlas.NewAddressSet(p.CoinIndex, addresses...)
 
 	txs := normalizeTxs(sourceTxs, p.CoinIndex, addressSet)
 	return txs, nil
@@ -50,8 +76,7 @@ func (p *Platform) getTxsByAddress(address string) (types.Txs, error) {
 	if err != nil {
 		return types.Txs{}, err
 	}
-	addressSet := mapset.NewSet()
-	addressSet.Add(address)
+	addressSet := blockatlas.NewAddressSet(p.CoinIndex, address)
 	txs := normalizeTxs(sourceTxs, p.CoinIndex, addressSet)
 	return txs, nil
 }
@@ -0,0 +1,305 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/config"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+	"gopkg.in/yaml.v2"
+)
+
+// contractAddresses are the addresses recorded in mock/datafiles.yaml by coin handle.
+// Coins missing here are skipped: record a provider response of an address to cover them.
+var contractAddresses = map[string]string{
+	"aeternity":   "ak_2WGWYMgWy1opZxgA8AVzGCTavCQyUBtbKx5SrCX6E4kmDZMtJb",
+	"aion":        "0xa04f0117864ccf5013861a89f08c6fc790284d72356c8a362025d31b855ed6ed",
+	"binance":     "bnb1z35wusfv8twfele77vddclka9z84ugywug48gn",
+	"bitcoin":     "bc1qrfr44n2j4czd5c9txwlnw0yj2h82x9566fglqj",
+	"bitcoincash": "bitcoincash:qq07l6rr5lsdm3m80qxw80ku2ex0tj76vvsxpvmgme",
+	"cosmos":      "cosmos1dx27g0kzhwej0ekcf2k9hsktcxnmpl7fcehcvq",
+	"dash":        "XrcbsQdrFYEzbqA9nCJi8zDtnRZzNKkCtG",
+	"decred":      "DsTxPUVFxXeNgu5fzozr4mTR4tqqMaKcvpY",
+	"digibyte":    "DEs1RJKuASSjfphFJdxX9eidrjWewMZgAi",
+	"doge":        "D5dAUAx3Ezg1q4dRgzKTBsxp4VJietWkDh",
+	"ethereum":    "0x0875BCab22dE3d02402bc38aEe4104e1239374a7",
+	"groestlcoin": "33Ym3fecmWaHD19jymYt6fGd9TqSDQFfQj",
+	"icon":        "hxee691e7bccc4eb11fee922896e9f51490e62b12e",
+	"iotex":       "io1vg808avg2ydye8djl2axmkc9j0xhzu6vdaw6g5",
+	"kava":        "kava1l8va9zyl50cpzv447c694k3jndelc9ygtfll2m",
+	"litecoin":    "ltc1qpm594ntjq6ayqjngf6t9td2dxtey9d7985eept",
+	"nebulas":     "n1RCYwrpLMpSpUCQ8QUDzGRg6B2PnY8R94a",
+	"ontology":    "AUyL4TZ1zFEcSKDJrjFnD7vsq5iFZMZqT7",
+	"qtum":        "QZJbNrGT3cZ1J1AEHtgH3JWM7uLBNAejLZ",
+	"ravencoin":   "RGkwvrUors8DtmhKy5bddFwRCTZaunjpvo",
+	"ripple":      "rMQ98K56yXJbDGv49ZSmW51sLn94Xe1mu1",
+	"stellar":     "GDKIJJIKXLOM2NRMPNQZUUYK24ZPVFC6426GZAEP3KUK6KEJLACCWNMX",
+	"tezos":       "tz1foWxaV3VQyWqFbWTERS6YDJjPT6C7jPp8",
+	"theta":       "0xac0eeb6ee3e32e2c74e14ac74155063e4f4f981f",
+	"tron":        "TFFriedwRtWdFuzerDDtkoQTZ29smDZ1MB",
+	"viacoin":     "VdMPvn7vUTSzbYjiMDs1jku9wAh1Ri2Y1A",
+	"waves":       "3PJ4q4sqriJs2y7Z45wmbLrbmV9MDecbPxD",
+	"zcash":       "t1LwLWo1Mo3s4RPtUpeyUD1eYd47inL3bwX",
+	"zcoin":       "a8EF4cpenEgEn9hm2NL5KfFK1UmSZZaQVn",
+	"zelcash":     "t1JKRwXGfKTGfPV1z48rvoLyabk31z3xwHa",
+	"zilliqa":     "zil1l8ddxvejeam70qang54wnqkgtmlu5mwlgzy64z",
+}
+
+// schema maps the required fields of a JSON object to their JSON type
+type schema map[string]string
+
+var (
+	txPageSchema = schema{"total": "number", "docs": "array", "status": "boolean", "decimals": "number"}
+	txSchema     = schema{
+		"id": "string", "coin": "number", "from": "string", "to": "string", "fee": "string", "date": "number",
+		"block": "number", "status": "string", "sequence": "number", "type": "string", "memo": "string",
+		"metadata": "object", "direction": "string", "block_height": "number",
+	}
+	tokenTransferSchema = schema{
+		"name": "string", "symbol": "string", "token_id": "string", "decimals": "number",
+		"value": "string", "from": "string", "to": "string",
+	}
+	metadataSchemas = map[types.TransactionType]schema{
+		types.TxTransfer:            {"value": "string", "symbol": "string", "decimals": "number"},
+		types.TxNativeTokenTransfer: tokenTransferSchema,
+		types.TxTokenTransfer:       tokenTransferSchema,
+		types.TxCollectibleTransfer: {"name": "string", "contract": "string", "image_url": "string"},
+		types.TxTokenSwap:           {"input": "object", "output": "object"},
+		types.TxContractCall:        {"input": "string", "value": "string"},
+		types.TxAnyAction: {
+			"coin": "number", "title": "string", "key": "string", "token_id": "string",
+			"name": "string", "symbol": "string", "decimals": "number", "value": "string",
+		},
+	}
+	txStatuses   = []types.Status{types.StatusCompleted, types.StatusPending, types.StatusError}
+	txDirections = []types.Direction{types.DirectionOutgoing, types.DirectionIncoming, types.DirectionSelf}
+	amountRegexp = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// TestTxPage_Contract replays the recorded provider responses of each registered coin
+// and validates the transactions page against the shared shape
+func TestTxPage_Contract(t *testing.T) {
+	server := newReplayServer(t, "..")
+	defer server.Close()
+	initMockConfig(t, server.URL)
+
+	platforms := getAllHandlers()
+	handles := make([]string, 0, len(platforms))
+	for handle := range platforms {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+	for _, handle := range handles {
+		t.Run(handle, func(t *testing.T) {
+			txAPI, ok := platforms[handle].(blockatlas.TxAPI)
+			if !ok {
+				t.Skip("no transactions API")
+			}
+			address, ok := contractAddresses[handle]
+			if !ok {
+				t.Skip("no recorded address")
+			}
+			txs, err := txAPI.GetTxsByAddress(address)
+			assert.Nil(t, err)
+			assert.NotEmpty(t, txs)
+
+			page := blockatlas.NewTxPage(blockatlas.NewTxs(blockatlas.SetDirections(txs, address)), txAPI.Coin().Decimals)
+			body, err := json.Marshal(page)
+			assert.Nil(t, err)
+			for _, violation := range validateTxPage(body, txAPI.Coin().ID) {
+				t.Error(violation)
+			}
+		})
+	}
+}
+
+func TestValidateTxPage(t *testing.T) {
+	valid := `{"total":1,"status":true,"decimals":8,"docs":[{"id":"1","coin":0,"from":"a","to":"b","fee":"1","date":1,
+		"block":1,"status":"completed","sequence":0,"type":"transfer","memo":"","direction":"incoming","block_height":1,
+		"metadata":{"value":"1","symbol":"BTC","decimals":8}}]}`
+	assert.Empty(t, validateTxPage([]byte(valid), 0))
+
+	invalid := `{"total":1,"status":true,"decimals":8,"docs":[{"id":"","coin":2,"from":"a","to":"b","fee":"0.1","date":0,
+		"block":1,"status":"done","sequence":0,"type":"transfer","memo":"","direction":"incoming","block_height":1,
+		"metadata":{"value":1,"symbol":"BTC"}}]}`
+	assert.Equal(t, []string{
+		"docs[0]: id is empty",
+		"docs[0]: coin is 2, expected 0",
+		"docs[0]: date is not set",
+		`docs[0]: status "done" is unknown`,
+		`docs[0]: fee "0.1" is not an amount`,
+		"docs[0].metadata: decimals is missing",
+		"docs[0].metadata: value is number, expected string",
+	}, validateTxPage([]byte(invalid), 0))
+}
+
+// validateTxPage returns the violations of the TxPage schema
+func validateTxPage(body []byte, coinID uint) []string {
+	var page map[string]interface{}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return []string{err.Error()}
+	}
+	violations := validateSchema("page", page, txPageSchema)
+	docs, _ := page["docs"].([]interface{})
+	for i, doc := range docs {
+		path := fmt.Sprintf("docs[%d]", i)
+		tx, ok := doc.(map[string]interface{})
+		if !ok {
+			violations = append(violations, path+": not an object")
+			continue
+		}
+		violations = append(violations, validateTx(path, tx, coinID)...)
+	}
+	return violations
+}
+
+func validateTx(path string, tx map[string]interface{}, coinID uint) []string {
+	violations := validateSchema(path, tx, txSchema)
+	if len(violations) > 0 {
+		return violations
+	}
+	if tx["id"] == "" {
+		violations = append(violations, path+": id is empty")
+	}
+	if coin := uint(tx["coin"].(float64)); coin != coinID {
+		violations = append(violations, fmt.Sprintf("%s: coin is %d, expected %d", path, coin, coinID))
+	}
+	if tx["date"].(float64) <= 0 {
+		violations = append(violations, path+": date is not set")
+	}
+	if !containsStatus(types.Status(tx["status"].(string))) {
+		violations = append(violations, fmt.Sprintf("%s: status %q is unknown", path, tx["status"]))
+	}
+	if !containsDirection(types.Direction(tx["direction"].(string))) {
+		violations = append(violations, fmt.Sprintf("%s: direction %q is unknown", path, tx["direction"]))
+	}
+	if fee := tx["fee"].(string); !amountRegexp.MatchString(fee) {
+		violations = append(violations, fmt.Sprintf("%s: fee %q is not an amount", path, fee))
+	}
+	txType := types.TransactionType(tx["type"].(string))
+	metadataSchema, ok := metadataSchemas[txType]
+	if !ok {
+		return append(violations, fmt.Sprintf("%s: type %q is unknown", path, txType))
+	}
+	metadata := tx["metadata"].(map[string]interface{})
+	violations = append(violations, validateSchema(path+".metadata", metadata, metadataSchema)...)
+	if value, ok := metadata["value"].(string); ok && metadataSchema["value"] == "string" && !amountRegexp.MatchString(value) {
+		violations = append(violations, fmt.Sprintf("%s.metadata: value %q is not an amount", path, value))
+	}
+	return violations
+}
+
+func validateSchema(path string, object map[string]interface{}, s schema) []string {
+	fields := make([]string, 0, len(s))
+	for field := range s {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	violations := make([]string, 0)
+	for _, field := range fields {
+		value, ok := object[field]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("%s: %s is missing", path, field))
+			continue
+		}
+		if actual := jsonType(value); actual != s[field] {
+			violations = append(violations, fmt.Sprintf("%s: %s is %s, expected %s", path, field, actual, s[field]))
+		}
+	}
+	return violations
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func containsStatus(status types.Status) bool {
+	for _, s := range txStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsDirection(direction types.Direction) bool {
+	for _, d := range txDirections {
+		if d == direction {
+			return true
+		}
+	}
+	return false
+}
+
+// initMockConfig loads configmock.yml with the mock server at url
+func initMockConfig(t *testing.T, url string) {
+	raw, err := ioutil.ReadFile("../configmock.yml")
+	assert.Nil(t, err)
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(strings.ReplaceAll(string(raw), "http://localhost:3347", url)), 0600))
+	config.Init(path)
+	// Ethereum is served by Blockbook, configmock.yml predates it
+	config.Default.Ethereum.API = url + "/mock/eth-blockbook-api"
+}
+
+type replayEntry struct {
+	File    string `yaml:"file"`
+	MockURL string `yaml:"mockURL"`
+	Method  string `yaml:"method"`
+}
+
+// newReplayServer serves the recorded GET responses of mock/datafiles.yaml like the mockserver,
+// the query params of a recording must all match
+func newReplayServer(t *testing.T, root string) *httptest.Server {
+	raw, err := ioutil.ReadFile(filepath.Join(root, "mock", "datafiles.yaml"))
+	assert.Nil(t, err)
+	var entries []replayEntry
+	assert.Nil(t, yaml.Unmarshal(raw, &entries))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, e := range entries {
+			recorded, err := url.Parse(e.MockURL)
+			if err != nil || e.Method != http.MethodGet || r.Method != http.MethodGet || recorded.Path != r.URL.Path {
+				continue
+			}
+			if !matchQuery(recorded.Query(), r.URL.Query()) {
+				continue
+			}
+			http.ServeFile(w, r, filepath.Join(root, e.File))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+func matchQuery(recorded, actual url.Values) bool {
+	for key := range recorded {
+		if actual.Get(key) != recorded.Get(key) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,12 +1,13 @@
 package ethereum
 
 import (
+	"context"
+
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/blockatlas/platform/bitcoin/blockbook"
 	"github.com/trustwallet/blockatlas/platform/ethereum/bounce"
 	"github.com/trustwallet/blockatlas/platform/ethereum/opensea"
-	"github.com/trustwallet/golibs/client"
 	"github.com/trustwallet/golibs/coin"
-	"github.com/trustwallet/golibs/network/middleware"
 )
 
 type Platform struct {
@@ -18,7 +19,7 @@ type Platform struct {
 func InitWithBlockbook(coinType uint, blockbookApi string) *Platform {
 	return &Platform{
 		CoinIndex: coinType,
-		client:    &blockbook.Client{Request: client.InitClient(blockbookApi, middleware.SentryErrorHandler)},
+		client:    blockbook.InitClient(blockbookApi),
 	}
 }
 
@@ -34,6 +35,15 @@ func InitWithBounce(coinType uint, blockbookApi, collectionApi string) *Platform
 	return platform
 }
 
+// WithContext binds the requests of the Blockbook client, the collectibles are not bound
+func (p *Platform) WithContext(ctx context.Context) blockatlas.Platform {
+	bound := *p
+	if client, ok := p.client.(*blockbook.Client); ok {
+		bound.client = client.WithContext(ctx)
+	}
+	return &bound
+}
+
 func (p *Platform) Coin() coin.Coin {
 	return coin.Coins[p.CoinIndex]
 }
@@ -1,11 +1,18 @@
 package ethereum
 
-import "github.com/trustwallet/golibs/types"
+import (
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
 
 func (p *Platform) CurrentBlockNumber() (int64, error) {
 	return p.client.GetCurrentBlockNumber()
 }
 
+func (p *Platform) GetCurrentBlockHeight() (blockatlas.BlockHead, error) {
+	return p.client.GetCurrentBlockHeight()
+}
+
 func (p *Platform) GetBlockByNumber(num int64) (*types.Block, error) {
 	return p.client.GetBlockByNumber(num, p.CoinIndex)
 }
@@ -1,13 +1,22 @@
 package ethereum
 
-import "github.com/trustwallet/golibs/types"
+import (
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
 
 type EthereumClient interface {
 	GetTransactions(address string, coinIndex uint) (types.Txs, error)
+	GetNativeTxs(address string, coinIndex uint) (types.Txs, error)
 	GetTokenTxs(address, token string, coinIndex uint) (types.Txs, error)
+	GetTransactionsWithInternal(address string, coinIndex uint) (types.Txs, types.Txs, error)
 	GetTokenList(address string, coinIndex uint) ([]types.Token, error)
+	GetTokenBalance(address, token string) (types.Amount, error)
+	GetBalances(address string, coinIndex uint) (map[string]types.Amount, error)
 	GetCurrentBlockNumber() (int64, error)
+	GetCurrentBlockHeight() (blockatlas.BlockHead, error)
 	GetBlockByNumber(num int64, coinIndex uint) (*types.Block, error)
+	IsContract(address string) (bool, error)
 }
 
 type CollectibleClient interface {
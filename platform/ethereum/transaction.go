@@ -1,6 +1,7 @@
 package ethereum
 
 import (
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -8,14 +9,48 @@ func (p *Platform) GetTxsByAddress(address string) (types.Txs, error) {
 	return p.client.GetTransactions(address, p.CoinIndex)
 }
 
+// SupportsTxFilter reports whether Blockbook filters the transactions, it only skips transactions with token transfers
+func (p *Platform) SupportsTxFilter(filter blockatlas.TxFilter) bool {
+	return filter.AssetType == blockatlas.AssetTypeNative
+}
+
+func (p *Platform) GetTxsByAddressFiltered(address string, filter blockatlas.TxFilter) (types.Txs, error) {
+	if !p.SupportsTxFilter(filter) {
+		return p.GetTxsByAddress(address)
+	}
+	return p.client.GetNativeTxs(address, p.CoinIndex)
+}
+
+func (p *Platform) IsContract(address string) (bool, error) {
+	return p.client.IsContract(address)
+}
+
 func (p *Platform) GetTokenTxsByAddress(address string, token string) (types.Txs, error) {
 	return p.client.GetTokenTxs(address, token, p.CoinIndex)
 }
 
+func (p *Platform) GetTxsWithInternalByAddress(address string) (types.Txs, types.Txs, error) {
+	return p.client.GetTransactionsWithInternal(address, p.CoinIndex)
+}
+
 func (p *Platform) GetTokenListByAddress(address string) ([]types.Token, error) {
 	return p.client.GetTokenList(address, p.CoinIndex)
 }
 
+func (p *Platform) GetTokenBalance(address, token string) (types.Amount, error) {
+	return p.client.GetTokenBalance(address, token)
+}
+
+// GetBalances reads the block first, the balances cover at least its transactions
+func (p *Platform) GetBalances(address string) (map[string]types.Amount, int64, error) {
+	block, err := p.client.GetCurrentBlockNumber()
+	if err != nil {
+		return nil, 0, err
+	}
+	balances, err := p.client.GetBalances(address, p.CoinIndex)
+	return balances, block, err
+}
+
 func (p *Platform) GetTokenListIdsByAddress(address string) ([]string, error) {
 	assets, err := p.GetTokenListByAddress(address)
 	if err != nil {
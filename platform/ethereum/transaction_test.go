@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -16,8 +17,9 @@ var (
 		From: "A",
 		To:   "B",
 	}
-	page = types.Txs{tx}
-	c    Client
+	page     = types.Txs{tx}
+	nativeTx = types.Tx{ID: "2", Coin: 60, From: "A", To: "C", Meta: types.Transfer{Value: "1"}}
+	c        Client
 )
 
 func getTxClientMock() EthereumClient {
@@ -43,26 +45,74 @@ func TestPlatform_GetTxsByAddress(t *testing.T) {
 	assert.Equal(t, page, resp)
 }
 
+func TestPlatform_GetTxsByAddressFiltered(t *testing.T) {
+	p := Platform{
+		client: getTxClientMock(),
+	}
+	native := blockatlas.TxFilter{AssetType: blockatlas.AssetTypeNative}
+	assert.True(t, p.SupportsTxFilter(native))
+	assert.False(t, p.SupportsTxFilter(blockatlas.TxFilter{AssetType: blockatlas.AssetTypeNFT}))
+
+	resp, err := p.GetTxsByAddressFiltered("A", native)
+	assert.Nil(t, err)
+	assert.Equal(t, types.Txs{nativeTx}, resp)
+
+	resp, err = p.GetTxsByAddressFiltered("A", blockatlas.TxFilter{AssetType: blockatlas.AssetTypeFungible})
+	assert.Nil(t, err)
+	assert.Equal(t, page, resp)
+}
+
+func TestPlatform_IsContract(t *testing.T) {
+	p := Platform{client: getTxClientMock()}
+	isContract, err := p.IsContract("0xcontract")
+	assert.Nil(t, err)
+	assert.True(t, isContract)
+}
+
 func (c Client) GetTransactions(address string, coinIndex uint) (types.Txs, error) {
 	txs := make(types.Txs, 0)
 	txs = append(txs, tx)
 	return txs, nil
 }
 
+func (c Client) GetNativeTxs(address string, coinIndex uint) (types.Txs, error) {
+	return types.Txs{nativeTx}, nil
+}
+
 func (c Client) GetTokenTxs(address, token string, coinIndex uint) (types.Txs, error) {
 	txs := make(types.Txs, 0)
 	txs = append(txs, tx)
 	return txs, nil
 }
 
+func (c Client) GetTransactionsWithInternal(address string, coinIndex uint) (types.Txs, types.Txs, error) {
+	return types.Txs{tx}, types.Txs{}, nil
+}
+
 func (c Client) GetTokenList(address string, coinIndex uint) ([]types.Token, error) {
 	return []types.Token{}, nil
 }
 
+func (c Client) GetTokenBalance(address, token string) (types.Amount, error) {
+	return "0", nil
+}
+
+func (c Client) GetBalances(address string, coinIndex uint) (map[string]types.Amount, error) {
+	return map[string]types.Amount{}, nil
+}
+
 func (c Client) GetCurrentBlockNumber() (int64, error) {
 	return 0, nil
 }
 
+func (c Client) GetCurrentBlockHeight() (blockatlas.BlockHead, error) {
+	return blockatlas.BlockHead{}, nil
+}
+
 func (c Client) GetBlockByNumber(num int64, coinIndex uint) (*types.Block, error) {
 	return nil, nil
 }
+
+func (c Client) IsContract(address string) (bool, error) {
+	return address == "0xcontract", nil
+}
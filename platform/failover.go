@@ -0,0 +1,291 @@
+package platform
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/internal/metrics"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type (
+	// Failover serves transaction lookups from several providers of the same coin.
+	// Providers are tried from the healthiest on source errors, returning the first success.
+	Failover struct {
+		providers []*provider
+		// now is the clock the health windows of the providers decay with
+		now func() time.Time
+		// ctx is the request the lookups are bound to, see WithContext
+		ctx context.Context
+	}
+
+	// FailoverUtxo is a Failover of providers also serving XPUB lookups
+	FailoverUtxo struct {
+		*Failover
+	}
+
+	provider struct {
+		name   string
+		api    blockatlas.TxAPI
+		health *blockatlas.HealthWindow
+	}
+)
+
+// NewFailover returns the failover of the given providers, the first one being the primary.
+// Providers without transaction lookups are ignored.
+func NewFailover(platforms ...blockatlas.Platform) blockatlas.Platform {
+	f := &Failover{now: time.Now}
+	utxo := true
+	for i, p := range platforms {
+		api, ok := p.(blockatlas.TxAPI)
+		if !ok {
+			continue
+		}
+		if _, ok := p.(blockatlas.TxUtxoAPI); !ok {
+			utxo = false
+		}
+		f.providers = append(f.providers, &provider{name: strconv.Itoa(i), api: api, health: &blockatlas.HealthWindow{}})
+	}
+	if utxo {
+		return FailoverUtxo{Failover: f}
+	}
+	return f
+}
+
+// WithFailover returns the failover of a registered platform if secondary APIs are configured
+func WithFailover(p blockatlas.Platform) blockatlas.Platform {
+	handle := p.Coin().Handle
+	if Platforms[handle] != p {
+		return p
+	}
+	if failover, ok := FailoverPlatforms[handle]; ok {
+		return failover
+	}
+	return p
+}
+
+// WithContext binds the requests of the providers, the health windows are shared with f.
+// The provider serving the lookups is named in the context, see blockatlas.TrackProvider
+func (f *Failover) WithContext(ctx context.Context) blockatlas.Platform {
+	return f.withContext(ctx)
+}
+
+func (f *Failover) withContext(ctx context.Context) *Failover {
+	bound := &Failover{now: f.now, ctx: ctx, providers: make([]*provider, 0, len(f.providers))}
+	for _, p := range f.providers {
+		api, ok := blockatlas.BindContext(p.api, ctx).(blockatlas.TxAPI)
+		if !ok {
+			api = p.api
+		}
+		bound.providers = append(bound.providers, &provider{name: p.name, api: api, health: p.health})
+	}
+	return bound
+}
+
+func (f FailoverUtxo) WithContext(ctx context.Context) blockatlas.Platform {
+	return FailoverUtxo{Failover: f.Failover.withContext(ctx)}
+}
+
+func (f *Failover) Coin() coin.Coin {
+	return f.providers[0].api.Coin()
+}
+
+func (f *Failover) GetTxsByAddress(address string) (types.Txs, error) {
+	return f.lookup(func(api blockatlas.TxAPI) (types.Txs, error) {
+		return api.GetTxsByAddress(address)
+	})
+}
+
+// SupportsTxFilter reports whether any of the providers applies the filter upstream
+func (f *Failover) SupportsTxFilter(filter blockatlas.TxFilter) bool {
+	for _, p := range f.providers {
+		if api, ok := p.api.(blockatlas.TxFilterAPI); ok && api.SupportsTxFilter(filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTxsByAddressFiltered pushes the filter down to the providers supporting it,
+// the others serve all the transactions to be filtered locally
+func (f *Failover) GetTxsByAddressFiltered(address string, filter blockatlas.TxFilter) (types.Txs, error) {
+	return f.lookup(func(api blockatlas.TxAPI) (types.Txs, error) {
+		if filterAPI, ok := api.(blockatlas.TxFilterAPI); ok && filterAPI.SupportsTxFilter(filter) {
+			return filterAPI.GetTxsByAddressFiltered(address, filter)
+		}
+		return api.GetTxsByAddress(address)
+	})
+}
+
+// GetTxsByAddressLimit asks the providers for the latest transactions,
+// the ones not limiting their page natively serve a full page
+func (f *Failover) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	return f.lookup(func(api blockatlas.TxAPI) (types.Txs, error) {
+		if limitAPI, ok := api.(blockatlas.TxLimitAPI); ok {
+			return limitAPI.GetTxsByAddressLimit(address, limit)
+		}
+		return api.GetTxsByAddress(address)
+	})
+}
+
+// GetTxsWithInternalByAddress asks the providers for the transactions with their internal transfers,
+// the ones without internal transactions serve the transactions only
+func (f *Failover) GetTxsWithInternalByAddress(address string) (types.Txs, types.Txs, error) {
+	var internal types.Txs
+	txs, err := f.lookup(func(api blockatlas.TxAPI) (types.Txs, error) {
+		internalAPI, ok := api.(blockatlas.InternalTxAPI)
+		if !ok {
+			internal = nil
+			return api.GetTxsByAddress(address)
+		}
+		var (
+			txs types.Txs
+			err error
+		)
+		txs, internal, err = internalAPI.GetTxsWithInternalByAddress(address)
+		return txs, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return txs, internal, nil
+}
+
+// IsContract asks the healthiest provider telling contracts apart
+func (f *Failover) IsContract(address string) (bool, error) {
+	err := blockatlas.ErrNotSupported
+	for _, p := range f.byHealth() {
+		api, ok := p.api.(blockatlas.ContractAPI)
+		if !ok {
+			continue
+		}
+		var isContract bool
+		if isContract, err = api.IsContract(address); err == nil {
+			return isContract, nil
+		}
+	}
+	return false, err
+}
+
+// CurrentBlockNumber returns the chain head of the healthiest provider serving it
+func (f *Failover) CurrentBlockNumber() (int64, error) {
+	err := blockatlas.ErrNotSupported
+	for _, p := range f.byHealth() {
+		api, ok := p.api.(blockatlas.BlockAPI)
+		if !ok {
+			continue
+		}
+		var num int64
+		if num, err = api.CurrentBlockNumber(); err == nil {
+			return num, nil
+		}
+	}
+	return 0, err
+}
+
+func (f *Failover) GetBlockByNumber(num int64) (*types.Block, error) {
+	err := blockatlas.ErrNotSupported
+	for _, p := range f.byHealth() {
+		api, ok := p.api.(blockatlas.BlockAPI)
+		if !ok {
+			continue
+		}
+		var block *types.Block
+		if block, err = api.GetBlockByNumber(num); err == nil {
+			return block, nil
+		}
+	}
+	return nil, err
+}
+
+func (f FailoverUtxo) GetTxsByXpub(xpub string) (types.Txs, error) {
+	return f.lookup(func(api blockatlas.TxAPI) (types.Txs, error) {
+		return api.(blockatlas.TxUtxoAPI).GetTxsByXpub(xpub)
+	})
+}
+
+// GetAddressesFromXpub returns the derived addresses of the healthiest provider serving them
+func (f FailoverUtxo) GetAddressesFromXpub(xpub string) ([]blockatlas.XpubAddress, error) {
+	err := blockatlas.ErrNotSupported
+	for _, p := range f.byHealth() {
+		api, ok := p.api.(blockatlas.XpubAddressesAPI)
+		if !ok {
+			continue
+		}
+		var addresses []blockatlas.XpubAddress
+		if addresses, err = api.GetAddressesFromXpub(xpub); err == nil {
+			return addresses, nil
+		}
+	}
+	return nil, err
+}
+
+func (f *Failover) lookup(get func(api blockatlas.TxAPI) (types.Txs, error)) (types.Txs, error) {
+	var err error
+	for _, p := range f.byHealth() {
+		var txs types.Txs
+		txs, err = get(p.api)
+		if f.ctx != nil && f.ctx.Err() != nil {
+			// the request is gone, the provider is not to blame
+			return nil, err
+		}
+		failed := blockatlas.IsSourceConnError(err)
+		f.observe(p, failed)
+		if !failed {
+			if f.ctx != nil && err == nil {
+				blockatlas.SetServedBy(f.ctx, p.name)
+			}
+			return txs, err
+		}
+		f.logger().WithFields(log.Fields{
+			"coin":     f.Coin().Handle,
+			"provider": p.name,
+			"reason":   blockatlas.GetSourceErrorReason(err),
+			"error":    err,
+		}).Warn("Provider failed, trying next")
+	}
+	return nil, blockatlas.NewSourceError(err)
+}
+
+// logger returns the log entry of the request the providers are bound to, see WithContext
+func (f *Failover) logger() *log.Entry {
+	if f.ctx == nil {
+		return log.NewEntry(log.StandardLogger())
+	}
+	return blockatlas.Logger(f.ctx)
+}
+
+// Health returns the recent failure rates of the providers in the configured order
+func (f *Failover) Health() blockatlas.CoinHealth {
+	providers := make([]blockatlas.ProviderHealth, 0, len(f.providers))
+	for _, p := range f.providers {
+		providers = append(providers, p.health.ProviderHealthAt(p.name, f.now()))
+	}
+	return blockatlas.NewCoinHealth(providers...)
+}
+
+// byHealth orders the providers by failure rate, keeping the configured order on ties.
+// Failure rates decay over time, a provider skipped after failures is tried first again once recovered.
+func (f *Failover) byHealth() []*provider {
+	result := make([]*provider, len(f.providers))
+	copy(result, f.providers)
+	now := f.now()
+	rates := make(map[*provider]float64, len(result))
+	for _, p := range result {
+		rates[p] = p.health.FailureRateAt(now)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return rates[result[i]] < rates[result[j]]
+	})
+	return result
+}
+
+func (f *Failover) observe(p *provider, failed bool) {
+	metrics.ObserveProviderRequest(f.Coin().Handle, p.name, failed)
+	p.health.ObserveAt(failed, f.now())
+}
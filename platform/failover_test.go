@@ -0,0 +1,250 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type txAPIMock struct {
+	id    string
+	err   error
+	calls int
+}
+
+func (m *txAPIMock) Coin() coin.Coin {
+	return coin.Bitcoin()
+}
+
+func (m *txAPIMock) GetTxsByAddress(address string) (types.Txs, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return types.Txs{{ID: m.id}}, nil
+}
+
+func TestFailover_GetTxsByAddress(t *testing.T) {
+	primary := &txAPIMock{id: "primary", err: blockatlas.ErrSourceConn}
+	secondary := &txAPIMock{id: "secondary"}
+	failover := NewFailover(primary, secondary).(*Failover)
+	now := time.Unix(1600000000, 0)
+	failover.now = func() time.Time { return now }
+
+	txs, err := failover.GetTxsByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", txs[0].ID)
+	assert.Equal(t, 1, primary.calls)
+
+	// the healthy provider is preferred for the next lookups
+	txs, err = failover.GetTxsByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", txs[0].ID)
+	assert.Equal(t, 1, primary.calls)
+	assert.Equal(t, blockatlas.ProviderStatusOK, failover.Health().Providers[0].Status)
+
+	// the failure decays, the recovered primary is preferred again
+	primary.err = nil
+	now = now.Add(blockatlas.HealthDecayInterval)
+	txs, err = failover.GetTxsByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", txs[0].ID)
+	assert.Equal(t, 2, primary.calls)
+}
+
+func TestFailover_HealthRecovers(t *testing.T) {
+	primary := &txAPIMock{id: "primary", err: blockatlas.ErrSourceConn}
+	failover := NewFailover(primary, &txAPIMock{id: "secondary"}).(*Failover)
+	now := time.Unix(1600000000, 0)
+	failover.now = func() time.Time { return now }
+	for i := 0; i < 10; i++ {
+		failover.providers[0].health.ObserveAt(true, now)
+	}
+	assert.Equal(t, blockatlas.ProviderStatusDown, failover.Health().Providers[0].Status)
+
+	now = now.Add(10 * blockatlas.HealthDecayInterval)
+	assert.Equal(t, blockatlas.ProviderStatusOK, failover.Health().Providers[0].Status)
+}
+
+// filterAPIMock filters native transactions upstream
+type filterAPIMock struct {
+	txAPIMock
+}
+
+func (m *filterAPIMock) SupportsTxFilter(filter blockatlas.TxFilter) bool {
+	return filter.AssetType == blockatlas.AssetTypeNative
+}
+
+func (m *filterAPIMock) GetTxsByAddressFiltered(address string, filter blockatlas.TxFilter) (types.Txs, error) {
+	m.calls++
+	return types.Txs{{ID: m.id + "-filtered"}}, nil
+}
+
+func TestFailover_GetTxsByAddressFiltered(t *testing.T) {
+	native := blockatlas.TxFilter{AssetType: blockatlas.AssetTypeNative}
+	plain := &txAPIMock{id: "plain", err: blockatlas.ErrSourceConn}
+	filtering := &filterAPIMock{txAPIMock{id: "filtering"}}
+	failover := NewFailover(plain, filtering).(blockatlas.TxFilterAPI)
+
+	assert.True(t, failover.SupportsTxFilter(native))
+	assert.False(t, failover.SupportsTxFilter(blockatlas.TxFilter{AssetType: blockatlas.AssetTypeNFT}))
+
+	txs, err := failover.GetTxsByAddressFiltered("address", native)
+	assert.Nil(t, err)
+	assert.Equal(t, "filtering-filtered", txs[0].ID)
+	assert.Equal(t, 1, plain.calls)
+
+	txs, err = failover.GetTxsByAddressFiltered("address", blockatlas.TxFilter{AssetType: blockatlas.AssetTypeNFT})
+	assert.Nil(t, err)
+	assert.Equal(t, "filtering", txs[0].ID)
+}
+
+// limitAPIMock limits its page natively
+type limitAPIMock struct {
+	txAPIMock
+}
+
+func (m *limitAPIMock) GetTxsByAddressLimit(address string, limit int) (types.Txs, error) {
+	m.calls++
+	return types.Txs{{ID: m.id + "-limited"}}, nil
+}
+
+func TestFailover_GetTxsByAddressLimit(t *testing.T) {
+	plain := &txAPIMock{id: "plain"}
+	limiting := &limitAPIMock{txAPIMock{id: "limiting"}}
+
+	txs, err := NewFailover(limiting, plain).(blockatlas.TxLimitAPI).GetTxsByAddressLimit("address", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "limiting-limited", txs[0].ID)
+
+	txs, err = NewFailover(plain, limiting).(blockatlas.TxLimitAPI).GetTxsByAddressLimit("address", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", txs[0].ID)
+}
+
+// internalAPIMock serves internal transfers along with its page
+type internalAPIMock struct {
+	txAPIMock
+}
+
+func (m *internalAPIMock) GetTxsWithInternalByAddress(address string) (types.Txs, types.Txs, error) {
+	txs, err := m.GetTxsByAddress(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txs, types.Txs{{ID: m.id + "-internal"}}, nil
+}
+
+func TestFailover_GetTxsWithInternalByAddress(t *testing.T) {
+	broken := &internalAPIMock{txAPIMock{id: "broken", err: blockatlas.ErrSourceConn}}
+	internal := &internalAPIMock{txAPIMock{id: "internal"}}
+	plain := &txAPIMock{id: "plain"}
+
+	txs, internalTxs, err := NewFailover(broken, internal).(blockatlas.InternalTxAPI).GetTxsWithInternalByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "internal", txs[0].ID)
+	assert.Equal(t, "internal-internal", internalTxs[0].ID)
+
+	txs, internalTxs, err = NewFailover(plain, internal).(blockatlas.InternalTxAPI).GetTxsWithInternalByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", txs[0].ID)
+	assert.Empty(t, internalTxs)
+}
+
+func TestFailover_Health(t *testing.T) {
+	primary := &txAPIMock{id: "primary", err: blockatlas.ErrSourceConn}
+	secondary := &txAPIMock{id: "secondary"}
+	failover := NewFailover(primary, secondary).(*Failover)
+	for i := 0; i < 5; i++ {
+		failover.providers[0].health.Observe(true)
+		failover.providers[1].health.Observe(false)
+	}
+
+	health := failover.Health()
+	assert.Equal(t, blockatlas.ProviderStatusDegraded, health.Status)
+	assert.Equal(t, "0", health.Providers[0].Provider)
+	assert.Equal(t, blockatlas.ProviderStatusDown, health.Providers[0].Status)
+	assert.Equal(t, blockatlas.ProviderStatusOK, health.Providers[1].Status)
+
+	// the down primary is tried last
+	_, err := failover.GetTxsByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, primary.calls)
+}
+
+func TestFailover_RequestError(t *testing.T) {
+	primary := &txAPIMock{id: "primary", err: blockatlas.ErrInvalidAddr}
+	secondary := &txAPIMock{id: "secondary"}
+	failover := NewFailover(primary, secondary).(blockatlas.TxAPI)
+
+	_, err := failover.GetTxsByAddress("address")
+	assert.Equal(t, blockatlas.ErrInvalidAddr, err)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestFailover_AllFailed(t *testing.T) {
+	failover := NewFailover(
+		&txAPIMock{err: blockatlas.ErrSourceConn},
+		&txAPIMock{err: blockatlas.ErrSourceConn},
+	).(blockatlas.TxAPI)
+
+	_, err := failover.GetTxsByAddress("address")
+	assert.Equal(t, blockatlas.ErrSourceConn, err)
+
+	_, ok := failover.(blockatlas.TxUtxoAPI)
+	assert.False(t, ok)
+	assert.False(t, blockatlas.IsSourceConnError(errors.New("invalid response")))
+}
+
+type contextTxAPIMock struct {
+	txAPIMock
+	ctx context.Context
+}
+
+func (m *contextTxAPIMock) WithContext(ctx context.Context) blockatlas.Platform {
+	return &contextTxAPIMock{txAPIMock: txAPIMock{id: m.id, err: m.err}, ctx: ctx}
+}
+
+func TestFailover_WithContext(t *testing.T) {
+	primary := &contextTxAPIMock{txAPIMock: txAPIMock{id: "primary", err: blockatlas.ErrSourceConn}}
+	secondary := &txAPIMock{id: "secondary"}
+	failover := NewFailover(primary, secondary).(*Failover)
+
+	ctx := blockatlas.TrackProvider(context.Background())
+	bound := blockatlas.BindContext(failover, ctx).(*Failover)
+	assert.Equal(t, ctx, bound.providers[0].api.(*contextTxAPIMock).ctx)
+	assert.Nil(t, primary.ctx)
+	assert.Equal(t, secondary, bound.providers[1].api)
+
+	// the failures seen by the bound failover are shared with the original one
+	txs, err := bound.GetTxsByAddress("address")
+	assert.Nil(t, err)
+	assert.Equal(t, "secondary", txs[0].ID)
+	provider, ok := blockatlas.ServedBy(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "1", provider)
+	assert.Same(t, failover.providers[0].health, bound.providers[0].health)
+	assert.Equal(t, failover.Health(), bound.Health())
+	assert.Equal(t, 0, primary.calls)
+}
+
+func TestFailover_CanceledRequest(t *testing.T) {
+	primary := &txAPIMock{id: "primary", err: blockatlas.ErrSourceConn}
+	failover := NewFailover(primary, &txAPIMock{id: "secondary"}).(*Failover)
+
+	ctx, cancel := context.WithCancel(blockatlas.TrackProvider(context.Background()))
+	cancel()
+	_, err := failover.WithContext(ctx).(blockatlas.TxAPI).GetTxsByAddress("address")
+	assert.Equal(t, blockatlas.ErrSourceConn, err)
+
+	// the provider is not blamed for the request gone
+	assert.Equal(t, 0.0, failover.providers[0].health.FailureRateAt(time.Now()))
+	_, ok := blockatlas.ServedBy(ctx)
+	assert.False(t, ok)
+}
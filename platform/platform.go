@@ -1,6 +1,7 @@
 package platform
 
 import (
+	log "github.com/sirupsen/logrus"
 	"github.com/trustwallet/blockatlas/config"
 	"github.com/trustwallet/blockatlas/platform/oasis"
 
@@ -108,3 +109,65 @@ func getCollectionsHandlers() blockatlas.CollectionsAPIs {
 		coin.SMARTCHAIN: ethereum.InitWithBounce(coin.SMARTCHAIN, config.Default.Smartchain.API, config.Default.Smartchain.CollectionsAPI),
 	}
 }
+
+// getTestnetHandlers initializes the test network platforms of the given coin handles,
+// only Blockbook based coins are supported
+func getTestnetHandlers(apis map[string]string) blockatlas.Platforms {
+	platforms := make(blockatlas.Platforms)
+	for handle, api := range apis {
+		platform, ok := InitBlockbookPlatform(handle, api)
+		if !ok {
+			log.WithFields(log.Fields{"handle": handle}).Warn("Testnet is not supported")
+			continue
+		}
+		platforms[handle] = platform
+	}
+	return platforms
+}
+
+// getFailoverHandlers wraps the transaction lookups of the given platforms with their secondary APIs,
+// only Blockbook based coins are supported
+func getFailoverHandlers(apis map[string][]string, platforms map[string]blockatlas.Platform) blockatlas.Platforms {
+	failovers := make(blockatlas.Platforms)
+	for handle, secondaryAPIs := range apis {
+		primary, ok := platforms[handle]
+		if !ok {
+			continue
+		}
+		providers := []blockatlas.Platform{primary}
+		for _, api := range secondaryAPIs {
+			secondary, ok := InitBlockbookPlatform(handle, api)
+			if !ok {
+				log.WithFields(log.Fields{"handle": handle}).Warn("Failover is not supported")
+				break
+			}
+			providers = append(providers, secondary)
+		}
+		if len(providers) > 1 {
+			failovers[handle] = NewFailover(providers...)
+		}
+	}
+	return failovers
+}
+
+// InitBlockbookPlatform initializes the platform of a Blockbook based coin with another API
+func InitBlockbookPlatform(handle, api string) (blockatlas.Platform, bool) {
+	switch handle {
+	case coin.Bitcoin().Handle:
+		return bitcoin.Init(coin.BITCOIN, api), true
+	case coin.Litecoin().Handle:
+		return bitcoin.Init(coin.LITECOIN, api), true
+	case coin.Bitcoincash().Handle:
+		return bitcoin.Init(coin.BITCOINCASH, api), true
+	case coin.Doge().Handle:
+		return bitcoin.Init(coin.DOGE, api), true
+	case coin.Dash().Handle:
+		return bitcoin.Init(coin.DASH, api), true
+	case coin.Ethereum().Handle:
+		return ethereum.InitWithBlockbook(coin.ETHEREUM, api), true
+	case coin.Smartchain().Handle:
+		return ethereum.InitWithBlockbook(coin.SMARTCHAIN, api), true
+	default:
+		return nil, false
+	}
+}
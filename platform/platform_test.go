@@ -0,0 +1,22 @@
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/golibs/coin"
+)
+
+func TestGetTestnetHandlers(t *testing.T) {
+	platforms := getTestnetHandlers(map[string]string{
+		coin.Bitcoin().Handle:  "https://tbtc.example.com",
+		coin.Ethereum().Handle: "https://goerli.example.com",
+		coin.Tezos().Handle:    "https://tezos.example.com",
+	})
+	assert.Len(t, platforms, 2)
+	assert.Equal(t, coin.Bitcoin(), platforms[coin.Bitcoin().Handle].Coin())
+	assert.Equal(t, coin.Ethereum(), platforms[coin.Ethereum().Handle].Coin())
+	assert.NotContains(t, platforms, coin.Tezos().Handle)
+
+	assert.Empty(t, getTestnetHandlers(nil))
+}
@@ -1,8 +1,12 @@
 package platform
 
 import (
+	"net/http"
+
 	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/config"
 	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/client"
 )
 
 var (
@@ -15,11 +19,26 @@ var (
 	// TokensAPIs contain platforms with token services
 	TokensAPIs map[uint]blockatlas.TokensAPI
 
+	// BalanceAPIs contain platforms providing the current balances of an address
+	BalanceAPIs map[uint]blockatlas.BalanceAPI
+
 	// StakeAPIs contain platforms with staking services
 	StakeAPIs map[string]blockatlas.StakeAPI
 
 	// CollectionsAPIs contain platforms which collections services
 	CollectionsAPIs blockatlas.CollectionsAPIs
+
+	// TestnetPlatforms contains the test network platforms by handle
+	TestnetPlatforms blockatlas.Platforms
+
+	// FailoverPlatforms contains the transaction lookups with secondary APIs by handle
+	FailoverPlatforms blockatlas.Platforms
+
+	// TrustedTokens contains the tokens bypassing the memo and token filters
+	TrustedTokens blockatlas.TrustedTokens
+
+	// SpamTokens contains the known spam tokens, reloadable at runtime
+	SpamTokens *blockatlas.SpamTokens
 )
 
 func getActivePlatforms(handles []string) []blockatlas.Platform {
@@ -52,6 +71,7 @@ func Init(platformHandles []string) {
 	Platforms = make(map[string]blockatlas.Platform)
 	BlockAPIs = make(map[string]blockatlas.BlockAPI)
 	TokensAPIs = make(map[uint]blockatlas.TokensAPI)
+	BalanceAPIs = make(map[uint]blockatlas.BalanceAPI)
 	StakeAPIs = make(map[string]blockatlas.StakeAPI)
 
 	for _, platform := range platformList {
@@ -72,10 +92,47 @@ func Init(platformHandles []string) {
 		if tokenAPI, ok := platform.(blockatlas.TokensAPI); ok {
 			TokensAPIs[platform.Coin().ID] = tokenAPI
 		}
+		if balanceAPI, ok := platform.(blockatlas.BalanceAPI); ok {
+			BalanceAPIs[platform.Coin().ID] = balanceAPI
+		}
 		if stakeAPI, ok := platform.(blockatlas.StakeAPI); ok {
 			StakeAPIs[handle] = stakeAPI
 		}
 	}
 
 	CollectionsAPIs = getCollectionsHandlers()
+	TestnetPlatforms = getTestnetHandlers(config.Default.Testnet)
+	FailoverPlatforms = getFailoverHandlers(config.Default.Failover, Platforms)
+	TrustedTokens = blockatlas.NewTrustedTokens(config.Default.TrustedTokens)
+	SpamTokens = blockatlas.NewSpamTokens(config.Default.Spam.Tokens)
+	limitResponseSize(config.Default.MaxResponseSize)
+}
+
+// limitResponseSize bounds the responses of the providers, platforms share the golibs default HTTP client
+func limitResponseSize(maxSize int64) {
+	if maxSize <= 0 {
+		return
+	}
+	transport := client.DefaultClient.Transport
+	if limited, ok := transport.(blockatlas.LimitedTransport); ok {
+		transport = limited.RoundTripper
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.DefaultClient.Transport = blockatlas.LimitedTransport{RoundTripper: transport, MaxSize: maxSize}
+}
+
+// RecordResponses records the responses of the providers to dir, see blockatlas.Recorder.
+// Only the requests bound to the context of a recorded request are recorded
+func RecordResponses(dir string) *blockatlas.Recorder {
+	transport := client.DefaultClient.Transport
+	if recording, ok := transport.(blockatlas.RecordingTransport); ok {
+		transport = recording.RoundTripper
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.DefaultClient.Transport = blockatlas.RecordingTransport{RoundTripper: transport}
+	return &blockatlas.Recorder{Dir: dir}
 }
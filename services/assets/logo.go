@@ -0,0 +1,28 @@
+package assets
+
+import (
+	"time"
+
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/client"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/network/middleware"
+)
+
+// Registry serves the logos of the assets repository
+type Registry struct{}
+
+func (Registry) GetCoinLogo(c coin.Coin) string {
+	return URL + c.Handle + "/info/logo.png"
+}
+
+// GetTokenLogos returns the logos of the tokens listed for the coin, cached for an hour
+func (Registry) GetTokenLogos(c coin.Coin) (blockatlas.TokenLogos, error) {
+	var list TokenList
+	request := client.InitClient(URL+c.Handle, middleware.SentryErrorHandler)
+	err := request.GetWithCache(&list, "tokenlist.json", nil, time.Hour*1)
+	if err != nil {
+		return nil, err
+	}
+	return list.ToLogos(), nil
+}
@@ -1,5 +1,11 @@
 package assets
 
+import (
+	"strings"
+
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
 type (
 	AssetValidators []AssetValidator
 
@@ -26,8 +32,26 @@ type (
 	StakingInfo struct {
 		MinDelegation float64 `json:"minDelegation"`
 	}
+
+	// TokenList is the list of the tokens of a coin in the assets repository
+	TokenList struct {
+		Tokens []ListedToken `json:"tokens"`
+	}
+
+	ListedToken struct {
+		Address string `json:"address"`
+		LogoURI string `json:"logoURI"`
+	}
 )
 
+func (l TokenList) ToLogos() blockatlas.TokenLogos {
+	logos := make(blockatlas.TokenLogos, len(l.Tokens))
+	for _, token := range l.Tokens {
+		logos[strings.ToLower(token.Address)] = token.LogoURI
+	}
+	return logos
+}
+
 func (av AssetValidators) ToMap() AssetValidatorMap {
 	validators := make(AssetValidatorMap)
 	for _, v := range av {
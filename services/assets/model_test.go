@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 )
 
 func TestAssetValidators_toMap(t *testing.T) {
@@ -53,3 +54,14 @@ func TestAssetValidators_activeValidators(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenList_ToLogos(t *testing.T) {
+	list := TokenList{Tokens: []ListedToken{
+		{Address: "0xdAC17F958D2ee523a2206206994597C13D831ec7", LogoURI: "https://assets/usdt.png"},
+		{Address: "BUSD-BD1", LogoURI: "https://assets/busd.png"},
+	}}
+	assert.Equal(t, blockatlas.TokenLogos{
+		"0xdac17f958d2ee523a2206206994597c13d831ec7": "https://assets/usdt.png",
+		"busd-bd1": "https://assets/busd.png",
+	}, list.ToLogos())
+}
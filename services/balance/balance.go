@@ -0,0 +1,151 @@
+package balance
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/internal"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
+
+const Balance = "Balance"
+
+type (
+	// Store keeps the balances of the subscribed addresses, see db.Instance
+	Store interface {
+		GetSubscriptions(addresses []string) ([]models.Subscription, error)
+		GetConsumerSequence(consumer string, coin uint) (uint64, error)
+		SetConsumerSequence(consumer string, coin uint, sequence uint64) error
+		AddBalanceDeltas(deltas []models.BalanceDelta) error
+		RewindBalances(coin uint, fromBlock uint64) error
+		CheckpointBalances(coin uint, block uint64) error
+	}
+
+	// Consumer applies the net effect of the observed transactions to the balances of the subscribed addresses.
+	// A message with blocks already applied for its coin, e.g. replayed after a reorg, rewinds the balances
+	// of the coin to its lowest block first. Deltas older than CheckpointDepth blocks are folded into the
+	// balance checkpoints, deeper reorgs can't be rewound. Messages must be processed in order, by a single worker.
+	Consumer struct {
+		Store           Store
+		CheckpointDepth uint64
+		Tag             string
+	}
+)
+
+func (c Consumer) Callback(msg amqp.Delivery) error {
+	ctx := internal.DeliveryContext(msg)
+	var txs types.Txs
+	if err := json.Unmarshal(msg.Body, &txs); err != nil {
+		blockatlas.Logger(ctx).WithFields(log.Fields{"service": Balance, "body": string(msg.Body), "error": err}).Error("Unable to unmarshal MQ Message")
+		return nil
+	}
+	return c.Apply(ctx, txs)
+}
+
+// Apply updates the balances with the transactions of a single coin, logging with the correlation ID of ctx.
+// Every step is idempotent, a failed message can be applied again.
+func (c Consumer) Apply(ctx context.Context, txs types.Txs) error {
+	if len(txs) == 0 {
+		return nil
+	}
+	coin, sequence := txs[0].Coin, internal.TxsBlock(txs)
+
+	last, err := c.Store.GetConsumerSequence(c.Tag, coin)
+	if err != nil {
+		return err
+	}
+	if from := lowestBlock(txs); last > 0 && from <= last {
+		blockatlas.Logger(ctx).WithFields(log.Fields{"service": Balance, "coin": coin, "from": from, "last": last}).Warn("Rewinding balances")
+		if err := c.Store.RewindBalances(coin, from); err != nil {
+			return err
+		}
+	}
+
+	deltas, err := c.getDeltas(coin, txs)
+	if err != nil {
+		return err
+	}
+	if err := c.Store.AddBalanceDeltas(deltas); err != nil {
+		return err
+	}
+	if sequence > c.CheckpointDepth {
+		if err := c.Store.CheckpointBalances(coin, sequence-c.CheckpointDepth); err != nil {
+			return err
+		}
+	}
+	blockatlas.Logger(ctx).WithFields(log.Fields{"service": Balance, "coin": coin, "sequence": sequence, "deltas": len(deltas)}).Info("Balances updated")
+	return c.Store.SetConsumerSequence(c.Tag, coin, sequence)
+}
+
+// getDeltas returns the balance deltas of the subscribed addresses of the transactions
+func (c Consumer) getDeltas(coin uint, txs types.Txs) ([]models.BalanceDelta, error) {
+	ids := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, tx := range txs {
+		for _, address := range tx.GetAddresses() {
+			id := types.GetAddressID(strconv.Itoa(int(coin)), address)
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	subscriptions, err := c.Store.GetSubscriptions(ids)
+	if err != nil {
+		return nil, err
+	}
+	subscribed := make(map[string]bool)
+	for _, subscription := range subscriptions {
+		subscribed[subscription.Address] = true
+	}
+
+	deltas := make([]models.BalanceDelta, 0)
+	for _, tx := range txs {
+		addresses := make(map[string]bool)
+		for _, address := range tx.GetAddresses() {
+			addresses[address] = true
+		}
+		for address := range addresses {
+			id := types.GetAddressID(strconv.Itoa(int(coin)), address)
+			if !subscribed[id] {
+				continue
+			}
+			for assetID, amount := range GetBalanceDeltas(tx, address) {
+				deltas = append(deltas, models.BalanceDelta{
+					Address: id,
+					Asset:   assetID,
+					Hash:    tx.ID,
+					Coin:    coin,
+					Block:   tx.Block,
+					Delta:   amount.String(),
+				})
+			}
+		}
+	}
+	return deltas, nil
+}
+
+// GetBalanceDeltas returns the net effect of the transaction on the balances of the address by asset id,
+// see blockatlas.GetNetAmounts
+func GetBalanceDeltas(tx types.Tx, address string) map[string]*big.Int {
+	tx.Direction = ""
+	tx.Direction = tx.GetTransactionDirection(address)
+	tx.InferUtxoValue(address, tx.Coin)
+	return blockatlas.GetNetAmounts(tx)
+}
+
+func lowestBlock(txs types.Txs) uint64 {
+	lowest := txs[0].Block
+	for _, tx := range txs {
+		if tx.Block < lowest {
+			lowest = tx.Block
+		}
+	}
+	return lowest
+}
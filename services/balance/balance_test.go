@@ -0,0 +1,153 @@
+package balance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/golibs/types"
+)
+
+type storeMock struct {
+	subscriptions []string
+	sequence      uint64
+	deltas        []models.BalanceDelta
+	rewound       []uint64
+	checkpoints   []uint64
+}
+
+func (s *storeMock) GetSubscriptions(addresses []string) ([]models.Subscription, error) {
+	result := make([]models.Subscription, 0)
+	for _, address := range addresses {
+		for _, subscription := range s.subscriptions {
+			if address == subscription {
+				result = append(result, models.Subscription{Address: address})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *storeMock) GetConsumerSequence(string, uint) (uint64, error) {
+	return s.sequence, nil
+}
+
+func (s *storeMock) SetConsumerSequence(_ string, _ uint, sequence uint64) error {
+	if sequence > s.sequence {
+		s.sequence = sequence
+	}
+	return nil
+}
+
+func (s *storeMock) AddBalanceDeltas(deltas []models.BalanceDelta) error {
+	s.deltas = append(s.deltas, deltas...)
+	return nil
+}
+
+func (s *storeMock) RewindBalances(_ uint, fromBlock uint64) error {
+	s.rewound = append(s.rewound, fromBlock)
+	return nil
+}
+
+func (s *storeMock) CheckpointBalances(_ uint, block uint64) error {
+	s.checkpoints = append(s.checkpoints, block)
+	return nil
+}
+
+func TestGetBalanceDeltas(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      types.Tx
+		address string
+		want    map[string]string
+	}{
+		{
+			name:    "incoming transfer",
+			tx:      types.Tx{Coin: 60, From: "0xb", To: "0xa", Fee: "21", Meta: types.Transfer{Value: "1000"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "1000"},
+		},
+		{
+			name:    "outgoing transfer pays the fee",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xb", Fee: "21", Meta: types.Transfer{Value: "1000"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "-1021"},
+		},
+		{
+			name:    "self transfer only pays the fee",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xa", Fee: "21", Meta: types.Transfer{Value: "1000"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "-21"},
+		},
+		{
+			name:    "failed transfer only pays the fee",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xb", Fee: "21", Status: types.StatusError, Meta: types.Transfer{Value: "1000"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "-21"},
+		},
+		{
+			name:    "outgoing token transfer",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xt", Fee: "21", Meta: &types.TokenTransfer{TokenID: "0xt", From: "0xa", To: "0xb", Value: "5"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "-21", "c60_t0xt": "-5"},
+		},
+		{
+			name:    "incoming token transfer",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xt", Fee: "21", Meta: &types.TokenTransfer{TokenID: "0xt", From: "0xa", To: "0xb", Value: "5"}},
+			address: "0xb",
+			want:    map[string]string{"c60_t0xt": "5"},
+		},
+		{
+			name: "utxo change is kept",
+			tx: types.Tx{
+				Coin:    0,
+				Fee:     "10",
+				Inputs:  []types.TxOutput{{Address: "a", Value: "100"}},
+				Outputs: []types.TxOutput{{Address: "b", Value: "60"}, {Address: "a", Value: "30"}},
+			},
+			address: "a",
+			want:    map[string]string{"c0": "-70"},
+		},
+		{
+			name:    "contract call",
+			tx:      types.Tx{Coin: 60, From: "0xa", To: "0xc", Fee: "21", Meta: types.ContractCall{Input: "0x", Value: "0"}},
+			address: "0xa",
+			want:    map[string]string{"c60": "-21"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for asset, amount := range GetBalanceDeltas(tt.tx, tt.address) {
+				got[asset] = amount.String()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConsumer_Apply(t *testing.T) {
+	store := &storeMock{subscriptions: []string{"60_0xa"}}
+	consumer := Consumer{Store: store, CheckpointDepth: 10, Tag: "balances"}
+
+	txs := types.Txs{
+		{ID: "0x1", Coin: 60, From: "0xb", To: "0xa", Block: 20, Meta: types.Transfer{Value: "1000"}},
+		{ID: "0x2", Coin: 60, From: "0xb", To: "0xc", Block: 21, Meta: types.Transfer{Value: "1000"}},
+	}
+	assert.Nil(t, consumer.Apply(context.Background(), txs))
+	assert.Equal(t, []models.BalanceDelta{{Address: "60_0xa", Asset: "c60", Hash: "0x1", Coin: 60, Block: 20, Delta: "1000"}}, store.deltas)
+	assert.Equal(t, uint64(21), store.sequence)
+	assert.Empty(t, store.rewound)
+	assert.Equal(t, []uint64{11}, store.checkpoints)
+
+	// blocks already applied are rewound before being applied again
+	replayed := types.Txs{{ID: "0x3", Coin: 60, From: "0xa", To: "0xb", Fee: "1", Block: 21, Meta: types.Transfer{Value: "10"}}}
+	assert.Nil(t, consumer.Apply(context.Background(), replayed))
+	assert.Equal(t, []uint64{21}, store.rewound)
+	assert.Equal(t, "-11", store.deltas[1].Delta)
+	assert.Equal(t, uint64(21), store.sequence)
+
+	assert.Nil(t, consumer.Apply(context.Background(), types.Txs{}))
+	assert.Len(t, store.deltas, 2)
+}
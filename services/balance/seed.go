@@ -0,0 +1,65 @@
+package balance
+
+import (
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/internal"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/types"
+)
+
+type (
+	// SeedStore keeps the balances read from the providers, see db.Instance
+	SeedStore interface {
+		SeedBalances(balances []models.AddressBalance) error
+	}
+
+	// Seeder seeds the balances of the new subscriptions with their current balances read from the provider,
+	// the observed transactions then apply on top of them from the block they were read at.
+	// Coins without a blockatlas.BalanceAPI only count the transactions observed since the subscription.
+	Seeder struct {
+		Store SeedStore
+		APIs  map[uint]blockatlas.BalanceAPI
+	}
+)
+
+func (s Seeder) Callback(msg amqp.Delivery) error {
+	logger := internal.DeliveryLogger(msg)
+	var event types.SubscriptionEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		logger.WithFields(log.Fields{"service": Balance, "body": string(msg.Body), "error": err}).Error("Unable to unmarshal MQ Message")
+		return nil
+	}
+	if event.Operation != types.AddSubscription {
+		return nil
+	}
+	balances := make([]models.AddressBalance, 0)
+	for _, subscription := range event.ParseSubscriptions(event.Subscriptions) {
+		api, ok := s.APIs[subscription.Coin]
+		if !ok {
+			continue
+		}
+		amounts, block, err := api.GetBalances(subscription.Address)
+		if err != nil {
+			return err
+		}
+		for assetID, amount := range amounts {
+			balances = append(balances, models.AddressBalance{
+				Address:         subscription.AddressID(),
+				Asset:           assetID,
+				Coin:            subscription.Coin,
+				Balance:         string(amount),
+				Checkpoint:      string(amount),
+				CheckpointBlock: uint64(block),
+			})
+		}
+	}
+	if err := s.Store.SeedBalances(balances); err != nil {
+		return err
+	}
+	logger.WithFields(log.Fields{"service": Balance, "balances": len(balances)}).Info("Balances seeded")
+	return nil
+}
@@ -0,0 +1,63 @@
+package balance
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/coin"
+	"github.com/trustwallet/golibs/types"
+)
+
+type seedStoreMock struct {
+	balances []models.AddressBalance
+}
+
+func (s *seedStoreMock) SeedBalances(balances []models.AddressBalance) error {
+	s.balances = append(s.balances, balances...)
+	return nil
+}
+
+type balanceAPIMock struct {
+	balances map[string]types.Amount
+	err      error
+}
+
+func (m balanceAPIMock) Coin() coin.Coin {
+	return coin.Ethereum()
+}
+
+func (m balanceAPIMock) GetBalances(address string) (map[string]types.Amount, int64, error) {
+	return m.balances, 100, m.err
+}
+
+func TestSeeder_Callback(t *testing.T) {
+	delivery := func(operation types.SubscriptionOperation) amqp.Delivery {
+		body, _ := json.Marshal(types.SubscriptionEvent{
+			Subscriptions: types.Subscriptions{"60": {"0xa"}, "0": {"bc1a"}},
+			Operation:     operation,
+		})
+		return amqp.Delivery{Body: body}
+	}
+	api := balanceAPIMock{balances: map[string]types.Amount{"c60": "1000"}}
+
+	store := &seedStoreMock{}
+	seeder := Seeder{Store: store, APIs: map[uint]blockatlas.BalanceAPI{60: api}}
+	assert.Nil(t, seeder.Callback(delivery(types.AddSubscription)))
+	assert.Equal(t, []models.AddressBalance{
+		{Address: "60_0xa", Asset: "c60", Coin: 60, Balance: "1000", Checkpoint: "1000", CheckpointBlock: 100},
+	}, store.balances)
+
+	store = &seedStoreMock{}
+	seeder = Seeder{Store: store, APIs: map[uint]blockatlas.BalanceAPI{60: api}}
+	assert.Nil(t, seeder.Callback(delivery(types.DeleteSubscription)))
+	assert.Empty(t, store.balances)
+
+	seeder = Seeder{Store: store, APIs: map[uint]blockatlas.BalanceAPI{60: balanceAPIMock{err: errors.New("timeout")}}}
+	assert.NotNil(t, seeder.Callback(delivery(types.AddSubscription)))
+	assert.Empty(t, store.balances)
+}
@@ -7,6 +7,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 	"github.com/trustwallet/blockatlas/db"
+	"github.com/trustwallet/blockatlas/internal"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
@@ -15,9 +17,10 @@ const (
 )
 
 func RunNotifier(database *db.Instance, delivery amqp.Delivery) error {
+	ctx := internal.DeliveryContext(delivery)
 	transactions, err := GetTransactionsFromDelivery(delivery, Notifier)
 	if err != nil {
-		log.WithFields(log.Fields{"service": Notifier, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
+		blockatlas.Logger(ctx).WithFields(log.Fields{"service": Notifier, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
 		return nil
 	}
 
@@ -53,9 +56,9 @@ func RunNotifier(database *db.Instance, delivery amqp.Delivery) error {
 		return nil
 	}
 
-	err = publishNotifications(notifications)
+	err = publishNotifications(ctx, notifications)
 	if err != nil {
-		log.WithFields(log.Fields{"service": Notifier}).Error(err)
+		blockatlas.Logger(ctx).WithFields(log.Fields{"service": Notifier}).Error(err)
 	}
 
 	return nil
@@ -1,9 +1,11 @@
 package notifier
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/trustwallet/blockatlas/internal"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 
 	log "github.com/sirupsen/logrus"
@@ -17,22 +19,22 @@ func GetTransactionsFromDelivery(delivery amqp.Delivery, service string) (types.
 		return nil, err
 	}
 
-	log.WithFields(log.Fields{"service": service, "notifications": len(transactions)}).Info("Consumed")
+	internal.DeliveryLogger(delivery).WithFields(log.Fields{"service": service, "notifications": len(transactions)}).Info("Consumed")
 
 	return transactions, nil
 }
 
-func publishNotifications(notifications []types.TransactionNotification) error {
+func publishNotifications(ctx context.Context, notifications []types.TransactionNotification) error {
 	raw, err := json.Marshal(notifications)
 	if err != nil {
 		return err
 	}
-	err = internal.TxNotifications.Publish(raw)
+	err = internal.PublishWithHeaders(internal.TxNotifications, raw, internal.TraceHeaders(ctx))
 	if err != nil {
 		return err
 	}
 
-	log.WithFields(log.Fields{"service": Notifier, "notifications": len(notifications)}).Info("Notifications send")
+	blockatlas.Logger(ctx).WithFields(log.Fields{"service": Notifier, "notifications": len(notifications)}).Info("Notifications send")
 
 	return nil
 }
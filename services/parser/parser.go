@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/trustwallet/blockatlas/db"
+	"github.com/trustwallet/blockatlas/internal"
 	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/network/mq"
 	"github.com/trustwallet/golibs/numbers"
@@ -30,10 +31,14 @@ type (
 	Params struct {
 		Api                                       blockatlas.BlockAPI
 		TransactionsExchange                      mq.Exchange
+		TransactionsQueues                        []mq.Queue
 		ParsingBlocksInterval, FetchBlocksTimeout time.Duration
 		MaxBlocks                                 int64
 		StopChannel                               chan<- struct{}
 		Database                                  *db.Instance
+		TrustedTokens                             blockatlas.TrustedTokens
+		// SubscribedOnly skips the transactions touching no subscribed address
+		SubscribedOnly bool
 	}
 
 	GetBlockByNumber func(num int64) (*types.Block, error)
@@ -43,6 +48,9 @@ type (
 	}
 )
 
+// subscriptionsLookupSize bounds the addresses looked up by query, under the bind parameters limit of Postgres
+const subscriptionsLookupSize = 10000
+
 func RunParser(params Params, ctx context.Context) {
 	log.Info("------------------------------------------------------------")
 	for {
@@ -111,7 +119,10 @@ func parse(params Params) {
 	for _, block := range blocks {
 		txs = append(txs, block.Txs...)
 	}
-	txs = txs.FilterTransactionsByMemo()
+	txs = blockatlas.FilterCoinTxsByMemo(params.Api.Coin(), blockatlas.SanitizeMemos(txs), params.TrustedTokens)
+	if params.SubscribedOnly {
+		txs = filterSubscribed(params, txs)
+	}
 
 	err = publish(params, txs)
 	if err != nil {
@@ -271,6 +282,69 @@ func SaveLastParsedBlock(params Params, blocks []types.Block) error {
 	return nil
 }
 
+// filterSubscribed keeps the transactions touching a subscribed address, all of them are kept when
+// the subscriptions can't be read
+func filterSubscribed(params Params, txs types.Txs) types.Txs {
+	ids := GetAddressIDs(params.Api.Coin().ID, txs)
+	subscribed := make(map[string]bool)
+	for start := 0; start < len(ids); start += subscriptionsLookupSize {
+		end := start + subscriptionsLookupSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		subscriptions, err := params.Database.GetSubscriptions(ids[start:end])
+		if err != nil {
+			log.WithFields(log.Fields{"coin": params.Api.Coin().Handle, "error": err}).Warn("Failed to get subscriptions, publishing all transactions")
+			return txs
+		}
+		for _, subscription := range subscriptions {
+			subscribed[subscription.Address] = true
+		}
+	}
+	return FilterSubscribedTxs(params.Api.Coin().ID, txs, subscribed)
+}
+
+// GetAddressIDs returns the unique subscription ids of the addresses of the transactions, inputs and outputs included
+func GetAddressIDs(coinID uint, txs types.Txs) []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, tx := range txs {
+		for _, id := range txAddressIDs(coinID, tx) {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// FilterSubscribedTxs keeps the transactions with an address in the subscribed set, keyed by subscription id
+func FilterSubscribedTxs(coinID uint, txs types.Txs, subscribed map[string]bool) types.Txs {
+	result := make(types.Txs, 0, len(txs))
+	for _, tx := range txs {
+		for _, id := range txAddressIDs(coinID, tx) {
+			if subscribed[id] {
+				result = append(result, tx)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func txAddressIDs(coinID uint, tx types.Tx) []string {
+	coin := strconv.Itoa(int(coinID))
+	ids := make([]string, 0)
+	for _, address := range append(tx.GetAddresses(), tx.GetUtxoAddresses()...) {
+		if address != "" {
+			ids = append(ids, types.GetAddressID(coin, address))
+		}
+	}
+	return ids
+}
+
+// publish sends the transactions with their sequence headers to the exchange, or to the dedicated queues of the coin if any
 func publish(params Params, transactions types.Txs) error {
 
 	if len(transactions) == 0 {
@@ -282,7 +356,16 @@ func publish(params Params, transactions types.Txs) error {
 		log.WithFields(log.Fields{"operation": "publish marshal", "transactions": transactions, "coin": params.Api.Coin().Handle}).Error(err)
 		return err
 	}
-	return params.TransactionsExchange.Publish(body)
+	headers := internal.SequenceHeaders(transactions)
+	if len(params.TransactionsQueues) == 0 {
+		return internal.PublishToExchangeWithHeaders(params.TransactionsExchange, body, headers)
+	}
+	for _, queue := range params.TransactionsQueues {
+		if err := internal.PublishWithHeaders(queue, body, headers); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func getBlockByNumberWithRetry(attempts int, sleep time.Duration, getBlockByNumber GetBlockByNumber, n int64, symbol string) (*types.Block, error) {
@@ -218,3 +218,20 @@ func TestGetNextBlocksToParse(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterSubscribedTxs(t *testing.T) {
+	transfer := types.Tx{ID: "transfer", From: "addr1", To: "addr2", Meta: types.Transfer{Value: "1"}}
+	token := types.Tx{ID: "token", From: "addr3", To: "contract", Meta: types.TokenTransfer{From: "addr3", To: "addr4", Value: "1"}}
+	utxo := types.Tx{
+		ID:      "utxo",
+		Inputs:  []types.TxOutput{{Address: "addr5"}},
+		Outputs: []types.TxOutput{{Address: "addr6"}, {Address: "addr1"}},
+		Meta:    types.Transfer{Value: "1"},
+	}
+	txs := types.Txs{transfer, token, utxo}
+
+	assert.Equal(t, []string{"0_addr1", "0_addr2", "0_addr3", "0_addr4", "0_addr5", "0_addr6"}, GetAddressIDs(coin.BITCOIN, txs))
+	assert.Equal(t, types.Txs{transfer, utxo}, FilterSubscribedTxs(coin.BITCOIN, txs, map[string]bool{"0_addr1": true}))
+	assert.Equal(t, types.Txs{token}, FilterSubscribedTxs(coin.BITCOIN, txs, map[string]bool{"0_addr4": true}))
+	assert.Empty(t, FilterSubscribedTxs(coin.BITCOIN, txs, map[string]bool{"60_addr1": true}))
+}
@@ -0,0 +1,38 @@
+package spam
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+	"github.com/trustwallet/golibs/client"
+)
+
+// Refresh reloads the spam tokens from the static ids and the JSON array of token ids served at the URL
+func Refresh(list *blockatlas.SpamTokens, static []string, url string) error {
+	var ids []string
+	request := client.InitClient(url, nil)
+	if err := request.Get(&ids, "", nil); err != nil {
+		return err
+	}
+	list.Load(append(ids, static...))
+	return nil
+}
+
+// RunRefresher reloads the spam tokens at every interval until the context is done.
+// The list is kept as is when the URL fails.
+func RunRefresher(ctx context.Context, list *blockatlas.SpamTokens, static []string, url string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := Refresh(list, static, url); err != nil {
+			log.WithFields(log.Fields{"url": url, "error": err}).Error("Failed to refresh spam tokens")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package spam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
+)
+
+func TestRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`["0xSpam"]`))
+	}))
+	defer server.Close()
+
+	list := blockatlas.NewSpamTokens(nil)
+	assert.Nil(t, Refresh(list, []string{"0xStatic"}, server.URL))
+	assert.True(t, list.Contains("0xspam"))
+	assert.True(t, list.Contains("0xstatic"))
+
+	assert.NotNil(t, Refresh(list, nil, "http://127.0.0.1:1"))
+	assert.True(t, list.Contains("0xspam"))
+}
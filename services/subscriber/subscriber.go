@@ -2,32 +2,59 @@ package subscriber
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/trustwallet/blockatlas/internal"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/streadway/amqp"
 	"github.com/trustwallet/blockatlas/db"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/pkg/blockatlas"
 	"github.com/trustwallet/golibs/types"
 )
 
+// subscriptionStore looks up the stored subscriptions by their coin_address ids, see db.Instance
+type subscriptionStore interface {
+	GetSubscriptions(addresses []string) ([]models.Subscription, error)
+}
+
 func RunSubscriber(database *db.Instance, delivery amqp.Delivery) error {
+	ctx := internal.DeliveryContext(delivery)
+	logger := blockatlas.Logger(ctx)
 	var event types.SubscriptionEvent
 	err := json.Unmarshal(delivery.Body, &event)
 	if err != nil {
-		log.WithFields(log.Fields{"service": types.Notifications, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
+		logger.WithFields(log.Fields{"service": types.Notifications, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
 		return nil
 	}
 
 	subscriptions := event.ParseSubscriptions(event.Subscriptions)
 	switch event.Operation {
 	case types.AddSubscription:
-		err := database.CreateSubscriptions(subscriptions)
+		newSubscriptions, err := filterNewSubscriptions(database, subscriptions)
 		if err != nil {
-			log.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": subscriptions}).Error(err)
+			logger.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": subscriptions}).Error(err)
 			return err
 		}
-		log.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": len(subscriptions)}).Info("Add subscriptions")
+		if len(newSubscriptions) == 0 {
+			logger.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": len(subscriptions)}).Info("Subscriptions already exist")
+			return nil
+		}
+		err = database.CreateSubscriptions(newSubscriptions)
+		if err != nil {
+			logger.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": newSubscriptions}).Error(err)
+			return err
+		}
+		logger.WithFields(log.Fields{"service": types.Notifications, "operation": event.Operation, "subscriptions": len(newSubscriptions)}).Info("Add subscriptions")
+
+		// Only the new addresses need their tokens to be indexed
+		body, err := encodeSubscriptions(newSubscriptions, event.Operation)
+		if err != nil {
+			logger.Error(err)
+			return nil
+		}
+		delivery.Body = body
 	case types.DeleteSubscription:
 		subscriptionsIds := make([]string, 0)
 		for _, subscription := range subscriptions {
@@ -37,11 +64,59 @@ func RunSubscriber(database *db.Instance, delivery amqp.Delivery) error {
 	}
 
 	// Pass over subscribed addresses to find all associated tokens to such addresses
-	err = internal.SubscriptionsTokens.Publish(delivery.Body)
+	err = internal.PublishWithHeaders(internal.SubscriptionsTokens, delivery.Body, internal.TraceHeaders(ctx))
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+
+	// and to seed their cached balances
+	err = internal.PublishWithHeaders(internal.SubscriptionsBalances, delivery.Body, internal.TraceHeaders(ctx))
 	if err != nil {
-		log.Error(err)
+		logger.Error(err)
 		return nil
 	}
 
 	return nil
 }
+
+// filterNewSubscriptions returns the subscriptions not stored yet, without duplicates
+func filterNewSubscriptions(store subscriptionStore, subscriptions []types.Subscription) ([]types.Subscription, error) {
+	ids := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		ids = append(ids, subscription.AddressID())
+	}
+	stored, err := store.GetSubscriptions(ids)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(stored))
+	for _, subscription := range stored {
+		seen[subscription.Address] = true
+	}
+	result := make([]types.Subscription, 0)
+	for _, subscription := range subscriptions {
+		if seen[subscription.AddressID()] {
+			continue
+		}
+		seen[subscription.AddressID()] = true
+		result = append(result, subscription)
+	}
+	return result, nil
+}
+
+func encodeSubscriptions(subscriptions []types.Subscription, operation types.SubscriptionOperation) ([]byte, error) {
+	return json.Marshal(types.SubscriptionEvent{
+		Subscriptions: toSubscriptions(subscriptions),
+		Operation:     operation,
+	})
+}
+
+func toSubscriptions(subscriptions []types.Subscription) types.Subscriptions {
+	result := make(types.Subscriptions)
+	for _, subscription := range subscriptions {
+		coin := strconv.Itoa(int(subscription.Coin))
+		result[coin] = append(result[coin], subscription.Address)
+	}
+	return result
+}
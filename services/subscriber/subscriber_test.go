@@ -0,0 +1,62 @@
+package subscriber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/golibs/types"
+)
+
+type subscriptionStoreMock struct {
+	addresses []string
+	calls     int
+	err       error
+}
+
+func (m *subscriptionStoreMock) GetSubscriptions(addresses []string) ([]models.Subscription, error) {
+	m.calls++
+	result := make([]models.Subscription, 0)
+	for _, address := range addresses {
+		for _, stored := range m.addresses {
+			if address == stored {
+				result = append(result, models.Subscription{Address: address})
+			}
+		}
+	}
+	return result, m.err
+}
+
+func TestFilterNewSubscriptions(t *testing.T) {
+	subscriptions := []types.Subscription{
+		{Coin: 60, Address: "0xa"},
+		{Coin: 60, Address: "0xb"},
+		{Coin: 60, Address: "0xb"},
+		{Coin: 0, Address: "bc1a"},
+	}
+
+	store := &subscriptionStoreMock{addresses: []string{"60_0xa"}}
+	result, err := filterNewSubscriptions(store, subscriptions)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Subscription{{Coin: 60, Address: "0xb"}, {Coin: 0, Address: "bc1a"}}, result)
+	assert.Equal(t, 1, store.calls)
+
+	store = &subscriptionStoreMock{addresses: []string{"60_0xa", "60_0xb", "0_bc1a"}}
+	result, err = filterNewSubscriptions(store, subscriptions)
+	assert.Nil(t, err)
+	assert.Empty(t, result)
+
+	_, err = filterNewSubscriptions(&subscriptionStoreMock{err: errors.New("db down")}, subscriptions)
+	assert.NotNil(t, err)
+}
+
+func TestEncodeSubscriptions(t *testing.T) {
+	body, err := encodeSubscriptions([]types.Subscription{
+		{Coin: 60, Address: "0xb"},
+		{Coin: 60, Address: "0xc"},
+		{Coin: 0, Address: "bc1a"},
+	}, types.AddSubscription)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"subscriptions":{"60":["0xb","0xc"],"0":["bc1a"]},"operation":"AddSubscription"}`, string(body))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/streadway/amqp"
 	"github.com/trustwallet/blockatlas/db"
 	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/internal"
 	"github.com/trustwallet/blockatlas/services/notifier"
 	"github.com/trustwallet/golibs/types"
 )
@@ -17,9 +18,10 @@ const (
 )
 
 func RunTokenIndexer(database *db.Instance, delivery amqp.Delivery) error {
+	logger := internal.DeliveryLogger(delivery)
 	transactions, err := notifier.GetTransactionsFromDelivery(delivery, TokenIndexer)
 	if err != nil {
-		log.WithFields(log.Fields{"service": TokenIndexer, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
+		logger.WithFields(log.Fields{"service": TokenIndexer, "body": string(delivery.Body), "error": err}).Error("Unable to unmarshal MQ Message")
 		return nil
 	}
 
@@ -37,7 +39,7 @@ func RunTokenIndexer(database *db.Instance, delivery amqp.Delivery) error {
 	assets := GetAssetsFromTransactions(assetsTxs)
 	err = database.AddNewAssets(assets)
 	if err != nil {
-		log.WithFields(log.Fields{"service": TokenIndexer, "assets": assets}).Error("Failed to add new assets", err)
+		logger.WithFields(log.Fields{"service": TokenIndexer, "assets": assets}).Error("Failed to add new assets", err)
 		return err
 	}
 
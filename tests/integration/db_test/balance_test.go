@@ -0,0 +1,70 @@
+// +build integration
+
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trustwallet/blockatlas/db/models"
+	"github.com/trustwallet/blockatlas/tests/integration/setup"
+)
+
+func TestDb_Balances(t *testing.T) {
+	setup.CleanupPgContainer(database.Gorm)
+
+	balanceOf := func(asset string) string {
+		balances, err := database.GetAddressBalances("60_0xa")
+		assert.Nil(t, err)
+		for _, balance := range balances {
+			if balance.Asset == asset {
+				return balance.Balance
+			}
+		}
+		return ""
+	}
+
+	assert.Nil(t, database.AddBalanceDeltas([]models.BalanceDelta{
+		{Address: "60_0xa", Asset: "c60", Hash: "0x1", Coin: 60, Block: 10, Delta: "1000"},
+		{Address: "60_0xa", Asset: "c60", Hash: "0x2", Coin: 60, Block: 11, Delta: "-300"},
+		{Address: "60_0xa", Asset: "c60_t0xt", Hash: "0x2", Coin: 60, Block: 11, Delta: "5"},
+	}))
+	assert.Equal(t, "700", balanceOf("c60"))
+	assert.Equal(t, "5", balanceOf("c60_t0xt"))
+
+	// a transaction applied again replaces its delta
+	assert.Nil(t, database.AddBalanceDeltas([]models.BalanceDelta{
+		{Address: "60_0xa", Asset: "c60", Hash: "0x2", Coin: 60, Block: 11, Delta: "-300"},
+	}))
+	assert.Equal(t, "700", balanceOf("c60"))
+
+	assert.Nil(t, database.RewindBalances(60, 11))
+	assert.Equal(t, "1000", balanceOf("c60"))
+	assert.Equal(t, "0", balanceOf("c60_t0xt"))
+
+	assert.Nil(t, database.AddBalanceDeltas([]models.BalanceDelta{
+		{Address: "60_0xa", Asset: "c60", Hash: "0x3", Coin: 60, Block: 11, Delta: "-100"},
+	}))
+	assert.Nil(t, database.CheckpointBalances(60, 10))
+	assert.Equal(t, "900", balanceOf("c60"))
+
+	// deltas behind the checkpoint are dropped
+	assert.Nil(t, database.RewindBalances(60, 10))
+	assert.Equal(t, "1000", balanceOf("c60"))
+	assert.Nil(t, database.AddBalanceDeltas([]models.BalanceDelta{
+		{Address: "60_0xa", Asset: "c60", Hash: "0x1", Coin: 60, Block: 10, Delta: "1000"},
+	}))
+	assert.Equal(t, "1000", balanceOf("c60"))
+
+	// seeded balances replace the checkpoint, deltas up to its block are dropped
+	assert.Nil(t, database.AddBalanceDeltas([]models.BalanceDelta{
+		{Address: "60_0xa", Asset: "c60", Hash: "0x4", Coin: 60, Block: 12, Delta: "-50"},
+		{Address: "60_0xa", Asset: "c60", Hash: "0x5", Coin: 60, Block: 14, Delta: "20"},
+	}))
+	assert.Nil(t, database.SeedBalances([]models.AddressBalance{
+		{Address: "60_0xa", Asset: "c60", Coin: 60, Balance: "5000", Checkpoint: "5000", CheckpointBlock: 13},
+		{Address: "60_0xa", Asset: "c60_t0xu", Coin: 60, Balance: "7", Checkpoint: "7", CheckpointBlock: 13},
+	}))
+	assert.Equal(t, "5020", balanceOf("c60"))
+	assert.Equal(t, "7", balanceOf("c60_t0xu"))
+}
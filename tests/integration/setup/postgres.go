@@ -29,6 +29,8 @@ var (
 		&models.Asset{},
 		&models.Subscription{},
 		&models.SubscriptionsAssetAssociation{},
+		&models.AddressBalance{},
+		&models.BalanceDelta{},
 	}
 
 	url string